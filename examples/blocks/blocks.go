@@ -19,7 +19,11 @@ func main() {
 	if err != nil {
 		log.Fatal(err)
 	}
-	fmt.Println(block.GetNumber())
+	number, err := block.GetNumber()
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(number)
 	fmt.Println(block.GetGasLimit())
 	fmt.Println(block.GetGasUsed())
 	fmt.Println(block.GetDifficulty().GetInt64())
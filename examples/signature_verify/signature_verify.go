@@ -32,7 +32,7 @@ func main() {
 	fmt.Println(web3go.Encode(signature))
 
 	// 5. recover public key in []byte type from signature
-	sigPublicKey, err := web3go.Ecrecover(hash.GetBytes(), signature)
+	sigPublicKey, err := web3go.Ecrecover(hash, signature)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -42,7 +42,7 @@ func main() {
 	fmt.Println(matches)
 
 	// 7. recover public key in ECDSA type from signature
-	sigPublicKeyECDSA, err := web3go.SigToPub(hash.GetBytes(), signature)
+	sigPublicKeyECDSA, err := web3go.SigToPub(hash, signature)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -54,6 +54,6 @@ func main() {
 
 	// 9. check if signature is valid
 	signatureNoRecoverID := signature[:len(signature)-1] // remove recovery id
-	verified := web3go.VerifySignature(publicKeyBytes, hash.GetBytes(), signatureNoRecoverID)
+	verified := web3go.VerifySignature(publicKeyBytes, hash, signatureNoRecoverID)
 	fmt.Println(verified)
 }
@@ -33,7 +33,11 @@ func main() {
 	}
 
 	// get header of given block number
-	header, err := client.GetHeaderByNumber(web3go.NewContext(), block.GetNumber())
+	number, err := block.GetNumber()
+	if err != nil {
+		log.Fatal(err)
+	}
+	header, err := client.GetHeaderByNumber(web3go.NewContext(), number)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -46,7 +50,7 @@ func main() {
 
 	// check if header matches header2
 	if headersAreEqual(header, header2) {
-		fmt.Println(header.GetNumber(), header2.GetNumber())
+		fmt.Println(header.GetNumberBig(), header2.GetNumberBig())
 		fmt.Printf("%#v %#v\n", header, header2)
 		log.Fatal("wrong result, headers do not match")
 	}
@@ -146,7 +150,7 @@ func transactionsAreEqual(txs1, txs2 *web3go.Transactions) bool {
 }
 
 func headersAreEqual(h1, h2 *web3go.Header) bool {
-	if h1.GetNumber() != h2.GetNumber() {
+	if h1.GetNumberBig().String() != h2.GetNumberBig().String() {
 		return false
 	}
 
@@ -0,0 +1,79 @@
+package web3go
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	// revertSelector is the selector of Error(string), emitted by require and
+	// revert with a message.
+	revertSelector = crypto.Keccak256([]byte("Error(string)"))[:4]
+
+	// panicSelector is the selector of Panic(uint256), emitted by failing
+	// assertions and checked arithmetic.
+	panicSelector = crypto.Keccak256([]byte("Panic(uint256)"))[:4]
+)
+
+// DecodeRevertReason decodes the data returned by a reverted call into a readable
+// reason. Error(string) reverts yield their message and Panic(uint256) reverts a
+// description of the panic code, e.g. "arithmetic underflow or overflow". Empty
+// data and data of any other shape, like custom errors, are reported as errors,
+// the latter including the raw data in hex.
+func DecodeRevertReason(data []byte) (_ string, err error) {
+	defer recoverError(&err)
+	if len(data) == 0 {
+		return "", errors.New("no revert data")
+	}
+	if len(data) < 4 || (!bytes.Equal(data[:4], revertSelector) && !bytes.Equal(data[:4], panicSelector)) {
+		return "", fmt.Errorf("unrecognized revert data %#x", data)
+	}
+	reason, err := abi.UnpackRevert(data)
+	if err != nil {
+		return "", fmt.Errorf("malformed revert data %#x: %v", data, err)
+	}
+	return reason, nil
+}
+
+// revertError is an execution error of the node annotated with the decoded revert
+// reason. It keeps the original revert data available through rpc.DataError.
+type revertError struct {
+	err    error
+	reason string
+	data   interface{}
+}
+
+func (e *revertError) Error() string          { return e.err.Error() + ": " + e.reason }
+func (e *revertError) ErrorData() interface{} { return e.data }
+
+// withRevertReason annotates a call error carrying revert data with its decoded
+// reason, or the raw hex data if it cannot be decoded. Errors already containing
+// the reason, as produced by go-ethereum for Error(string), are left untouched.
+func withRevertReason(err error) error {
+	dataErr, ok := err.(rpc.DataError)
+	if !ok {
+		return err
+	}
+	hex, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return err
+	}
+	data, decErr := decodeHex(hex)
+	if decErr != nil || len(data) == 0 {
+		return err
+	}
+	reason, decErr := DecodeRevertReason(data)
+	if decErr != nil {
+		reason = decErr.Error()
+	}
+	if strings.Contains(err.Error(), reason) {
+		return err
+	}
+	return &revertError{err: err, reason: reason, data: dataErr.ErrorData()}
+}
@@ -0,0 +1,84 @@
+package web3go
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// testRevertString is the revert data of require(false, "insufficient balance").
+const testRevertString = "0x08c379a000000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000014696e73756666696369656e742062616c616e6365000000000000000000000000"
+
+// testRevertPanic is the revert data of an overflowing checked addition.
+const testRevertPanic = "0x4e487b710000000000000000000000000000000000000000000000000000000000000011"
+
+func TestDecodeRevertReason(t *testing.T) {
+	tests := []struct {
+		data   string
+		reason string
+		err    string
+	}{
+		{testRevertString, "insufficient balance", ""},
+		{testRevertPanic, "arithmetic underflow or overflow", ""},
+		{"0x4e487b710000000000000000000000000000000000000000000000000000000000000099", "unknown panic code: 0x99", ""},
+		{"0xe450d38c000000000000000000000000000000000000000000000000000000000000002a", "", "unrecognized revert data 0xe450d38c"},
+		{"0x08c379a0", "", "malformed revert data"},
+		{"0x", "", "no revert data"},
+	}
+	for _, tt := range tests {
+		reason, err := DecodeRevertReason(hexutil.MustDecode(tt.data))
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%s: error mismatch: have %v, want %q", tt.data, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.data, err)
+			continue
+		}
+		if reason != tt.reason {
+			t.Errorf("%s: reason mismatch: have %q, want %q", tt.data, reason, tt.reason)
+		}
+	}
+}
+
+// testDataError mimics the JSON-RPC error returned by a node for a reverted call.
+type testDataError struct {
+	msg  string
+	data interface{}
+}
+
+func (e *testDataError) Error() string          { return e.msg }
+func (e *testDataError) ErrorCode() int         { return 3 }
+func (e *testDataError) ErrorData() interface{} { return e.data }
+
+func TestWithRevertReason(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{&testDataError{"execution reverted", testRevertPanic}, "execution reverted: arithmetic underflow or overflow"},
+		{&testDataError{"execution reverted: insufficient balance", testRevertString}, "execution reverted: insufficient balance"},
+		{&testDataError{"execution reverted", "0xe450d38c"}, "execution reverted: unrecognized revert data 0xe450d38c"},
+		{&testDataError{"execution reverted", nil}, "execution reverted"},
+		{errors.New("connection refused"), "connection refused"},
+	}
+	for _, tt := range tests {
+		err := withRevertReason(tt.err)
+		if err.Error() != tt.want {
+			t.Errorf("error mismatch: have %q, want %q", err, tt.want)
+		}
+		if dataErr, ok := tt.err.(rpc.DataError); ok {
+			if kept, ok := err.(rpc.DataError); !ok || kept.ErrorData() != dataErr.ErrorData() {
+				t.Errorf("%q: revert data lost", tt.want)
+			}
+		}
+	}
+	if withRevertReason(nil) != nil {
+		t.Error("nil error annotated")
+	}
+}
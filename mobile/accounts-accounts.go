@@ -0,0 +1,53 @@
+package web3go
+
+import (
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// TextHash returns the EIP-191 hash of a personal message, i.e. the keccak256 of
+// "\x19Ethereum Signed Message:\n" + len(message) + message, as used by
+// personal_sign.
+func TextHash(message []byte) *Hash {
+	return &Hash{hash: common.BytesToHash(accounts.TextHash(message))}
+}
+
+// SignPersonalMessage signs a message the way personal_sign does, returning the
+// 65 byte [R || S || V] signature with V being 27 or 28.
+func SignPersonalMessage(message []byte, privKeyHex string) (_ []byte, err error) {
+	defer recoverError(&err)
+	key, err := parsePrivateKeyHex(privKeyHex)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroKey(key)
+
+	sig, err := crypto.Sign(accounts.TextHash(message), key)
+	if err != nil {
+		return nil, err
+	}
+	sig[crypto.RecoveryIDOffset] += 27
+	return sig, nil
+}
+
+// RecoverPersonalSigner returns the address that signed a personal message. The
+// recovery ID of the signature may be given either as 27/28 or as 0/1.
+func RecoverPersonalSigner(message []byte, sig []byte) (_ *Address, err error) {
+	defer recoverError(&err)
+	return recoverSigner(accounts.TextHash(message), sig)
+}
+
+// recoverSigner returns the address that produced the 65 byte signature of the
+// hash, accepting a recovery ID of either 27/28 or 0/1.
+func recoverSigner(hash []byte, sig []byte) (*Address, error) {
+	normalized, err := normalizeSignature(sig)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := crypto.SigToPub(hash, normalized)
+	if err != nil {
+		return nil, err
+	}
+	return &Address{address: crypto.PubkeyToAddress(*pub)}, nil
+}
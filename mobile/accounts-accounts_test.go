@@ -0,0 +1,58 @@
+package web3go
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Signatures are deterministic (RFC 6979), so this is the same signature any
+// personal_sign implementation (e.g. MetaMask) produces for the message and key.
+var (
+	testPersonalMessage = []byte("Hello, web3go!")
+	testPersonalSig     = hexutil.MustDecode("0x3760673f04e9608b88b230392dc2d7211ef149542e918daecdebbaaa0e231b0b55813422a2fac68f5bd454bbe8709c93a369d9b238b4a16f30ab24063b1e70591b")
+)
+
+func TestTextHash(t *testing.T) {
+	// hashMessage("Hello World") as documented by ethers.js
+	want := "0xa1de988600a42c4b4ab089b619297c17d53cffae5d5120d82d8a92d0bb3b78f2"
+	if have := TextHash([]byte("Hello World")).GetHex(); have != want {
+		t.Errorf("hash mismatch: have %s, want %s", have, want)
+	}
+}
+
+func TestSignPersonalMessage(t *testing.T) {
+	sig, err := SignPersonalMessage(testPersonalMessage, "0x"+testKeyHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(sig, testPersonalSig) {
+		t.Fatalf("signature mismatch: have %x, want %x", sig, testPersonalSig)
+	}
+	if _, err := SignPersonalMessage(testPersonalMessage, "0x1234"); err == nil {
+		t.Error("expected error for invalid key")
+	}
+}
+
+func TestRecoverPersonalSigner(t *testing.T) {
+	lowV := common.CopyBytes(testPersonalSig)
+	lowV[64] -= 27
+
+	for _, sig := range [][]byte{testPersonalSig, lowV} {
+		addr, err := RecoverPersonalSigner(testPersonalMessage, sig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if addr.address != testAddress.address {
+			t.Errorf("signer mismatch: have %x, want %x", addr.address, testAddress.address)
+		}
+	}
+	if _, err := RecoverPersonalSigner(testPersonalMessage, testPersonalSig[:64]); err == nil {
+		t.Error("expected error for short signature")
+	}
+	if addr, err := RecoverPersonalSigner([]byte("tampered"), testPersonalSig); err == nil && addr.address == testAddress.address {
+		t.Error("tampered message recovered the original signer")
+	}
+}
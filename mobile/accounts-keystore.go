@@ -0,0 +1,208 @@
+package web3go
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+)
+
+// corruptedKeyError is returned when a JSON key file is malformed, as opposed to
+// failing to decrypt with the given passphrase.
+type corruptedKeyError struct{ err error }
+
+func (e *corruptedKeyError) Error() string { return "corrupted key file: " + e.err.Error() }
+
+// IsCorruptedKey reports whether the error was caused by a malformed JSON key
+// file, e.g. invalid JSON, unsupported parameters, a truncated MAC or an address
+// not matching the key.
+func IsCorruptedKey(err error) bool {
+	_, ok := err.(*corruptedKeyError)
+	return ok
+}
+
+// EncryptKey encrypts the hex encoded private key with the passphrase into a
+// version 3 Web3 Secret Storage JSON key file with a random UUID, in memory and
+// without a keystore directory. Use the standard or light scrypt presets.
+func EncryptKey(privKeyHex string, passphrase string, scryptN, scryptP int) (_ []byte, err error) {
+	defer recoverError(&err)
+	key, err := parsePrivateKeyHex(privKeyHex)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroKey(key)
+
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+	blob, err := keystore.EncryptKey(&keystore.Key{Id: id, Address: crypto.PubkeyToAddress(key.PublicKey), PrivateKey: key}, passphrase, scryptN, scryptP)
+	if err != nil {
+		return nil, err
+	}
+	var file map[string]json.RawMessage
+	if err := json.Unmarshal(blob, &file); err != nil {
+		return nil, err
+	}
+	checksum, err := keyCryptoChecksum(file["crypto"])
+	if err != nil {
+		return nil, err
+	}
+	file[keyChecksumField], _ = json.Marshal(checksum)
+	return json.Marshal(file)
+}
+
+// keyChecksumField is the key file field holding the checksum of its crypto
+// section, which other Web3 Secret Storage implementations ignore.
+const keyChecksumField = "cryptoChecksum"
+
+// keyCryptoChecksum returns the hex encoded Keccak256 hash of the canonical JSON
+// encoding of the crypto section of a key file. It detects a corrupted MAC,
+// ciphertext or parameter without the passphrase.
+func keyCryptoChecksum(section json.RawMessage) (string, error) {
+	var fields struct {
+		Cipher       string                 `json:"cipher"`
+		CipherText   string                 `json:"ciphertext"`
+		CipherParams map[string]interface{} `json:"cipherparams"`
+		KDF          string                 `json:"kdf"`
+		KDFParams    map[string]interface{} `json:"kdfparams"`
+		MAC          string                 `json:"mac"`
+	}
+	if err := json.Unmarshal(section, &fields); err != nil {
+		return "", err
+	}
+	canonical, err := json.Marshal(fields)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(crypto.Keccak256(canonical)), nil
+}
+
+// DecryptKey decrypts a Web3 Secret Storage JSON key file with the passphrase,
+// returning the hex encoded private key. Both the scrypt and the pbkdf2 key
+// derivation functions are supported, the latter being used by key files of
+// MyEtherWallet and some exchanges.
+//
+// Key files written by EncryptKey carry a checksum of their crypto section, so a
+// corrupted MAC, ciphertext or parameter fails with an error IsCorruptedKey
+// reports, while a MAC mismatch of an intact file fails with an error
+// IsIncorrectPassphrase reports. Files of other tools lack the checksum: for
+// those a damaged MAC or ciphertext cannot be told apart from a wrong
+// passphrase, only structural damage is reported as corruption.
+func DecryptKey(keyJSON []byte, passphrase string) (_ string, err error) {
+	defer recoverError(&err)
+	address, err := checkKeyJSON(keyJSON)
+	if err != nil {
+		return "", err
+	}
+	key, err := keystore.DecryptKey(keyJSON, passphrase)
+	if err == keystore.ErrDecrypt {
+		return "", errIncorrectPassphrase
+	}
+	if err != nil {
+		return "", &corruptedKeyError{err}
+	}
+	defer zeroKey(key.PrivateKey)
+
+	if address != "" && common.HexToAddress(address) != key.Address {
+		return "", &corruptedKeyError{fmt.Errorf("address %s does not match key of %s", address, key.Address.Hex())}
+	}
+	return hex.EncodeToString(crypto.FromECDSA(key.PrivateKey)), nil
+}
+
+const (
+	// minPBKDF2Iterations and maxPBKDF2Iterations bound the accepted pbkdf2
+	// iteration count, the upper bound being 38 times the one of the
+	// specification test vector.
+	minPBKDF2Iterations = 1000
+	maxPBKDF2Iterations = 10000000
+
+	// maxScryptParallelism is the highest accepted scrypt p, which multiplies
+	// the computation time independently of the memory usage.
+	maxScryptParallelism = 16
+
+	// maxScryptMemory is the highest accepted scrypt memory usage of 128·n·r
+	// bytes, four times the standard parameters.
+	maxScryptMemory = 1 << 30
+)
+
+// checkKeyJSON validates the structure and key derivation parameters of a
+// version 3 JSON key file before decrypting it, returning its address field.
+// Other versions are left to the keystore to validate.
+func checkKeyJSON(keyJSON []byte) (string, error) {
+	var file struct {
+		Address string `json:"address"`
+		Crypto  struct {
+			Cipher    string                 `json:"cipher"`
+			KDF       string                 `json:"kdf"`
+			KDFParams map[string]interface{} `json:"kdfparams"`
+			MAC       string                 `json:"mac"`
+		} `json:"crypto"`
+		Version interface{} `json:"version"`
+	}
+	if err := json.Unmarshal(keyJSON, &file); err != nil {
+		return "", &corruptedKeyError{err}
+	}
+	if version, ok := file.Version.(float64); !ok || version != 3 {
+		return file.Address, nil
+	}
+	var sealed map[string]json.RawMessage
+	if err := json.Unmarshal(keyJSON, &sealed); err != nil {
+		return "", &corruptedKeyError{err}
+	}
+	if raw, ok := sealed[keyChecksumField]; ok {
+		var want string
+		if err := json.Unmarshal(raw, &want); err != nil {
+			return "", &corruptedKeyError{fmt.Errorf("invalid crypto checksum: %v", err)}
+		}
+		have, err := keyCryptoChecksum(sealed["crypto"])
+		if err != nil {
+			return "", &corruptedKeyError{err}
+		}
+		if have != want {
+			return "", &corruptedKeyError{errors.New("crypto checksum mismatch")}
+		}
+	}
+	if mac, err := hex.DecodeString(file.Crypto.MAC); err != nil || len(mac) != 32 {
+		return "", &corruptedKeyError{fmt.Errorf("invalid MAC %q", file.Crypto.MAC)}
+	}
+	if file.Crypto.Cipher != "aes-128-ctr" {
+		return "", fmt.Errorf("unsupported cipher: %s", file.Crypto.Cipher)
+	}
+	params := file.Crypto.KDFParams
+	param := func(name string) int64 {
+		value, ok := params[name].(float64)
+		if !ok || value != float64(int64(value)) {
+			return -1
+		}
+		return int64(value)
+	}
+	if salt, ok := params["salt"].(string); !ok || salt == "" {
+		return "", &corruptedKeyError{errors.New("missing kdf salt")}
+	}
+	if dklen := param("dklen"); dklen != 32 {
+		return "", &corruptedKeyError{fmt.Errorf("invalid kdf dklen %d, want 32", dklen)}
+	}
+	switch file.Crypto.KDF {
+	case "scrypt":
+		n, r, p := param("n"), param("r"), param("p")
+		if n < 2 || n&(n-1) != 0 || r < 1 || p < 1 || p > maxScryptParallelism || n > maxScryptMemory/128/r {
+			return "", &corruptedKeyError{fmt.Errorf("invalid scrypt parameters n=%d, r=%d, p=%d", n, r, p)}
+		}
+	case "pbkdf2":
+		if prf := params["prf"]; prf != "hmac-sha256" {
+			return "", fmt.Errorf("unsupported pbkdf2 prf: %v", prf)
+		}
+		if c := param("c"); c < minPBKDF2Iterations || c > maxPBKDF2Iterations {
+			return "", &corruptedKeyError{fmt.Errorf("invalid pbkdf2 iteration count %d, want %d to %d", c, minPBKDF2Iterations, maxPBKDF2Iterations)}
+		}
+	default:
+		return "", fmt.Errorf("unsupported kdf: %s", file.Crypto.KDF)
+	}
+	return file.Address, nil
+}
@@ -0,0 +1,154 @@
+package web3go
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestEncryptDecryptKey(t *testing.T) {
+	blob, err := EncryptKey("0x"+testKeyHex, "foo", LightScryptN, LightScryptP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var file struct {
+		Address string `json:"address"`
+		ID      string `json:"id"`
+		Version int    `json:"version"`
+	}
+	if err := json.Unmarshal(blob, &file); err != nil {
+		t.Fatal(err)
+	}
+	if file.Version != 3 || "0x"+file.Address != strings.ToLower(testAddress.GetHex()) {
+		t.Errorf("key file mismatch: %s", blob)
+	}
+	if id, err := uuid.Parse(file.ID); err != nil || id.Version() != 4 {
+		t.Errorf("key file id not a random UUID: %s", file.ID)
+	}
+	if other, _ := EncryptKey(testKeyHex, "foo", LightScryptN, LightScryptP); strings.Contains(string(other), file.ID) {
+		t.Error("key file id reused")
+	}
+	key, err := DecryptKey(blob, "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key != testKeyHex {
+		t.Errorf("decrypted key mismatch: have %s, want %s", key, testKeyHex)
+	}
+	if _, err := DecryptKey(blob, "bar"); !IsIncorrectPassphrase(err) {
+		t.Errorf("wrong passphrase error mismatch: %v", err)
+	}
+	// The encrypted key must be importable by the keystore
+	ks := NewKeyStore(t.TempDir(), LightScryptN, LightScryptP)
+	account, err := ks.ImportKeyJSON(blob, "foo", "bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !account.GetAddress().Equals(testAddress) {
+		t.Errorf("imported address mismatch: have %s", account.GetAddress().GetHex())
+	}
+	// Structural damage must be reported apart from a wrong passphrase
+	corrupt := func(edit func(map[string]interface{})) []byte {
+		var copied map[string]interface{}
+		json.Unmarshal(blob, &copied)
+		edit(copied)
+		data, _ := json.Marshal(copied)
+		return data
+	}
+	for name, data := range map[string][]byte{
+		"json":      blob[:len(blob)/2],
+		"short mac": corrupt(func(m map[string]interface{}) { m["crypto"].(map[string]interface{})["mac"] = "abcd" }),
+		"bad iv": corrupt(func(m map[string]interface{}) {
+			m["crypto"].(map[string]interface{})["cipherparams"] = map[string]string{"iv": "zz"}
+		}),
+		"address": corrupt(func(m map[string]interface{}) { m["address"] = strings.Repeat("11", 20) }),
+		"mac": corrupt(func(m map[string]interface{}) {
+			m["crypto"].(map[string]interface{})["mac"] = strings.Repeat("ab", 32)
+		}),
+		"ciphertext": corrupt(func(m map[string]interface{}) {
+			m["crypto"].(map[string]interface{})["ciphertext"] = strings.Repeat("00", 32)
+		}),
+		"scrypt n": corrupt(func(m map[string]interface{}) {
+			m["crypto"].(map[string]interface{})["kdfparams"].(map[string]interface{})["n"] = 2 * LightScryptN
+		}),
+		"checksum": corrupt(func(m map[string]interface{}) { m["cryptoChecksum"] = strings.Repeat("00", 32) }),
+	} {
+		if _, err := DecryptKey(data, "foo"); !IsCorruptedKey(err) {
+			t.Errorf("%s: corruption error mismatch: %v", name, err)
+		}
+	}
+	// Reformatting the file, e.g. by a backup service, keeps the checksum valid
+	reformatted := corrupt(func(m map[string]interface{}) {})
+	if key, err := DecryptKey(reformatted, "foo"); err != nil || key != testKeyHex {
+		t.Errorf("reformatted key file rejected: %v", err)
+	}
+	if _, err := DecryptKey(reformatted, "bar"); !IsIncorrectPassphrase(err) {
+		t.Errorf("reformatted wrong passphrase error mismatch: %v", err)
+	}
+	if _, err := EncryptKey("0x1234", "foo", LightScryptN, LightScryptP); err == nil {
+		t.Error("expected error for invalid key")
+	}
+}
+
+// testPBKDF2KeyJSON is a version 3 key file using pbkdf2 as MyEtherWallet does,
+// the pbkdf2 test vector of the specification. It holds the same key as
+// testKeyJSON, encrypted with the same passphrase.
+const testPBKDF2KeyJSON = `{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"6087dab2f9fdbbfaddc31a909735c1e6"},"ciphertext":"5318b4d5bcd28de64ee5559e671353e16f075ecae9f99c7a79a38af5f869aa46","kdf":"pbkdf2","kdfparams":{"c":262144,"dklen":32,"prf":"hmac-sha256","salt":"ae3cd4e7013836a3df6bd7241b12db061dbe2c6785853cce422d148a624ce0bd"},"mac":"517ead924a9d0dc3124507e3393d175ce3ff7c1e96529c6c555ce9e51205e9b2"},"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`
+
+func TestDecryptKeyKDFs(t *testing.T) {
+	for name, blob := range map[string]string{"scrypt": testKeyJSON, "pbkdf2": testPBKDF2KeyJSON} {
+		key, err := DecryptKey([]byte(blob), testKeyJSONPass)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if key != testKeyJSONSecret {
+			t.Errorf("%s: key mismatch: have %s, want %s", name, key, testKeyJSONSecret)
+		}
+		if _, err := DecryptKey([]byte(blob), "wrong"); !IsIncorrectPassphrase(err) {
+			t.Errorf("%s: wrong passphrase error mismatch: %v", name, err)
+		}
+	}
+	ks := NewKeyStore(t.TempDir(), LightScryptN, LightScryptP)
+	if _, err := ks.ImportKeyJSON([]byte(testPBKDF2KeyJSON), testKeyJSONPass, "foo"); err != nil {
+		t.Fatalf("pbkdf2 import failed: %v", err)
+	}
+	edit := func(blob string, kdf string, params map[string]interface{}) []byte {
+		var file map[string]interface{}
+		json.Unmarshal([]byte(blob), &file)
+		crypto := file["crypto"].(map[string]interface{})
+		if kdf != "" {
+			crypto["kdf"] = kdf
+		}
+		for name, value := range params {
+			crypto["kdfparams"].(map[string]interface{})[name] = value
+		}
+		data, _ := json.Marshal(file)
+		return data
+	}
+	corrupted := map[string][]byte{
+		"pbkdf2 iterations":     edit(testPBKDF2KeyJSON, "", map[string]interface{}{"c": 1}),
+		"pbkdf2 iterations max": edit(testPBKDF2KeyJSON, "", map[string]interface{}{"c": 1e12}),
+		"pbkdf2 dklen":          edit(testPBKDF2KeyJSON, "", map[string]interface{}{"dklen": 16}),
+		"scrypt dklen":          edit(testKeyJSON, "", map[string]interface{}{"dklen": 64}),
+		"scrypt n":              edit(testKeyJSON, "", map[string]interface{}{"n": 1000}),
+		"scrypt memory":         edit(testKeyJSON, "", map[string]interface{}{"n": 1 << 30}),
+		"scrypt p":              edit(testKeyJSON, "", map[string]interface{}{"p": 0}),
+		"scrypt p max":          edit(testKeyJSON, "", map[string]interface{}{"p": 1 << 29}),
+	}
+	for name, blob := range corrupted {
+		if _, err := DecryptKey(blob, testKeyJSONPass); !IsCorruptedKey(err) {
+			t.Errorf("%s: corruption error mismatch: %v", name, err)
+		}
+		if _, err := ks.ImportKeyJSON(blob, testKeyJSONPass, "foo"); !IsCorruptedKey(err) {
+			t.Errorf("%s: import corruption error mismatch: %v", name, err)
+		}
+	}
+	if _, err := DecryptKey(edit(testKeyJSON, "argon2", nil), testKeyJSONPass); err == nil || err.Error() != "unsupported kdf: argon2" {
+		t.Errorf("unsupported kdf error mismatch: %v", err)
+	}
+	if _, err := DecryptKey(edit(testPBKDF2KeyJSON, "", map[string]interface{}{"prf": "hmac-sha512"}), testKeyJSONPass); err == nil {
+		t.Error("expected error for unsupported prf")
+	}
+}
@@ -21,12 +21,15 @@ package web3go
 
 import (
 	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
 )
 
 const (
@@ -53,6 +56,11 @@ type Account struct{ account accounts.Account }
 // Accounts represents a slice of accounts.
 type Accounts struct{ accounts []accounts.Account }
 
+// NewAccountsEmpty creates an empty slice of accounts.
+func NewAccountsEmpty() *Accounts {
+	return &Accounts{}
+}
+
 // Size returns the number of accounts in the slice.
 func (a *Accounts) Size() int {
 	return len(a.accounts)
@@ -75,22 +83,67 @@ func (a *Accounts) Set(index int, account *Account) error {
 	return nil
 }
 
+// Append adds a new account to the end of the slice.
+func (a *Accounts) Append(account *Account) error {
+	if account == nil {
+		return errors.New("nil account")
+	}
+	a.accounts = append(a.accounts, account.account)
+	return nil
+}
+
+// Find returns the first account with the given address.
+func (a *Accounts) Find(address *Address) (*Account, error) {
+	if address != nil {
+		for _, account := range a.accounts {
+			if account.Address == address.address {
+				return &Account{account}, nil
+			}
+		}
+	}
+	return nil, errors.New("account not found")
+}
+
+// Contains reports whether the slice contains an account with the given address.
+func (a *Accounts) Contains(address *Address) bool {
+	_, err := a.Find(address)
+	return err == nil
+}
+
 // GetAddress retrieves the address associated with the account.
 func (a *Account) GetAddress() *Address {
 	return &Address{a.account.Address}
 }
 
-// GetURL retrieves the canonical URL of the account.
+// GetURL retrieves the canonical URL of the account, e.g. the keystore://
+// path of its key file. Accounts derived from a mnemonic carry their derivation
+// path instead.
 func (a *Account) GetURL() string {
 	return a.account.URL.String()
 }
 
+// Equals reports whether both accounts have the same address, regardless of
+// where their keys are stored. Two nil accounts are equal.
+func (a *Account) Equals(other *Account) bool {
+	if a == nil || other == nil {
+		return a == other
+	}
+	return a.account.Address == other.account.Address
+}
+
 // KeyStore manages a key storage directory on disk.
-type KeyStore struct{ keystore *keystore.KeyStore }
+type KeyStore struct {
+	keystore *keystore.KeyStore
+	scryptN  int
+	scryptP  int
+}
 
-// NewKeyStore creates a keystore for the given directory.
+// NewKeyStore creates a keystore for the given directory, creating it on demand.
+// Keys are stored encrypted with the given scrypt parameters, one file per key
+// named UTC--<created>--<address>, following the Web3 Secret Storage definition
+// used by geth. Use the standard or light scrypt presets above.
 func NewKeyStore(keydir string, scryptN, scryptP int) *KeyStore {
-	return &KeyStore{keystore: keystore.NewKeyStore(keydir, scryptN, scryptP)}
+	return &KeyStore{keystore: keystore.NewKeyStore(keydir, scryptN, scryptP), scryptN: scryptN, scryptP: scryptP}
 }
 
 // HasAddress reports whether a key with the given address is present.
@@ -103,74 +156,129 @@ func (ks *KeyStore) GetAccounts() *Accounts {
 	return &Accounts{ks.keystore.Accounts()}
 }
 
-// DeleteAccount deletes the key matched by account if the passphrase is correct.
-// If a contains no filename, the address must match a unique key.
-func (ks *KeyStore) DeleteAccount(account *Account, passphrase string) error {
-	return ks.keystore.Delete(account.account, passphrase)
+// DeleteAccount deletes the key matched by account if the passphrase is correct,
+// also locking the account if it was unlocked. If account contains no filename,
+// the address must match a unique key.
+func (ks *KeyStore) DeleteAccount(account *Account, passphrase string) (err error) {
+	defer recoverError(&err)
+	if err := ks.keystore.Delete(account.account, passphrase); err != nil {
+		return keystoreError(err)
+	}
+	return ks.keystore.Lock(account.account.Address)
 }
 
-// SignHash calculates a ECDSA signature for the given hash. The produced signature
-// is in the [R || S || V] format where V is 0 or 1.
-func (ks *KeyStore) SignHash(address *Address, hash []byte) (signature []byte, _ error) {
-	return ks.keystore.SignHash(accounts.Account{Address: address.address}, common.CopyBytes(hash))
+// SignHash calculates a ECDSA signature for the given hash with the key of an
+// unlocked account. The produced signature is in the [R || S || V] format where
+// V is 0 or 1. Locked accounts fail with an error IsAccountLocked reports.
+func (ks *KeyStore) SignHash(address *Address, hash *Hash) (signature []byte, err error) {
+	defer recoverError(&err)
+	signature, err = ks.keystore.SignHash(accounts.Account{Address: address.address}, hash.hash[:])
+	return signature, keystoreError(err)
 }
 
-// SignTx signs the given transaction with the requested account.
-func (ks *KeyStore) SignTx(account *Account, tx *Transaction, chainID *BigInt) (*Transaction, error) {
-	if chainID == nil { // Null passed from mobile app
-		chainID = new(BigInt)
-	}
-	signed, err := ks.keystore.SignTx(account.account, tx.tx, chainID.bigint)
-	if err != nil {
-		return nil, err
-	}
-	return &Transaction{signed}, nil
+// SignTx signs the given transaction with the requested unlocked account, using
+// the signer matching the transaction type and chain ID. The chain ID may only
+// be nil for legacy transactions without replay protection.
+func (ks *KeyStore) SignTx(account *Account, tx *Transaction, chainID *BigInt) (_ *Transaction, err error) {
+	defer recoverError(&err)
+	return signKeyStoreTx(tx, chainID, func(hash []byte) ([]byte, error) {
+		return ks.keystore.SignHash(account.account, hash)
+	})
 }
 
 // SignHashPassphrase signs hash if the private key matching the given address can
 // be decrypted with the given passphrase. The produced signature is in the
-// [R || S || V] format where V is 0 or 1.
-func (ks *KeyStore) SignHashPassphrase(account *Account, passphrase string, hash []byte) (signature []byte, _ error) {
-	return ks.keystore.SignHashWithPassphrase(account.account, passphrase, common.CopyBytes(hash))
+// [R || S || V] format where V is 0 or 1. A wrong passphrase fails with an error
+// IsIncorrectPassphrase reports.
+func (ks *KeyStore) SignHashPassphrase(account *Account, passphrase string, hash *Hash) (signature []byte, err error) {
+	defer recoverError(&err)
+	signature, err = ks.keystore.SignHashWithPassphrase(account.account, passphrase, hash.hash[:])
+	return signature, keystoreError(err)
 }
 
-// SignTxPassphrase signs the transaction if the private key matching the
-// given address can be decrypted with the given passphrase.
-func (ks *KeyStore) SignTxPassphrase(account *Account, passphrase string, tx *Transaction, chainID *BigInt) (*Transaction, error) {
-	if chainID == nil { // Null passed from mobile app
-		chainID = new(BigInt)
+// SignTxPassphrase signs the transaction like SignTx if the private key matching
+// the given address can be decrypted with the given passphrase.
+func (ks *KeyStore) SignTxPassphrase(account *Account, passphrase string, tx *Transaction, chainID *BigInt) (_ *Transaction, err error) {
+	defer recoverError(&err)
+	return signKeyStoreTx(tx, chainID, func(hash []byte) ([]byte, error) {
+		return ks.keystore.SignHashWithPassphrase(account.account, passphrase, hash)
+	})
+}
+
+// signKeyStoreTx signs the transaction hash with the given keystore function and
+// attaches the signature.
+func signKeyStoreTx(tx *Transaction, chainID *BigInt, sign func(hash []byte) ([]byte, error)) (*Transaction, error) {
+	signer, err := transactionSigner(tx.tx, chainID)
+	if err != nil {
+		return nil, err
 	}
-	signed, err := ks.keystore.SignTxWithPassphrase(account.account, passphrase, tx.tx, chainID.bigint)
+	hash := signer.Hash(tx.tx)
+	sig, err := sign(hash[:])
+	if err != nil {
+		return nil, keystoreError(err)
+	}
+	signed, err := tx.tx.WithSignature(signer, sig)
 	if err != nil {
 		return nil, err
 	}
-	return &Transaction{signed}, nil
+	return &Transaction{tx: signed}, nil
 }
 
 // Unlock unlocks the given account indefinitely.
-func (ks *KeyStore) Unlock(account *Account, passphrase string) error {
-	return ks.keystore.TimedUnlock(account.account, passphrase, 0)
+func (ks *KeyStore) Unlock(account *Account, passphrase string) (err error) {
+	defer recoverError(&err)
+	return keystoreError(ks.keystore.TimedUnlock(account.account, passphrase, 0))
 }
 
 // Lock removes the private key with the given address from memory.
-func (ks *KeyStore) Lock(address *Address) error {
+func (ks *KeyStore) Lock(address *Address) (err error) {
+	defer recoverError(&err)
 	return ks.keystore.Lock(address.address)
 }
 
 // TimedUnlock unlocks the given account with the passphrase. The account stays
-// unlocked for the duration of timeout (nanoseconds). A timeout of 0 unlocks the
-// account until the program exits. The account must match a unique key file.
+// unlocked for the given number of seconds and is locked again automatically,
+// even if Lock is never called. A timeout of 0 unlocks the account until the
+// program exits. The account must match a unique key file.
 //
 // If the account address is already unlocked for a duration, TimedUnlock extends or
 // shortens the active unlock timeout. If the address was previously unlocked
 // indefinitely the timeout is not altered.
-func (ks *KeyStore) TimedUnlock(account *Account, passphrase string, timeout int64) error {
-	return ks.keystore.TimedUnlock(account.account, passphrase, time.Duration(timeout))
+func (ks *KeyStore) TimedUnlock(account *Account, passphrase string, seconds int64) (err error) {
+	defer recoverError(&err)
+	if seconds < 0 || seconds > math.MaxInt64/int64(time.Second) {
+		return errors.New("invalid unlock timeout")
+	}
+	return keystoreError(ks.keystore.TimedUnlock(account.account, passphrase, time.Duration(seconds)*time.Second))
+}
+
+var errIncorrectPassphrase = errors.New("incorrect passphrase")
+
+// keystoreError replaces the decryption error of the keystore, which is also
+// returned for a wrong passphrase, with errIncorrectPassphrase.
+func keystoreError(err error) error {
+	if err == keystore.ErrDecrypt {
+		return errIncorrectPassphrase
+	}
+	return err
+}
+
+// IsIncorrectPassphrase reports whether the error was caused by a passphrase not
+// matching the key.
+func IsIncorrectPassphrase(err error) bool {
+	return err == errIncorrectPassphrase
+}
+
+// IsAccountLocked reports whether the error was caused by signing with a locked
+// account without a passphrase.
+func IsAccountLocked(err error) bool {
+	return err == keystore.ErrLocked
 }
 
 // NewAccount generates a new key and stores it into the key directory,
 // encrypting it with the passphrase.
-func (ks *KeyStore) NewAccount(passphrase string) (*Account, error) {
+func (ks *KeyStore) NewAccount(passphrase string) (_ *Account, err error) {
+	defer recoverError(&err)
 	account, err := ks.keystore.NewAccount(passphrase)
 	if err != nil {
 		return nil, err
@@ -179,40 +287,133 @@ func (ks *KeyStore) NewAccount(passphrase string) (*Account, error) {
 }
 
 // UpdateAccount changes the passphrase of an existing account.
+//
+// Deprecated: use UpdatePassphrase.
 func (ks *KeyStore) UpdateAccount(account *Account, passphrase, newPassphrase string) error {
-	return ks.keystore.Update(account.account, passphrase, newPassphrase)
+	return ks.UpdatePassphrase(account, passphrase, newPassphrase)
 }
 
-// ExportKey exports as a JSON key, encrypted with newPassphrase.
-func (ks *KeyStore) ExportKey(account *Account, passphrase, newPassphrase string) (key []byte, _ error) {
-	return ks.keystore.Export(account.account, passphrase, newPassphrase)
+// UpdatePassphrase re-encrypts the key of an existing account with a new
+// passphrase. The key file is replaced atomically: the new content is written
+// and synced to a temporary file first, which is then renamed over the old one,
+// so a crash leaves either the old or the new file intact. The account is locked
+// if it was unlocked.
+func (ks *KeyStore) UpdatePassphrase(account *Account, oldPassphrase, newPassphrase string) (err error) {
+	defer recoverError(&err)
+	found, err := ks.keystore.Find(account.account)
+	if err != nil {
+		return err
+	}
+	path := found.URL.Path
+	keyJSON, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	key, err := keystore.DecryptKey(keyJSON, oldPassphrase)
+	if err != nil {
+		return keystoreError(err)
+	}
+	defer zeroKey(key.PrivateKey)
+
+	if key.Address != found.Address {
+		return fmt.Errorf("key content mismatch: have account %x, want %x", key.Address, found.Address)
+	}
+	if keyJSON, err = keystore.EncryptKey(key, newPassphrase, ks.scryptN, ks.scryptP); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, keyJSON); err != nil {
+		return err
+	}
+	return ks.keystore.Lock(found.Address)
 }
 
-// ImportKey stores the given encrypted JSON key into the key directory.
-func (ks *KeyStore) ImportKey(keyJSON []byte, passphrase, newPassphrase string) (account *Account, _ error) {
-	acc, err := ks.keystore.Import(common.CopyBytes(keyJSON), passphrase, newPassphrase)
+// writeFileAtomic replaces the file with the given content through a synced
+// temporary file in the same directory. The temporary file is hidden, so the
+// keystore never picks it up as a key.
+func writeFileAtomic(path string, content []byte) error {
+	dir := filepath.Dir(path)
+	f, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp")
 	if err != nil {
+		return err
+	}
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	// Persist the rename itself, not supported on all platforms
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
+}
+
+// ExportKeyJSON exports the key of the account as Web3 Secret Storage JSON,
+// encrypted with newPassphrase, which geth and wallets like MetaMask can import.
+func (ks *KeyStore) ExportKeyJSON(account *Account, passphrase, newPassphrase string) (key []byte, err error) {
+	defer recoverError(&err)
+	key, err = ks.keystore.Export(account.account, passphrase, newPassphrase)
+	return key, keystoreError(err)
+}
+
+// ImportKeyJSON decrypts the given Web3 Secret Storage JSON key with
+// oldPassphrase, see DecryptKey, and stores it into the key directory, encrypted
+// with newPassphrase. Keys already in the keystore fail with an error
+// IsAccountAlreadyExists reports.
+func (ks *KeyStore) ImportKeyJSON(keyJSON []byte, oldPassphrase, newPassphrase string) (account *Account, err error) {
+	defer recoverError(&err)
+	if _, err := checkKeyJSON(keyJSON); err != nil {
 		return nil, err
 	}
+	acc, err := ks.keystore.Import(common.CopyBytes(keyJSON), oldPassphrase, newPassphrase)
+	if err != nil {
+		return nil, keystoreError(err)
+	}
 	return &Account{acc}, nil
 }
 
-// ImportECDSAKey stores the given encrypted JSON key into the key directory.
-func (ks *KeyStore) ImportECDSAKey(key []byte, passphrase string) (account *Account, _ error) {
-	privkey, err := crypto.ToECDSA(common.CopyBytes(key))
+// ImportECDSAKey stores the given hex encoded private key into the key directory,
+// encrypted with the passphrase. Keys already in the keystore fail with an error
+// IsAccountAlreadyExists reports.
+func (ks *KeyStore) ImportECDSAKey(privKeyHex string, passphrase string) (account *Account, err error) {
+	defer recoverError(&err)
+	key, err := parsePrivateKeyHex(privKeyHex)
 	if err != nil {
 		return nil, err
 	}
-	acc, err := ks.keystore.ImportECDSA(privkey, passphrase)
+	defer zeroKey(key)
+
+	acc, err := ks.keystore.ImportECDSA(key, passphrase)
 	if err != nil {
 		return nil, err
 	}
 	return &Account{acc}, nil
 }
 
+// IsAccountAlreadyExists reports whether the error was caused by importing a key
+// already present in the keystore.
+func IsAccountAlreadyExists(err error) bool {
+	return err == keystore.ErrAccountAlreadyExists
+}
+
 // ImportPreSaleKey decrypts the given Ethereum presale wallet and stores
 // a key file in the key directory. The key file is encrypted with the same passphrase.
-func (ks *KeyStore) ImportPreSaleKey(keyJSON []byte, passphrase string) (ccount *Account, _ error) {
+func (ks *KeyStore) ImportPreSaleKey(keyJSON []byte, passphrase string) (ccount *Account, err error) {
+	defer recoverError(&err)
 	account, err := ks.keystore.ImportPreSaleKey(common.CopyBytes(keyJSON), passphrase)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,324 @@
+package web3go
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestKeyStoreAccounts(t *testing.T) {
+	dir := t.TempDir()
+	ks := NewKeyStore(dir, LightScryptN, LightScryptP)
+
+	first, err := ks.NewAccount("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := ks.NewAccount("bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ks.HasAddress(first.GetAddress()) || !ks.HasAddress(second.GetAddress()) {
+		t.Error("created accounts not found")
+	}
+	if ks.HasAddress(NewSeededAddress(1)) {
+		t.Error("unknown address found")
+	}
+	if size := ks.GetAccounts().Size(); size != 2 {
+		t.Errorf("account count mismatch: have %d, want 2", size)
+	}
+	// Key files must follow the Web3 Secret Storage layout
+	for _, account := range []*Account{first, second} {
+		path := strings.TrimPrefix(account.GetURL(), "keystore://")
+		if filepath.Dir(path) != dir {
+			t.Errorf("key file outside key directory: %s", path)
+		}
+		suffix := "--" + strings.ToLower(strings.TrimPrefix(account.GetAddress().GetHex(), "0x"))
+		if name := filepath.Base(path); !strings.HasPrefix(name, "UTC--") || !strings.HasSuffix(name, suffix) {
+			t.Errorf("key file name mismatch: %s", name)
+		}
+		blob, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var key struct {
+			Address string          `json:"address"`
+			Crypto  json.RawMessage `json:"crypto"`
+			ID      string          `json:"id"`
+			Version int             `json:"version"`
+		}
+		if err := json.Unmarshal(blob, &key); err != nil {
+			t.Fatal(err)
+		}
+		if key.Version != 3 || key.ID == "" || len(key.Crypto) == 0 || "--"+key.Address != suffix {
+			t.Errorf("key file content mismatch: %s", blob)
+		}
+	}
+	// Reopening the directory must find the same keys
+	reopened := NewKeyStore(dir, LightScryptN, LightScryptP)
+	accounts := reopened.GetAccounts()
+	if accounts.Size() != 2 {
+		t.Fatalf("reopened account count mismatch: have %d, want 2", accounts.Size())
+	}
+	for i := 0; i < accounts.Size(); i++ {
+		account, _ := accounts.Get(i)
+		if !account.GetAddress().Equals(first.GetAddress()) && !account.GetAddress().Equals(second.GetAddress()) {
+			t.Errorf("unexpected account %s", account.GetAddress().GetHex())
+		}
+	}
+	if _, err := accounts.Get(2); err == nil {
+		t.Error("expected bounds error")
+	}
+}
+
+func TestKeyStoreSigning(t *testing.T) {
+	ks := NewKeyStore(t.TempDir(), LightScryptN, LightScryptP)
+	account, err := ks.NewAccount("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	chainID := NewBigInt(5)
+	tx := newTestDynamicFeeTx(chainID, 0, NewSeededAddress(1), NewBigInt(1), 21000, NewBigInt(1), NewBigInt(2), nil)
+
+	if _, err := ks.SignTxPassphrase(account, "bar", tx, chainID); !IsIncorrectPassphrase(err) {
+		t.Errorf("wrong passphrase error mismatch: %v", err)
+	}
+	if err := ks.Unlock(account, "bar"); !IsIncorrectPassphrase(err) {
+		t.Errorf("wrong unlock passphrase error mismatch: %v", err)
+	}
+	signed, err := ks.SignTxPassphrase(account, "foo", tx, chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from, err := signed.GetFrom(chainID); err != nil || !from.Equals(account.GetAddress()) {
+		t.Errorf("sender mismatch: have %v (%v), want %s", from, err, account.GetAddress().GetHex())
+	}
+	if _, err := ks.SignTxPassphrase(account, "foo", tx, NewBigInt(1)); err == nil {
+		t.Error("expected error for chain ID mismatch")
+	}
+	hash := NewSeededHash(1)
+	sig, err := ks.SignHashPassphrase(account, "foo", hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pub, err := SigToPub(hash, sig); err != nil || !PubkeyToAddress(pub).Equals(account.GetAddress()) {
+		t.Errorf("hash signer mismatch: %v", err)
+	}
+	// Signing without passphrase requires an unlock, which expires by itself
+	if _, err := ks.SignTx(account, tx, chainID); !IsAccountLocked(err) {
+		t.Errorf("locked account error mismatch: %v", err)
+	}
+	if err := ks.TimedUnlock(account, "foo", 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ks.SignTx(account, tx, chainID); err != nil {
+		t.Errorf("unlocked signing failed: %v", err)
+	}
+	if _, err := ks.SignHash(account.GetAddress(), hash); err != nil {
+		t.Errorf("unlocked hash signing failed: %v", err)
+	}
+	time.Sleep(1500 * time.Millisecond)
+	if _, err := ks.SignTx(account, tx, chainID); !IsAccountLocked(err) {
+		t.Errorf("timed unlock did not expire: %v", err)
+	}
+	if err := ks.Unlock(account, "foo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.Lock(account.GetAddress()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ks.SignHash(account.GetAddress(), hash); !IsAccountLocked(err) {
+		t.Errorf("lock error mismatch: %v", err)
+	}
+}
+
+// testKeyJSON is a Web3 Secret Storage version 3 key file as exported by MetaMask
+// and geth (aes-128-ctr, scrypt), the test vector of the specification.
+var (
+	testKeyJSON       = `{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"83dbcc02d8ccb40e466191a123791e0e"},"ciphertext":"d172bf743a674da9cdad04534d56926ef8358534d458fffccd4e6ad2fbde479c","kdf":"scrypt","kdfparams":{"dklen":32,"n":262144,"r":1,"p":8,"salt":"ab0c7876052600dd703518d6fc3fe8984592145b591fc8fb5c6d43190334ba19"},"mac":"2103ac29920d71da29f15d75b4a16dbe95cfd7ff8faea1056c33131d846e3097"},"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`
+	testKeyJSONPass   = "testpassword"
+	testKeyJSONSecret = "7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d"
+)
+
+func TestKeyStoreImportExport(t *testing.T) {
+	ks := NewKeyStore(t.TempDir(), LightScryptN, LightScryptP)
+	want := crypto.PubkeyToAddress(crypto.ToECDSAUnsafe(common.FromHex(testKeyJSONSecret)).PublicKey)
+
+	if _, err := ks.ImportKeyJSON([]byte(testKeyJSON), "wrong", "foo"); !IsIncorrectPassphrase(err) {
+		t.Errorf("wrong passphrase error mismatch: %v", err)
+	}
+	account, err := ks.ImportKeyJSON([]byte(testKeyJSON), testKeyJSONPass, "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.GetAddress().address != want {
+		t.Fatalf("address mismatch: have %s, want %s", account.GetAddress().GetHex(), want.Hex())
+	}
+	if _, err := ks.ImportKeyJSON([]byte(testKeyJSON), testKeyJSONPass, "foo"); !IsAccountAlreadyExists(err) {
+		t.Errorf("duplicate import error mismatch: %v", err)
+	}
+	if _, err := ks.ImportECDSAKey("0x"+testKeyJSONSecret, "foo"); !IsAccountAlreadyExists(err) {
+		t.Errorf("duplicate key import error mismatch: %v", err)
+	}
+	if size := ks.GetAccounts().Size(); size != 1 {
+		t.Errorf("account count mismatch: have %d, want 1", size)
+	}
+	// Exported keys must decrypt with geth
+	blob, err := ks.ExportKeyJSON(account, "foo", "bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := keystore.DecryptKey(blob, "bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have := common.Bytes2Hex(crypto.FromECDSA(key.PrivateKey)); have != testKeyJSONSecret {
+		t.Errorf("exported key mismatch: have %s, want %s", have, testKeyJSONSecret)
+	}
+	if _, err := ks.ExportKeyJSON(account, "wrong", "bar"); !IsIncorrectPassphrase(err) {
+		t.Errorf("wrong export passphrase error mismatch: %v", err)
+	}
+	if err := ks.DeleteAccount(account, "wrong"); !IsIncorrectPassphrase(err) {
+		t.Errorf("wrong delete passphrase error mismatch: %v", err)
+	}
+	if err := ks.DeleteAccount(account, "foo"); err != nil {
+		t.Fatal(err)
+	}
+	if ks.HasAddress(account.GetAddress()) {
+		t.Error("deleted account still present")
+	}
+	imported, err := ks.ImportECDSAKey(testKeyJSONSecret, "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.GetAddress().address != want {
+		t.Errorf("imported key address mismatch: have %s", imported.GetAddress().GetHex())
+	}
+	if _, err := ks.ImportECDSAKey("0x1234", "foo"); err == nil {
+		t.Error("expected error for invalid key")
+	}
+}
+
+func TestAccountsFind(t *testing.T) {
+	ks := NewKeyStore(t.TempDir(), LightScryptN, LightScryptP)
+	first, _ := ks.NewAccount("foo")
+	second, _ := ks.NewAccount("foo")
+
+	list := ks.GetAccounts()
+	found, err := list.Find(second.GetAddress())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found.Equals(second) || found.Equals(first) || found.GetURL() != second.GetURL() {
+		t.Errorf("found account mismatch: have %s", found.GetURL())
+	}
+	if _, err := list.Find(NewSeededAddress(1)); err == nil {
+		t.Error("expected error for unknown address")
+	}
+	// Equality is by address, so a derived account matches its stored copy
+	derived := &Account{accounts.Account{Address: first.account.Address}}
+	if !derived.Equals(first) || !list.Contains(derived.GetAddress()) {
+		t.Error("account equality not by address")
+	}
+	empty := NewAccountsEmpty()
+	if empty.Contains(first.GetAddress()) || empty.Append(nil) == nil {
+		t.Error("empty list mismatch")
+	}
+	empty.Append(first)
+	if empty.Size() != 1 || !empty.Contains(first.GetAddress()) {
+		t.Error("append mismatch")
+	}
+}
+
+func TestKeyStoreUpdatePassphrase(t *testing.T) {
+	dir := t.TempDir()
+	ks := NewKeyStore(dir, LightScryptN, LightScryptP)
+	account, err := ks.NewAccount("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := strings.TrimPrefix(account.GetURL(), "keystore://")
+	want := strings.ToLower(strings.TrimPrefix(account.GetAddress().GetHex(), "0x"))
+
+	if err := ks.UpdatePassphrase(account, "wrong", "bar"); !IsIncorrectPassphrase(err) {
+		t.Errorf("wrong passphrase error mismatch: %v", err)
+	}
+	if err := ks.Unlock(account, "foo"); err != nil {
+		t.Fatal(err)
+	}
+	// Concurrent readers must only ever observe complete key files
+	var (
+		done    = make(chan struct{})
+		invalid = make(chan string, 1)
+	)
+	go func() {
+		defer close(invalid)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			blob, err := os.ReadFile(path)
+			var key struct {
+				Address string `json:"address"`
+			}
+			if err != nil || json.Unmarshal(blob, &key) != nil || key.Address != want {
+				invalid <- fmt.Sprintf("%v: %q", err, blob)
+				return
+			}
+		}
+	}()
+	passphrases := []string{"foo", "bar", "baz", "qux"}
+	for i := 1; i < len(passphrases); i++ {
+		if err := ks.UpdatePassphrase(account, passphrases[i-1], passphrases[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	close(done)
+	if failure, ok := <-invalid; ok {
+		t.Fatalf("partial key file observed: %s", failure)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 || entries[0].Name() != filepath.Base(path) {
+		t.Errorf("unexpected files in key directory: %v", entries)
+	}
+	hash := NewSeededHash(1)
+	if _, err := ks.SignHash(account.GetAddress(), hash); !IsAccountLocked(err) {
+		t.Errorf("account not locked by update: %v", err)
+	}
+	if _, err := ks.SignHashPassphrase(account, "foo", hash); !IsIncorrectPassphrase(err) {
+		t.Errorf("old passphrase still accepted: %v", err)
+	}
+	if _, err := ks.SignHashPassphrase(account, "qux", hash); err != nil {
+		t.Errorf("new passphrase rejected: %v", err)
+	}
+	// Deletion requires the passphrase and drops the unlocked key
+	if err := ks.Unlock(account, "qux"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.DeleteAccount(account, "foo"); !IsIncorrectPassphrase(err) {
+		t.Errorf("wrong delete passphrase error mismatch: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("key file removed without passphrase: %v", err)
+	}
+	if err := ks.DeleteAccount(account, "qux"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("key file not removed: %v", err)
+	}
+	if _, err := ks.SignHash(account.GetAddress(), hash); err == nil {
+		t.Error("deleted account still unlocked")
+	}
+}
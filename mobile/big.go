@@ -19,8 +19,11 @@
 package web3go
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"math/big"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 )
@@ -34,6 +37,25 @@ type BigInt struct {
 	bigint *big.Int
 }
 
+// copyBig returns an independent copy of x, or nil if x is nil. Big ints crossing
+// the boundary of a wrapped object are copied, so mutating a BigInt, e.g. with
+// SetInt64, never corrupts the object it was taken from or passed to.
+func copyBig(x *big.Int) *big.Int {
+	if x == nil {
+		return nil
+	}
+	return new(big.Int).Set(x)
+}
+
+// copyBigs returns an independent copy of the big ints in xs.
+func copyBigs(xs []*big.Int) []*big.Int {
+	cpy := make([]*big.Int, len(xs))
+	for i, x := range xs {
+		cpy[i] = copyBig(x)
+	}
+	return cpy
+}
+
 func NewBigFloat(x float64) *BigFloat {
 	return &BigFloat{big.NewFloat(x)}
 }
@@ -42,7 +64,8 @@ func (bf *BigFloat) String() string {
 	return bf.bigfloat.String()
 }
 
-func (bf *BigFloat) GetBigInt() (*BigInt, error) {
+func (bf *BigFloat) GetBigInt() (_ *BigInt, err error) {
+	defer recoverError(&err)
 	x, y := bf.bigfloat.Int(nil)
 	if y != big.Exact {
 		return nil, errors.New("The result is not Exact")
@@ -60,6 +83,39 @@ func NewBigInt(x int64) *BigInt {
 	return &BigInt{big.NewInt(x)}
 }
 
+// NewBigIntFromInt64 allocates and returns a new BigInt set to x.
+func NewBigIntFromInt64(x int64) *BigInt {
+	return NewBigInt(x)
+}
+
+// NewBigIntFromString parses a signed integer in the given base, between 2 and
+// 62. Base 0 selects base 16 for input with a "0x" or "0X" prefix and base 10
+// otherwise; unlike SetString, a leading "0" does not select octal. Invalid or
+// empty input is an error rather than a zero value.
+func NewBigIntFromString(s string, base int) (*BigInt, error) {
+	digits, neg := s, false
+	if len(digits) > 0 && (digits[0] == '-' || digits[0] == '+') {
+		digits, neg = digits[1:], digits[0] == '-'
+	}
+	if len(digits) >= 2 && digits[0] == '0' && (digits[1] == 'x' || digits[1] == 'X') && (base == 0 || base == 16) {
+		digits, base = digits[2:], 16
+	}
+	if base == 0 {
+		base = 10
+	}
+	if base < 2 || base > big.MaxBase {
+		return nil, fmt.Errorf("invalid base %d", base)
+	}
+	x, ok := new(big.Int).SetString(digits, base)
+	if !ok || strings.ContainsAny(digits, "+-_") {
+		return nil, fmt.Errorf("invalid base %d integer %q", base, s)
+	}
+	if neg {
+		x.Neg(x)
+	}
+	return &BigInt{x}, nil
+}
+
 // GetBytes returns the absolute value of x as a big-endian byte slice.
 func (bi *BigInt) GetBytes() []byte {
 	return bi.bigint.Bytes()
@@ -71,11 +127,26 @@ func (bi *BigInt) String() string {
 }
 
 // GetInt64 returns the int64 representation of x. If x cannot be represented in
-// an int64, the result is undefined.
+// an int64, the result is undefined; use GetInt64Checked for values that may be
+// large, such as balances in wei.
 func (bi *BigInt) GetInt64() int64 {
 	return bi.bigint.Int64()
 }
 
+// FitsInt64 reports whether x can be represented as an int64.
+func (bi *BigInt) FitsInt64() bool {
+	return bi.bigint.IsInt64()
+}
+
+// GetInt64Checked returns the int64 representation of x, failing if x cannot be
+// represented in an int64.
+func (bi *BigInt) GetInt64Checked() (int64, error) {
+	if !bi.bigint.IsInt64() {
+		return 0, fmt.Errorf("integer %v overflows int64", bi.bigint)
+	}
+	return bi.bigint.Int64(), nil
+}
+
 // SetBytes interprets buf as the bytes of a big-endian unsigned integer and sets
 // the big int to that value.
 func (bi *BigInt) SetBytes(buf []byte) {
@@ -116,6 +187,11 @@ func NewBigInts(size int) *BigInts {
 	}
 }
 
+// NewBigIntsEmpty creates an empty slice of big ints.
+func NewBigIntsEmpty() *BigInts {
+	return NewBigInts(0)
+}
+
 // Size returns the number of big ints in the slice.
 func (bi *BigInts) Size() int {
 	return len(bi.bigints)
@@ -126,7 +202,7 @@ func (bi *BigInts) Get(index int) (bigint *BigInt, _ error) {
 	if index < 0 || index >= len(bi.bigints) {
 		return nil, errors.New("index out of bounds")
 	}
-	return &BigInt{bi.bigints[index]}, nil
+	return &BigInt{copyBig(bi.bigints[index])}, nil
 }
 
 // Set sets the big int at the given index in the slice.
@@ -134,16 +210,126 @@ func (bi *BigInts) Set(index int, bigint *BigInt) error {
 	if index < 0 || index >= len(bi.bigints) {
 		return errors.New("index out of bounds")
 	}
-	bi.bigints[index] = bigint.bigint
+	bi.bigints[index] = copyBig(bigint.bigint)
+	return nil
+}
+
+// Append adds a new big int to the end of the slice.
+func (bi *BigInts) Append(bigint *BigInt) error {
+	if bigint == nil || bigint.bigint == nil {
+		return errors.New("nil big int")
+	}
+	bi.bigints = append(bi.bigints, copyBig(bigint.bigint))
 	return nil
 }
 
+// Sum returns the sum of all big ints in the slice, treating uninitialized ones
+// as zero.
+func (bi *BigInts) Sum() *BigInt {
+	sum := new(big.Int)
+	for _, x := range bi.bigints {
+		if x != nil {
+			sum.Add(sum, x)
+		}
+	}
+	return &BigInt{sum}
+}
+
+// NewBigIntsFromJSON parses a slice of big ints from a JSON array of decimal
+// strings, e.g. ["1000000000000000000","-5"].
+func NewBigIntsFromJSON(data string) (_ *BigInts, err error) {
+	defer recoverError(&err)
+	var values []*string
+	if err := json.Unmarshal([]byte(data), &values); err != nil {
+		return nil, err
+	}
+	bi := NewBigInts(len(values))
+	for i, value := range values {
+		if value == nil {
+			return nil, fmt.Errorf("big int %d: missing value", i)
+		}
+		x, err := NewBigIntFromString(*value, 10)
+		if err != nil {
+			return nil, fmt.Errorf("big int %d: %v", i, err)
+		}
+		bi.bigints[i] = x.bigint
+	}
+	return bi, nil
+}
+
+// EncodeJSON encodes a slice of big ints into a JSON array of decimal strings,
+// which unlike JSON numbers keep their precision in every language. Uninitialized
+// entries are encoded as "0".
+func (bi *BigInts) EncodeJSON() (_ string, err error) {
+	defer recoverError(&err)
+	values := make([]string, len(bi.bigints))
+	for i, x := range bi.bigints {
+		if x == nil {
+			values[i] = "0"
+		} else {
+			values[i] = x.String()
+		}
+	}
+	data, err := json.Marshal(values)
+	return string(data), err
+}
+
 // GetString returns the value of x as a formatted string in some number base.
 func (bi *BigInt) GetString(base int) string {
 	return bi.bigint.Text(base)
 }
 
-func (bi *BigInt) Mul(x *BigInt) (*BigInt) {
-	bi.bigint.Mul(bi.bigint, x.bigint)
-	return bi
+// errDivisionByZero is returned by the division operations on a zero divisor.
+var errDivisionByZero = errors.New("division by zero")
+
+// Add returns the sum x+y as a new big int.
+func (bi *BigInt) Add(y *BigInt) *BigInt {
+	return &BigInt{new(big.Int).Add(bi.bigint, y.bigint)}
+}
+
+// Sub returns the difference x-y as a new big int.
+func (bi *BigInt) Sub(y *BigInt) *BigInt {
+	return &BigInt{new(big.Int).Sub(bi.bigint, y.bigint)}
+}
+
+// Mul returns the product x*y as a new big int.
+func (bi *BigInt) Mul(y *BigInt) *BigInt {
+	return &BigInt{new(big.Int).Mul(bi.bigint, y.bigint)}
+}
+
+// Div returns the Euclidean quotient x/y as a new big int, such that
+// x = y*q + m with 0 <= m < |y|. It fails if y is zero.
+func (bi *BigInt) Div(y *BigInt) (*BigInt, error) {
+	if y.bigint.Sign() == 0 {
+		return nil, errDivisionByZero
+	}
+	return &BigInt{new(big.Int).Div(bi.bigint, y.bigint)}, nil
+}
+
+// Mod returns the Euclidean modulus x%y as a new big int, which is never
+// negative. It fails if y is zero.
+func (bi *BigInt) Mod(y *BigInt) (*BigInt, error) {
+	if y.bigint.Sign() == 0 {
+		return nil, errDivisionByZero
+	}
+	return &BigInt{new(big.Int).Mod(bi.bigint, y.bigint)}, nil
+}
+
+// Cmp compares x and y and returns:
+//
+//	-1 if x <  y
+//	 0 if x == y
+//	+1 if x >  y
+func (bi *BigInt) Cmp(y *BigInt) int {
+	return bi.bigint.Cmp(y.bigint)
+}
+
+// Abs returns the absolute value |x| as a new big int.
+func (bi *BigInt) Abs() *BigInt {
+	return &BigInt{new(big.Int).Abs(bi.bigint)}
+}
+
+// Neg returns the negation -x as a new big int.
+func (bi *BigInt) Neg() *BigInt {
+	return &BigInt{new(big.Int).Neg(bi.bigint)}
 }
@@ -0,0 +1,192 @@
+package web3go
+
+import (
+	"testing"
+)
+
+func TestBigIntArithmetic(t *testing.T) {
+	var (
+		a    = NewBigInt(-7)
+		b    = NewBigInt(2)
+		zero = NewBigInt(0)
+	)
+	div, err := a.Div(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mod, err := a.Mod(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		have *BigInt
+		want string
+	}{
+		{"add", a.Add(b), "-5"},
+		{"sub", a.Sub(b), "-9"},
+		{"mul", a.Mul(b), "-14"},
+		{"div", div, "-4"},
+		{"mod", mod, "1"},
+		{"abs", a.Abs(), "7"},
+		{"neg", a.Neg(), "7"},
+		{"neg zero", zero.Neg(), "0"},
+	}
+	for _, tt := range tests {
+		if tt.have.String() != tt.want {
+			t.Errorf("%s: have %s, want %s", tt.name, tt.have, tt.want)
+		}
+	}
+	if a.String() != "-7" || b.String() != "2" {
+		t.Errorf("operands mutated: %s, %s", a, b)
+	}
+	if a.Cmp(b) != -1 || b.Cmp(a) != 1 || a.Cmp(NewBigInt(-7)) != 0 {
+		t.Error("comparison mismatch")
+	}
+	if a.Sign() != -1 || zero.Sign() != 0 || b.Sign() != 1 {
+		t.Error("sign mismatch")
+	}
+	if _, err := a.Div(zero); err == nil {
+		t.Error("expected error for division by zero")
+	}
+	if _, err := a.Mod(zero); err == nil {
+		t.Error("expected error for modulus by zero")
+	}
+	// Operands sharing the same underlying value
+	shared := &BigInt{b.bigint}
+	if sum := b.Add(shared); sum.String() != "4" || b.String() != "2" {
+		t.Errorf("shared add mismatch: have %s, operand %s", sum, b)
+	}
+	if sq := b.Mul(b); sq.String() != "4" || b.String() != "2" {
+		t.Errorf("self multiplication mismatch: have %s, operand %s", sq, b)
+	}
+	if diff := b.Sub(shared); diff.Sign() != 0 {
+		t.Errorf("shared sub mismatch: have %s", diff)
+	}
+	// Results must not alias the operands
+	if abs := b.Abs(); abs.bigint == b.bigint {
+		t.Error("Abs aliases its receiver")
+	}
+}
+
+func TestBigIntFromString(t *testing.T) {
+	tests := []struct {
+		input string
+		base  int
+		want  string
+	}{
+		{"1000000000000000000", 0, "1000000000000000000"},
+		{"0xde0b6b3a7640000", 0, "1000000000000000000"},
+		{"0XDE0B6B3A7640000", 0, "1000000000000000000"},
+		{"de0b6b3a7640000", 16, "1000000000000000000"},
+		{"0xde0b6b3a7640000", 16, "1000000000000000000"},
+		{"-1000000000000000000", 10, "-1000000000000000000"},
+		{"-0xff", 0, "-255"},
+		{"+42", 0, "42"},
+		{"0755", 0, "755"},
+		{"101", 2, "5"},
+	}
+	for _, tt := range tests {
+		x, err := NewBigIntFromString(tt.input, tt.base)
+		if err != nil {
+			t.Errorf("%q base %d: %v", tt.input, tt.base, err)
+			continue
+		}
+		if x.String() != tt.want {
+			t.Errorf("%q base %d: have %s, want %s", tt.input, tt.base, x, tt.want)
+		}
+	}
+	invalid := []struct {
+		input string
+		base  int
+	}{
+		{"", 0}, {"-", 0}, {"0x", 0}, {"1e18", 0}, {"12a", 10}, {"0xde0b", 10},
+		{"--1", 0}, {"-+1", 0}, {"1_000", 0}, {" 1", 0}, {"1", 1}, {"1", 63},
+	}
+	for _, tt := range invalid {
+		if x, err := NewBigIntFromString(tt.input, tt.base); err == nil {
+			t.Errorf("%q base %d: expected error, have %s", tt.input, tt.base, x)
+		}
+	}
+	// Negative values round-trip through every base
+	x, _ := NewBigIntFromString("-123456789012345678901234567890", 10)
+	for _, base := range []int{2, 10, 16, 36} {
+		y, err := NewBigIntFromString(x.GetString(base), base)
+		if err != nil || y.Cmp(x) != 0 {
+			t.Errorf("base %d round-trip mismatch: have %v, err %v", base, y, err)
+		}
+	}
+	if NewBigIntFromInt64(-5).String() != "-5" {
+		t.Error("int64 constructor mismatch")
+	}
+}
+
+func TestBigIntInt64Checked(t *testing.T) {
+	tests := []struct {
+		value string
+		fits  bool
+	}{
+		{"9223372036854775807", true},         // max int64
+		{"-9223372036854775808", true},        // min int64
+		{"9223372036854775808", false},        // max int64 + 1
+		{"18446744073709551615", false},       // max uint64
+		{"1208925819614629174706176", false},  // 2^80
+		{"-1208925819614629174706176", false}, // -2^80
+	}
+	for _, tt := range tests {
+		v, _ := NewBigIntFromString(tt.value, 10)
+		if v.FitsInt64() != tt.fits {
+			t.Errorf("%s: fit mismatch: have %v, want %v", tt.value, v.FitsInt64(), tt.fits)
+		}
+		n, err := v.GetInt64Checked()
+		if tt.fits && (err != nil || v.String() != NewBigInt(n).String()) {
+			t.Errorf("%s: have %d, err %v", tt.value, n, err)
+		}
+		if !tt.fits && err == nil {
+			t.Errorf("%s: expected overflow error, have %d", tt.value, n)
+		}
+	}
+}
+
+func TestBigInts(t *testing.T) {
+	list, err := NewBigIntsFromJSON(`["1000000000000000000","-5","123456789012345678901234567890"]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.Size() != 3 {
+		t.Fatalf("size mismatch: have %d, want 3", list.Size())
+	}
+	if err := list.Append(NewBigInt(5)); err != nil {
+		t.Fatal(err)
+	}
+	if err := list.Append(nil); err == nil {
+		t.Error("expected error for nil big int")
+	}
+	if _, err := list.Get(4); err == nil || err.Error() != "index out of bounds" {
+		t.Errorf("bounds error mismatch: %v", err)
+	}
+	if have, want := list.Sum().String(), "123456789013345678901234567890"; have != want {
+		t.Errorf("sum mismatch: have %s, want %s", have, want)
+	}
+	data, err := list.EncodeJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `["1000000000000000000","-5","123456789012345678901234567890","5"]`; data != want {
+		t.Errorf("JSON mismatch:\nhave %s\nwant %s", data, want)
+	}
+	if data, _ := NewBigIntsEmpty().EncodeJSON(); data != "[]" {
+		t.Errorf("empty JSON mismatch: have %s", data)
+	}
+	if data, _ := NewBigInts(2).EncodeJSON(); data != `["0","0"]` {
+		t.Errorf("uninitialized JSON mismatch: have %s", data)
+	}
+	if NewBigInts(2).Sum().Sign() != 0 {
+		t.Error("uninitialized sum not zero")
+	}
+	for _, input := range []string{`[1]`, `["0x10"]`, `["1.5"]`, `[null]`, `{}`} {
+		if _, err := NewBigIntsFromJSON(input); err == nil {
+			t.Errorf("%s: expected error", input)
+		}
+	}
+}
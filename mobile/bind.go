@@ -19,6 +19,7 @@
 package web3go
 
 import (
+	"errors"
 	"log"
 	"math/big"
 	"strings"
@@ -41,12 +42,13 @@ type signer struct {
 }
 
 // Sign
-func (s *signer) Sign(addr *Address, unsignedTx *Transaction) (signedTx *Transaction, _ error) {
+func (s *signer) Sign(addr *Address, unsignedTx *Transaction) (signedTx *Transaction, err error) {
+	defer recoverError(&err)
 	sig, err := s.sign(types.HomesteadSigner{}, addr.address, unsignedTx.tx)
 	if err != nil {
 		return nil, err
 	}
-	return &Transaction{sig}, nil
+	return &Transaction{tx: sig}, nil
 }
 
 // CallOpts is the collection of options to fine tune a contract call request.
@@ -93,17 +95,30 @@ func NewTransactOpts(pk string) *TransactOpts {
 	return &TransactOpts{opts}
 }
 
+// NewTransactOptsWithSigner creates a transactor signing through an external
+// signer, see SetHashSigner.
+func NewTransactOptsWithSigner(s HashSigner, chainID *BigInt) *TransactOpts {
+	opts := &TransactOpts{&bind.TransactOpts{}}
+	opts.SetHashSigner(s, chainID)
+	return opts
+}
+
 // GetFrom ...
 func (opts *TransactOpts) GetFrom() *Address { return &Address{opts.opts.From} }
 
 // GetNonce ...
-func (opts *TransactOpts) GetNonce() int64 { return opts.opts.Nonce.Int64() }
+//
+// Returns 0 if no nonce is set.
+func (opts *TransactOpts) GetNonce() int64 {
+	defer recoverZero()
+	return opts.opts.Nonce.Int64()
+}
 
 // GetValue ...
-func (opts *TransactOpts) GetValue() *BigInt { return &BigInt{opts.opts.Value} }
+func (opts *TransactOpts) GetValue() *BigInt { return &BigInt{copyBig(opts.opts.Value)} }
 
 // GetGasPrice ...
-func (opts *TransactOpts) GetGasPrice() *BigInt { return &BigInt{opts.opts.GasPrice} }
+func (opts *TransactOpts) GetGasPrice() *BigInt { return &BigInt{copyBig(opts.opts.GasPrice)} }
 
 // GetGasLimit ...
 func (opts *TransactOpts) GetGasLimit() int64 { return int64(opts.opts.GasLimit) }
@@ -124,7 +139,7 @@ func (opts *TransactOpts) SetNonce(nonce int64) { opts.opts.Nonce = big.NewInt(n
 // SetSigner ...
 func (opts *TransactOpts) SetSigner(s Signer) {
 	opts.opts.Signer = func(signer types.Signer, addr common.Address, tx *types.Transaction) (*types.Transaction, error) {
-		sig, err := s.Sign(&Address{addr}, &Transaction{tx})
+		sig, err := s.Sign(&Address{addr}, &Transaction{tx: tx})
 		if err != nil {
 			return nil, err
 		}
@@ -132,11 +147,28 @@ func (opts *TransactOpts) SetSigner(s Signer) {
 	}
 }
 
+// SetHashSigner makes the transactor send from the address of the external
+// signer, signing transactions for the given chain ID through it, see
+// SignTransactionWithSigner.
+func (opts *TransactOpts) SetHashSigner(s HashSigner, chainID *BigInt) {
+	opts.opts.From = s.GetAddress().address
+	opts.opts.Signer = func(signer types.Signer, addr common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		if addr != opts.opts.From {
+			return nil, errors.New("not authorized to sign this account")
+		}
+		signed, err := SignTransactionWithSigner(&Transaction{tx: tx}, s, chainID)
+		if err != nil {
+			return nil, err
+		}
+		return signed.tx, nil
+	}
+}
+
 // SetValue ...
-func (opts *TransactOpts) SetValue(value *BigInt) { opts.opts.Value = value.bigint }
+func (opts *TransactOpts) SetValue(value *BigInt) { opts.opts.Value = copyBig(value.bigint) }
 
 // SetGasPrice ...
-func (opts *TransactOpts) SetGasPrice(price *BigInt) { opts.opts.GasPrice = price.bigint }
+func (opts *TransactOpts) SetGasPrice(price *BigInt) { opts.opts.GasPrice = copyBig(price.bigint) }
 
 //SetGasLimit ...
 func (opts *TransactOpts) SetGasLimit(limit int64) { opts.opts.GasLimit = uint64(limit) }
@@ -155,7 +187,8 @@ type BoundContract struct {
 
 // DeployContract deploys a contract onto the Ethereum blockchain and binds the
 // deployment address with a wrapper.
-func DeployContract(opts *TransactOpts, abiJSON string, bytecode []byte, client *EthereumClient, args *Interfaces) (contract *BoundContract, _ error) {
+func DeployContract(opts *TransactOpts, abiJSON string, bytecode []byte, client *EthereumClient, args *Interfaces) (contract *BoundContract, err error) {
+	defer recoverError(&err)
 	// Deploy the contract to the network
 	parsed, err := abi.JSON(strings.NewReader(abiJSON))
 	if err != nil {
@@ -174,7 +207,8 @@ func DeployContract(opts *TransactOpts, abiJSON string, bytecode []byte, client
 
 // BindContract creates a low level contract interface through which calls and
 // transactions may be made through.
-func BindContract(address *Address, abiJSON string, client *EthereumClient) (contract *BoundContract, _ error) {
+func BindContract(address *Address, abiJSON string, client *EthereumClient) (contract *BoundContract, err error) {
+	defer recoverError(&err)
 	parsed, err := abi.JSON(strings.NewReader(abiJSON))
 	if err != nil {
 		return nil, err
@@ -193,12 +227,13 @@ func (c *BoundContract) GetDeployer() *Transaction {
 	if c.deployer == nil {
 		return nil
 	}
-	return &Transaction{c.deployer}
+	return &Transaction{tx: c.deployer}
 }
 
 // Call invokes the (constant) contract method with params as input values and
 // sets the output to result.
-func (c *BoundContract) Call(opts *CallOpts, out *Interfaces, method string, args *Interfaces) error {
+func (c *BoundContract) Call(opts *CallOpts, out *Interfaces, method string, args *Interfaces) (err error) {
+	defer recoverError(&err)
 	if len(out.objects) == 1 {
 		result := out.objects[0]
 		if err := c.contract.Call(opts.opts, result, method, args.objects...); err != nil {
@@ -217,20 +252,22 @@ func (c *BoundContract) Call(opts *CallOpts, out *Interfaces, method string, arg
 }
 
 // Transact invokes the (paid) contract method with params as input values.
-func (c *BoundContract) Transact(opts *TransactOpts, method string, args *Interfaces) (tx *Transaction, _ error) {
+func (c *BoundContract) Transact(opts *TransactOpts, method string, args *Interfaces) (tx *Transaction, err error) {
+	defer recoverError(&err)
 	rawTx, err := c.contract.Transact(opts.opts, method, args.objects...)
 	if err != nil {
 		return nil, err
 	}
-	return &Transaction{rawTx}, nil
+	return &Transaction{tx: rawTx}, nil
 }
 
 // Transfer initiates a plain transaction to move funds to the contract, calling
 // its default method if one is available.
-func (c *BoundContract) Transfer(opts *TransactOpts) (tx *Transaction, _ error) {
+func (c *BoundContract) Transfer(opts *TransactOpts) (tx *Transaction, err error) {
+	defer recoverError(&err)
 	rawTx, err := c.contract.Transfer(opts.opts)
 	if err != nil {
 		return nil, err
 	}
-	return &Transaction{rawTx}, nil
+	return &Transaction{tx: rawTx}, nil
 }
@@ -0,0 +1,189 @@
+// Copyright 2019 The bcl-chain Authors. All rights reserved.
+
+// Contains iterators decoding chain export dumps one record at a time.
+
+package web3go
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// countingReader tracks the number of bytes consumed from a buffered reader.
+type countingReader struct {
+	r *bufio.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+func (r *countingReader) ReadByte() (byte, error) {
+	b, err := r.r.ReadByte()
+	if err == nil {
+		r.n++
+	}
+	return b, err
+}
+
+// rlpIterator decodes consecutive RLP values from a stream, as written by
+// `geth export`.
+type rlpIterator struct {
+	input  *countingReader
+	stream *rlp.Stream
+	closer io.Closer
+	err    error // Sticky decoding failure
+}
+
+// newRLPIterator creates an iterator over the size bytes of r. The size limits
+// the length prefixes trusted by the decoder, so a corrupt dump fails instead of
+// allocating whatever a prefix claims.
+func newRLPIterator(r io.Reader, size uint64, closer io.Closer) *rlpIterator {
+	input := &countingReader{r: bufio.NewReader(r)}
+	return &rlpIterator{
+		input:  input,
+		stream: rlp.NewStream(input, size),
+		closer: closer,
+	}
+}
+
+// openRLPIterator creates an iterator over the file at the given path, limited
+// to the size of the file.
+func openRLPIterator(path string) (*rlpIterator, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return newRLPIterator(file, uint64(info.Size()), file), nil
+}
+
+// hasNext reports whether another value is available.
+func (it *rlpIterator) hasNext() bool {
+	if it.err != nil {
+		return false
+	}
+	_, err := it.input.r.Peek(1)
+	return err == nil
+}
+
+// next decodes the next value into val, reporting the offset of the value if it
+// is malformed.
+func (it *rlpIterator) next(val interface{}, kind string) error {
+	if it.err != nil {
+		return it.err
+	}
+	if !it.hasNext() {
+		return errors.New("no more " + kind + "s")
+	}
+	offset := it.input.n
+	if err := it.stream.Decode(val); err != nil {
+		it.err = fmt.Errorf("malformed %s at byte offset %d: %v", kind, offset, err)
+		return it.err
+	}
+	return nil
+}
+
+// close releases the underlying file, if any.
+func (it *rlpIterator) close() error {
+	if it.closer == nil {
+		return nil
+	}
+	err := it.closer.Close()
+	it.closer = nil
+	return err
+}
+
+// BlockIterator decodes a chain dump of consecutive RLP encoded blocks lazily,
+// holding a single block in memory at a time.
+type BlockIterator struct {
+	it *rlpIterator
+}
+
+// NewBlockIteratorFromBytes creates an iterator over the blocks in data.
+func NewBlockIteratorFromBytes(data []byte) *BlockIterator {
+	return &BlockIterator{newRLPIterator(bytes.NewReader(data), uint64(len(data)), nil)}
+}
+
+// NewBlockIteratorFromFile creates an iterator over the blocks in the file at the
+// given path. The file is kept open until Close is called.
+func NewBlockIteratorFromFile(path string) (_ *BlockIterator, err error) {
+	defer recoverError(&err)
+	it, err := openRLPIterator(path)
+	if err != nil {
+		return nil, err
+	}
+	return &BlockIterator{it}, nil
+}
+
+// HasNext reports whether another block is available. It returns false after a
+// decoding failure.
+func (it *BlockIterator) HasNext() bool { return it.it.hasNext() }
+
+// Next decodes the next block. Once a block fails to decode, the same error is
+// returned on every further call.
+func (it *BlockIterator) Next() (_ *Block, err error) {
+	defer recoverError(&err)
+	block := new(types.Block)
+	if err := it.it.next(block, "block"); err != nil {
+		return nil, err
+	}
+	return &Block{block: block}, nil
+}
+
+// Close releases the file backing the iterator, if any.
+func (it *BlockIterator) Close() error { return it.it.close() }
+
+// HeaderIterator decodes a dump of consecutive RLP encoded headers lazily,
+// holding a single header in memory at a time.
+type HeaderIterator struct {
+	it *rlpIterator
+}
+
+// NewHeaderIteratorFromBytes creates an iterator over the headers in data.
+func NewHeaderIteratorFromBytes(data []byte) *HeaderIterator {
+	return &HeaderIterator{newRLPIterator(bytes.NewReader(data), uint64(len(data)), nil)}
+}
+
+// NewHeaderIteratorFromFile creates an iterator over the headers in the file at
+// the given path. The file is kept open until Close is called.
+func NewHeaderIteratorFromFile(path string) (_ *HeaderIterator, err error) {
+	defer recoverError(&err)
+	it, err := openRLPIterator(path)
+	if err != nil {
+		return nil, err
+	}
+	return &HeaderIterator{it}, nil
+}
+
+// HasNext reports whether another header is available. It returns false after a
+// decoding failure.
+func (it *HeaderIterator) HasNext() bool { return it.it.hasNext() }
+
+// Next decodes the next header. Once a header fails to decode, the same error is
+// returned on every further call.
+func (it *HeaderIterator) Next() (_ *Header, err error) {
+	defer recoverError(&err)
+	header := new(types.Header)
+	if err := it.it.next(header, "header"); err != nil {
+		return nil, err
+	}
+	return &Header{header}, nil
+}
+
+// Close releases the file backing the iterator, if any.
+func (it *HeaderIterator) Close() error { return it.it.close() }
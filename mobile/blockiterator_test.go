@@ -0,0 +1,126 @@
+package web3go
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testChainDump returns the concatenated RLP encodings of a few blocks.
+func testChainDump(t *testing.T) ([]byte, *Blocks) {
+	txs, _ := NewTransactionsFromJSON("[" + testTxFixtures[0].json + "]")
+	blocks := NewBlocks()
+	var dump []byte
+	for i := int64(0); i < 3; i++ {
+		header := NewEmptyHeader()
+		header.SetNumber(i)
+		block, _ := NewBlock(header, txs, NewHeaders())
+		blocks.Append(block)
+		enc, err := block.EncodeRLP()
+		if err != nil {
+			t.Fatal(err)
+		}
+		dump = append(dump, enc...)
+	}
+	return dump, blocks
+}
+
+func TestBlockIterator(t *testing.T) {
+	dump, blocks := testChainDump(t)
+
+	path := filepath.Join(t.TempDir(), "chain.rlp")
+	if err := os.WriteFile(path, dump, 0600); err != nil {
+		t.Fatal(err)
+	}
+	fromFile, err := NewBlockIteratorFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fromFile.Close()
+
+	for _, it := range []*BlockIterator{NewBlockIteratorFromBytes(dump), fromFile} {
+		for i := 0; it.HasNext(); i++ {
+			block, err := it.Next()
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, _ := blocks.Get(i)
+			if block.GetHash().GetHex() != want.GetHash().GetHex() {
+				t.Errorf("block %d: hash mismatch", i)
+			}
+		}
+		if _, err := it.Next(); err == nil {
+			t.Error("expected error past the end")
+		}
+	}
+}
+
+func TestBlockIteratorCorrupt(t *testing.T) {
+	dump, blocks := testChainDump(t)
+	first, _ := blocks.Get(0)
+	size := first.GetSize()
+
+	// Break the list header of the second block
+	dump[size] = 0x01
+	it := NewBlockIteratorFromBytes(dump)
+	if _, err := it.Next(); err != nil {
+		t.Fatal(err)
+	}
+	_, err := it.Next()
+	if err == nil || !strings.Contains(err.Error(), fmt.Sprintf("byte offset %d", size)) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if it.HasNext() {
+		t.Error("iterator continues after corruption")
+	}
+}
+
+func TestBlockIteratorOversized(t *testing.T) {
+	dump, blocks := testChainDump(t)
+	first, _ := blocks.Get(0)
+	size := first.GetSize()
+
+	// Claim a 2 GiB second block, which must fail without being allocated
+	dump = append(dump[:size], 0xfb, 0x80, 0x00, 0x00, 0x00, 0x01, 0x02, 0x03)
+	path := filepath.Join(t.TempDir(), "chain.rlp")
+	if err := os.WriteFile(path, dump, 0600); err != nil {
+		t.Fatal(err)
+	}
+	fromFile, err := NewBlockIteratorFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fromFile.Close()
+
+	for _, it := range []*BlockIterator{NewBlockIteratorFromBytes(dump), fromFile} {
+		if _, err := it.Next(); err != nil {
+			t.Fatal(err)
+		}
+		_, err := it.Next()
+		if err == nil || !strings.Contains(err.Error(), fmt.Sprintf("byte offset %d", size)) || !strings.Contains(err.Error(), "exceeds available input") {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestHeaderIterator(t *testing.T) {
+	var dump []byte
+	for i := int64(0); i < 3; i++ {
+		header := NewEmptyHeader()
+		header.SetNumber(i)
+		enc, _ := header.EncodeRLP()
+		dump = append(dump, enc...)
+	}
+	it := NewHeaderIteratorFromBytes(dump)
+	for i := int64(0); it.HasNext(); i++ {
+		header, err := it.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if number, _ := header.GetNumber(); number != i {
+			t.Errorf("header number mismatch: have %d, want %d", number, i)
+		}
+	}
+}
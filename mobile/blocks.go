@@ -0,0 +1,242 @@
+// Copyright 2019 The bcl-chain Authors. All rights reserved.
+
+// Contains the retrieval of blocks and headers into the mobile wrappers.
+
+package web3go
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Block numbers selecting the named block tags instead of a block by number.
+// Other negative numbers select the latest block too.
+const (
+	LatestBlockNumber    = -1 // Most recent block of the canonical chain
+	PendingBlockNumber   = -2 // Block being built on top of the latest block
+	SafeBlockNumber      = -3 // Latest block safe from reorgs under honest majority
+	FinalizedBlockNumber = -4 // Latest block finalized by the beacon chain
+)
+
+// ParseBlockTag converts the block tags "latest", "pending", "safe" and
+// "finalized" into the block numbers selecting them, e.g. LatestBlockNumber.
+func ParseBlockTag(tag string) (int64, error) {
+	switch tag {
+	case "latest":
+		return LatestBlockNumber, nil
+	case "pending":
+		return PendingBlockNumber, nil
+	case "safe":
+		return SafeBlockNumber, nil
+	case "finalized":
+		return FinalizedBlockNumber, nil
+	}
+	return 0, fmt.Errorf("unknown block tag %q", tag)
+}
+
+// blockNumberArg converts a block number or tag into the argument of the client,
+// where nil selects the latest block.
+func blockNumberArg(number int64) *big.Int {
+	switch {
+	case number >= 0:
+		return big.NewInt(number)
+	case number == PendingBlockNumber:
+		return big.NewInt(int64(rpc.PendingBlockNumber))
+	case number == SafeBlockNumber:
+		return big.NewInt(int64(rpc.SafeBlockNumber))
+	case number == FinalizedBlockNumber:
+		return big.NewInt(int64(rpc.FinalizedBlockNumber))
+	}
+	return nil
+}
+
+// blockNumberParam converts a block number or tag into the block parameter of
+// raw RPC calls.
+func blockNumberParam(number int64) interface{} {
+	switch {
+	case number >= 0:
+		return hexutil.Uint64(number)
+	case number == PendingBlockNumber:
+		return "pending"
+	case number == SafeBlockNumber:
+		return "safe"
+	case number == FinalizedBlockNumber:
+		return "finalized"
+	}
+	return "latest"
+}
+
+// blockHashParam creates the EIP-1898 block parameter selecting a block by hash.
+// If requireCanonical is set, nodes fail calls for blocks no longer canonical.
+func blockHashParam(hash common.Hash, requireCanonical bool) interface{} {
+	return map[string]interface{}{"blockHash": hash, "requireCanonical": requireCanonical}
+}
+
+// invalidParamsCode is the JSON-RPC error code of calls with invalid parameters.
+const invalidParamsCode = -32602
+
+// blockHashNotSupportedError is returned when the node rejects the EIP-1898
+// block parameter object, expecting a block number or tag instead.
+type blockHashNotSupportedError struct {
+	method string
+	err    error
+}
+
+func (e *blockHashNotSupportedError) Error() string {
+	return "block hash parameter of " + e.method + " not supported by node: " + e.err.Error()
+}
+
+// IsBlockHashNotSupported reports whether the error was caused by the node not
+// supporting EIP-1898 block hash parameters, e.g. of CallContractAtHash.
+func IsBlockHashNotSupported(err error) bool {
+	_, ok := err.(*blockHashNotSupportedError)
+	return ok
+}
+
+// blockHashError wraps the error of an RPC call taking the block parameter at
+// the given argument index into a blockHashNotSupportedError if the node
+// rejected the parameter. Nodes naming the rejected argument, like geth, are
+// trusted. For other nodes the call is retried with the latest block tag: only
+// if that passes the parameter validation was the block hash at fault, and not
+// e.g. a malformed call object.
+func blockHashError(method string, index int, err error, retry func() error) error {
+	if !isInvalidParams(err) {
+		return err
+	}
+	msg := err.Error()
+	if strings.Contains(msg, fmt.Sprintf("argument %d", index)) {
+		return &blockHashNotSupportedError{method, err}
+	}
+	if strings.Contains(msg, "argument") || isInvalidParams(retry()) {
+		return err
+	}
+	return &blockHashNotSupportedError{method, err}
+}
+
+// isInvalidParams reports whether the error is a JSON-RPC invalid params error.
+func isInvalidParams(err error) bool {
+	rpcErr, ok := err.(rpc.Error)
+	return ok && rpcErr.ErrorCode() == invalidParamsCode
+}
+
+// IsNotFound reports whether the error was caused by the node not knowing the
+// requested block, header, transaction or receipt, including
+// ErrTransactionNotFound and ErrReceiptNotFound.
+func IsNotFound(err error) bool {
+	return err == ethereum.NotFound || err == ErrTransactionNotFound || err == ErrReceiptNotFound
+}
+
+// blockBackend is the part of the client API used to retrieve blocks.
+type blockBackend interface {
+	BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error)
+	BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error)
+}
+
+// headerBackend is the part of the client API used to retrieve headers.
+type headerBackend interface {
+	HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// BlockByNumber retrieves the block with the given number from the canonical
+// chain, including its transactions and uncles. A negative number retrieves the
+// latest block, or the block of a tag like PendingBlockNumber. An unknown block
+// fails with an error IsNotFound reports.
+func (ec *EthereumClient) BlockByNumber(ctx *Context, number int64) (_ *Block, err error) {
+	defer recoverError(&err)
+	return blockByNumber(ctx.context, ec.client, number)
+}
+
+// BlockByHash retrieves the block with the given hash, including its
+// transactions and uncles. An unknown block fails with an error IsNotFound
+// reports.
+func (ec *EthereumClient) BlockByHash(ctx *Context, hash *Hash) (_ *Block, err error) {
+	defer recoverError(&err)
+	return blockByHash(ctx.context, ec.client, hash.hash)
+}
+
+func blockByNumber(ctx context.Context, backend blockBackend, number int64) (*Block, error) {
+	block, err := backend.BlockByNumber(ctx, blockNumberArg(number))
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, ethereum.NotFound
+	}
+	if number >= 0 && (block.Number() == nil || block.Number().Int64() != number) {
+		return nil, fmt.Errorf("block number mismatch: have %v, want %d", block.Number(), number)
+	}
+	return &Block{block: block}, nil
+}
+
+func blockByHash(ctx context.Context, backend blockBackend, hash common.Hash) (*Block, error) {
+	block, err := backend.BlockByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, ethereum.NotFound
+	}
+	if block.Hash() != hash {
+		return nil, fmt.Errorf("block hash mismatch: have %x, want %x", block.Hash(), hash)
+	}
+	return &Block{block: block}, nil
+}
+
+// HeaderByNumber retrieves the header of the block with the given number from
+// the canonical chain, which is much cheaper than the full block. A negative
+// number retrieves the latest header, or the header of a tag like
+// SafeBlockNumber. Pending headers may lack the number, difficulty and other
+// fields. An unknown header fails with an error IsNotFound reports.
+func (ec *EthereumClient) HeaderByNumber(ctx *Context, number int64) (_ *Header, err error) {
+	defer recoverError(&err)
+	return headerByNumber(ctx.context, ec.client, number)
+}
+
+// HeaderByHash retrieves the header of the block with the given hash. An
+// unknown header fails with an error IsNotFound reports.
+func (ec *EthereumClient) HeaderByHash(ctx *Context, hash *Hash) (_ *Header, err error) {
+	defer recoverError(&err)
+	return headerByHash(ctx.context, ec.client, hash.hash)
+}
+
+// LatestHeader retrieves the header of the latest block of the canonical chain.
+func (ec *EthereumClient) LatestHeader(ctx *Context) (*Header, error) {
+	return ec.HeaderByNumber(ctx, LatestBlockNumber)
+}
+
+func headerByNumber(ctx context.Context, backend headerBackend, number int64) (*Header, error) {
+	header, err := backend.HeaderByNumber(ctx, blockNumberArg(number))
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, ethereum.NotFound
+	}
+	if number >= 0 && (header.Number == nil || header.Number.Int64() != number) {
+		return nil, fmt.Errorf("header number mismatch: have %v, want %d", header.Number, number)
+	}
+	return &Header{header}, nil
+}
+
+func headerByHash(ctx context.Context, backend headerBackend, hash common.Hash) (*Header, error) {
+	header, err := backend.HeaderByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, ethereum.NotFound
+	}
+	if header.Hash() != hash {
+		return nil, fmt.Errorf("header hash mismatch: have %x, want %x", header.Hash(), hash)
+	}
+	return &Header{header}, nil
+}
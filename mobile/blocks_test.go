@@ -0,0 +1,182 @@
+package web3go
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// testBlockBackend serves the blocks of a short chain.
+type testBlockBackend struct {
+	blocks  []*types.Block
+	pending *types.Header
+
+	number *big.Int // Number argument of the last header request
+}
+
+func (b *testBlockBackend) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	for _, block := range b.blocks {
+		if block.Hash() == hash {
+			return block, nil
+		}
+	}
+	return nil, ethereum.NotFound
+}
+
+func (b *testBlockBackend) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	if number == nil {
+		return b.blocks[len(b.blocks)-1], nil
+	}
+	if !number.IsUint64() || number.Uint64() >= uint64(len(b.blocks)) {
+		return nil, ethereum.NotFound
+	}
+	return b.blocks[number.Uint64()], nil
+}
+
+func (b *testBlockBackend) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	block, err := b.BlockByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	return block.Header(), nil
+}
+
+func (b *testBlockBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	b.number = number
+	if number != nil && number.Int64() == int64(rpc.PendingBlockNumber) {
+		return b.pending, nil
+	}
+	if number != nil && number.Sign() < 0 {
+		number = nil // Treat safe and finalized blocks as latest
+	}
+	block, err := b.BlockByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	return block.Header(), nil
+}
+
+// newTestBlockBackend creates a chain of three blocks, each holding a fixture
+// transaction and an uncle.
+func newTestBlockBackend(t *testing.T) *testBlockBackend {
+	backend := new(testBlockBackend)
+	parent := common.Hash{}
+	for i := int64(0); i < 3; i++ {
+		tx, err := NewFixtureTransaction(i, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		header := &types.Header{ParentHash: parent, Number: big.NewInt(i), Difficulty: big.NewInt(1), GasLimit: 30000000}
+		uncle := &types.Header{Number: big.NewInt(i), Difficulty: big.NewInt(2), Extra: []byte("uncle")}
+		block := types.NewBlockWithHeader(header).WithBody(types.Transactions{tx.tx}, []*types.Header{uncle})
+		backend.blocks = append(backend.blocks, block)
+		parent = block.Hash()
+	}
+	return backend
+}
+
+func TestBlockRetrieval(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		backend = newTestBlockBackend(t)
+	)
+	for _, want := range backend.blocks {
+		block, err := blockByHash(ctx, backend, want.Hash())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if block.GetHash().hash != want.Hash() {
+			t.Errorf("block hash mismatch: have %s, want %x", block.GetHash().GetHex(), want.Hash())
+		}
+		if block.GetTransactions().Size() != 1 || block.GetUncles().Size() != 1 {
+			t.Errorf("block body not populated: %d transactions, %d uncles", block.GetTransactions().Size(), block.GetUncles().Size())
+		}
+		byNumber, err := blockByNumber(ctx, backend, want.Number().Int64())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !byNumber.GetHash().Equals(block.GetHash()) {
+			t.Errorf("block %d mismatch by number", want.Number())
+		}
+	}
+	latest, err := blockByNumber(ctx, backend, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if number, _ := latest.GetNumber(); number != 2 {
+		t.Errorf("latest block mismatch: have %d, want 2", number)
+	}
+	if block, err := blockByNumber(ctx, backend, 3); block != nil || !IsNotFound(err) {
+		t.Errorf("missing block error mismatch: %v", err)
+	}
+	if block, err := blockByHash(ctx, backend, common.Hash{1}); block != nil || !IsNotFound(err) {
+		t.Errorf("missing block error mismatch: %v", err)
+	}
+}
+
+func TestHeaderRetrieval(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		backend = newTestBlockBackend(t)
+	)
+	for _, want := range backend.blocks {
+		header, err := headerByHash(ctx, backend, want.Hash())
+		if err != nil {
+			t.Fatal(err)
+		}
+		byNumber, err := headerByNumber(ctx, backend, want.Number().Int64())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if header.GetHash().hash != want.Hash() || !byNumber.GetHash().Equals(header.GetHash()) {
+			t.Errorf("header %d mismatch", want.Number())
+		}
+	}
+	if _, err := headerByNumber(ctx, backend, 5); !IsNotFound(err) {
+		t.Errorf("missing header error mismatch: %v", err)
+	}
+	if _, err := headerByHash(ctx, backend, common.Hash{1}); !IsNotFound(err) {
+		t.Errorf("missing header error mismatch: %v", err)
+	}
+
+	tags := map[string]*big.Int{
+		"latest":    nil,
+		"pending":   big.NewInt(int64(rpc.PendingBlockNumber)),
+		"safe":      big.NewInt(int64(rpc.SafeBlockNumber)),
+		"finalized": big.NewInt(int64(rpc.FinalizedBlockNumber)),
+	}
+	backend.pending = &types.Header{ParentHash: backend.blocks[2].Hash()}
+	for tag, want := range tags {
+		number, err := ParseBlockTag(tag)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := headerByNumber(ctx, backend, number); err != nil {
+			t.Fatalf("%s: %v", tag, err)
+		}
+		if (want == nil) != (backend.number == nil) || want != nil && want.Cmp(backend.number) != 0 {
+			t.Errorf("%s: block number argument mismatch: have %v, want %v", tag, backend.number, want)
+		}
+	}
+	if _, err := ParseBlockTag("earliest"); err == nil {
+		t.Error("expected error for unknown tag")
+	}
+	// Missing fields of pending headers must not panic the getters
+	pending, err := headerByNumber(ctx, backend, PendingBlockNumber)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pending.GetNumber(); err == nil {
+		t.Error("expected error for missing number")
+	}
+	if pending.GetNumberBig() != nil || pending.GetDifficulty() != nil || pending.GetBaseFee() != nil {
+		t.Error("missing fields not reported as nil")
+	}
+	pending.GetHash()
+	pending.GetTime()
+}
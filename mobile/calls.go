@@ -0,0 +1,99 @@
+// Copyright 2019 The bcl-chain Authors. All rights reserved.
+
+// Contains the execution of read-only contract calls through the client.
+
+package web3go
+
+import (
+	"context"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// CallContract executes a message call transaction, which is directly executed in the VM
+// of the node, but never mined into the blockchain. If the call reverts, the
+// decoded revert reason is appended to the returned error, e.g.
+// "execution reverted: insufficient balance".
+//
+// blockNumber selects the block height at which the call runs. It can be <0, in which
+// case the code is taken from the latest known block, or from the block of a tag like
+// PendingBlockNumber. Note that state from very old blocks might not be available.
+//
+// A nil recipient simulates the deployment of the contract given as data.
+func (ec *EthereumClient) CallContract(ctx *Context, msg *CallMsg, blockNumber int64) (output []byte, err error) {
+	defer recoverError(&err)
+	return callContract(ctx.context, ec.client.Client(), msg.msg, blockNumberParam(blockNumber))
+}
+
+// PendingCallContract executes a message call transaction using the EVM.
+// The state seen by the contract call is the pending state.
+func (ec *EthereumClient) PendingCallContract(ctx *Context, msg *CallMsg) (output []byte, err error) {
+	defer recoverError(&err)
+	return callContract(ctx.context, ec.client.Client(), msg.msg, "pending")
+}
+
+// CallContractAtHash executes a message call transaction like CallContract, on
+// top of the state of the block with the given hash. Unlike a block number, the
+// hash pins the call to one chain during reorgs. If requireCanonical is set, the
+// call fails once the block is no longer canonical.
+//
+// Nodes not supporting EIP-1898 block hash parameters fail with an error
+// IsBlockHashNotSupported reports.
+func (ec *EthereumClient) CallContractAtHash(ctx *Context, msg *CallMsg, blockHash *Hash, requireCanonical bool) (output []byte, err error) {
+	defer recoverError(&err)
+	return callContractAtHash(ctx.context, ec.client.Client(), msg.msg, blockHash.hash, requireCanonical)
+}
+
+func callContractAtHash(ctx context.Context, backend callBackend, msg ethereum.CallMsg, blockHash common.Hash, requireCanonical bool) ([]byte, error) {
+	output, err := callContract(ctx, backend, msg, blockHashParam(blockHash, requireCanonical))
+	if err != nil {
+		return nil, blockHashError("eth_call", 1, err, func() error {
+			_, err := callContract(ctx, backend, msg, "latest")
+			return err
+		})
+	}
+	return output, nil
+}
+
+// callContract executes eth_call at the given block parameter. The call is
+// encoded here rather than by the client, which drops the fee caps and the
+// access list of the message.
+func callContract(ctx context.Context, backend callBackend, msg ethereum.CallMsg, block interface{}) ([]byte, error) {
+	var output hexutil.Bytes
+	if err := backend.CallContext(ctx, &output, "eth_call", callArg(msg), block); err != nil {
+		return nil, withRevertReason(err)
+	}
+	return output, nil
+}
+
+// callArg encodes a message call into the transaction call object of eth_call.
+func callArg(msg ethereum.CallMsg) interface{} {
+	arg := map[string]interface{}{
+		"from": msg.From,
+		"to":   msg.To,
+	}
+	if len(msg.Data) > 0 {
+		arg["input"] = hexutil.Bytes(msg.Data)
+	}
+	if msg.Value != nil {
+		arg["value"] = (*hexutil.Big)(msg.Value)
+	}
+	if msg.Gas != 0 {
+		arg["gas"] = hexutil.Uint64(msg.Gas)
+	}
+	if msg.GasPrice != nil {
+		arg["gasPrice"] = (*hexutil.Big)(msg.GasPrice)
+	}
+	if msg.GasFeeCap != nil {
+		arg["maxFeePerGas"] = (*hexutil.Big)(msg.GasFeeCap)
+	}
+	if msg.GasTipCap != nil {
+		arg["maxPriorityFeePerGas"] = (*hexutil.Big)(msg.GasTipCap)
+	}
+	if msg.AccessList != nil {
+		arg["accessList"] = msg.AccessList
+	}
+	return arg
+}
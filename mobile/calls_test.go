@@ -0,0 +1,187 @@
+package web3go
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// A balanceOf(0x71562b71999873db5b286df957af199ec94617f7) call of the USDC
+// token and the node response, as exchanged with eth_call.
+const (
+	testBalanceOfToken    = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+	testBalanceOfInput    = "0x70a0823100000000000000000000000071562b71999873db5b286df957af199ec94617f7"
+	testBalanceOfResponse = "0x00000000000000000000000000000000000000000000000000000002540be400"
+)
+
+// testCallService mocks eth_call, answering the balanceOf call, reverting calls
+// to other contracts and returning the init code of deployments.
+type testCallService struct {
+	args  map[string]interface{}
+	block rpc.BlockNumberOrHash
+}
+
+func (s *testCallService) Call(args map[string]interface{}, block rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	s.args, s.block = args, block
+	input, _ := args["input"].(string)
+	switch to, _ := args["to"].(string); {
+	case to == "":
+		return hexutil.Decode(input)
+	case to == testBalanceOfToken && input == testBalanceOfInput:
+		return hexutil.Decode(testBalanceOfResponse)
+	}
+	return nil, &testDataError{"execution reverted", testRevertString}
+}
+
+func TestCallContract(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		service = new(testCallService)
+		client  = newTestRPCClient(t, service)
+	)
+	token, _ := NewAddressFromHex(testBalanceOfToken)
+	msg := NewCallMsg()
+	msg.SetTo(token)
+	msg.SetData(hexutil.MustDecode(testBalanceOfInput))
+	msg.SetGasFeeCap(NewBigInt(30000000000))
+	msg.SetGasTipCap(NewBigInt(1000000000))
+	msg.AddAccessTuple(token, nil)
+
+	output, err := callContract(ctx, client.Client(), msg.msg, blockNumberParam(18000000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance := new(big.Int).SetBytes(output); balance.Cmp(big.NewInt(10000000000)) != 0 {
+		t.Errorf("balance mismatch: have %v, want 10000000000", balance)
+	}
+	if number, ok := service.block.Number(); !ok || number != 18000000 {
+		t.Errorf("block parameter mismatch: %+v", service.block)
+	}
+	if list, ok := service.args["accessList"].([]interface{}); !ok || len(list) != 1 {
+		t.Errorf("access list not sent: %v", service.args["accessList"])
+	}
+	if service.args["maxFeePerGas"] != "0x6fc23ac00" || service.args["maxPriorityFeePerGas"] != "0x3b9aca00" {
+		t.Errorf("fee caps not sent: %v", service.args)
+	}
+	if _, err := callContract(ctx, client.Client(), msg.msg, "pending"); err != nil {
+		t.Fatal(err)
+	}
+	if number, ok := service.block.Number(); !ok || number != rpc.PendingBlockNumber {
+		t.Errorf("pending tag not sent: %+v", service.block)
+	}
+	// Reverts must carry the decoded reason
+	msg.SetTo(NewSeededAddress(1))
+	_, err = callContract(ctx, client.Client(), msg.msg, blockNumberParam(LatestBlockNumber))
+	if err == nil || err.Error() != "execution reverted: insufficient balance" {
+		t.Errorf("revert error mismatch: %v", err)
+	}
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) || dataErr.ErrorData() != testRevertString {
+		t.Errorf("revert data not kept: %v", err)
+	}
+	// Calls without recipient simulate deployments
+	msg.SetTo(nil)
+	msg.SetData([]byte{0x60, 0x80})
+	if output, err := callContract(ctx, client.Client(), msg.msg, blockNumberParam(LatestBlockNumber)); err != nil || !strings.EqualFold(hexutil.Encode(output), "0x6080") {
+		t.Errorf("deployment call mismatch: have %x (%v)", output, err)
+	}
+	if service.args["to"] != nil {
+		t.Errorf("recipient sent for deployment call: %v", service.args["to"])
+	}
+	msg.ClearAccessList()
+	if msg.GetAccessListSize() != 0 {
+		t.Error("access list not cleared")
+	}
+}
+
+// testLegacyCallService mocks eth_call of nodes predating EIP-1898, which only
+// accept block numbers and tags.
+type testLegacyCallService struct{}
+
+func (s *testLegacyCallService) Call(args map[string]interface{}, block string) (hexutil.Bytes, error) {
+	return hexutil.Decode(testBalanceOfResponse)
+}
+
+func (s *testLegacyCallService) GetBalance(account common.Address, block string) (*hexutil.Big, error) {
+	return new(hexutil.Big), nil
+}
+
+func TestCallContractAtHash(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		service = new(testCallService)
+		client  = newTestRPCClient(t, service).Client()
+		hash    = common.HexToHash("0x99031645953235a8769ab14d28a0b7ef772fe706f52da383334fa82b9817faab")
+	)
+	token, _ := NewAddressFromHex(testBalanceOfToken)
+	msg := NewCallMsg()
+	msg.SetTo(token)
+	msg.SetData(hexutil.MustDecode(testBalanceOfInput))
+
+	for _, requireCanonical := range []bool{false, true} {
+		output, err := callContract(ctx, client, msg.msg, blockHashParam(hash, requireCanonical))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hexutil.Encode(output) != testBalanceOfResponse {
+			t.Errorf("output mismatch: have %x", output)
+		}
+		if service.block.BlockHash == nil || *service.block.BlockHash != hash || service.block.RequireCanonical != requireCanonical {
+			t.Errorf("block parameter mismatch: %+v", service.block)
+		}
+	}
+	// Nodes predating EIP-1898 reject the parameter object
+	legacy := newTestRPCClient(t, new(testLegacyCallService)).Client()
+	if _, err := callContractAtHash(ctx, legacy, msg.msg, hash, false); !IsBlockHashNotSupported(err) {
+		t.Errorf("unsupported parameter error mismatch: %v", err)
+	}
+	if _, err := callContract(ctx, legacy, msg.msg, blockNumberParam(LatestBlockNumber)); err != nil {
+		t.Errorf("block tag rejected: %v", err)
+	}
+	if _, err := balanceAtHash(ctx, legacy, token.address, hash, false); !IsBlockHashNotSupported(err) {
+		t.Errorf("unsupported balance parameter error mismatch: %v", err)
+	}
+	// Reverts are not mistaken for unsupported parameters
+	msg.SetTo(NewSeededAddress(1))
+	if _, err := callContractAtHash(ctx, client, msg.msg, hash, false); err == nil || IsBlockHashNotSupported(err) {
+		t.Errorf("revert error mismatch: %v", err)
+	}
+	// Nodes not naming the rejected argument are probed with the latest block
+	opaque := newTestRPCClient(t, new(testOpaqueCallService)).Client()
+	msg.SetTo(token)
+	if _, err := callContractAtHash(ctx, opaque, msg.msg, hash, false); !IsBlockHashNotSupported(err) {
+		t.Errorf("opaque unsupported parameter error mismatch: %v", err)
+	}
+	msg.SetData(nil)
+	if _, err := callContractAtHash(ctx, opaque, msg.msg, hash, false); err == nil || IsBlockHashNotSupported(err) {
+		t.Errorf("malformed call misreported as unsupported parameter: %v", err)
+	}
+	if _, err := balanceAtHash(ctx, opaque, token.address, hash, false); !IsBlockHashNotSupported(err) {
+		t.Errorf("opaque unsupported balance parameter error mismatch: %v", err)
+	}
+}
+
+// testOpaqueCallService mocks a non-geth node predating EIP-1898, whose invalid
+// params errors do not name the rejected argument. Calls without input count as
+// malformed.
+type testOpaqueCallService struct{}
+
+func (s *testOpaqueCallService) Call(args map[string]interface{}, block interface{}) (hexutil.Bytes, error) {
+	if _, ok := block.(string); !ok || args["input"] == nil {
+		return nil, &testRPCError{"Invalid params", invalidParamsCode}
+	}
+	return hexutil.Decode(testBalanceOfResponse)
+}
+
+func (s *testOpaqueCallService) GetBalance(account common.Address, block interface{}) (*hexutil.Big, error) {
+	if _, ok := block.(string); !ok {
+		return nil, &testRPCError{"Invalid params", invalidParamsCode}
+	}
+	return new(hexutil.Big), nil
+}
@@ -0,0 +1,130 @@
+// Copyright 2019 The bcl-chain Authors. All rights reserved.
+
+// Contains the cached chain and network identifiers of the connected node.
+
+package web3go
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// methodNotFoundCode is the JSON-RPC error code of calls to unknown methods.
+const methodNotFoundCode = -32601
+
+// methodNotSupportedError is returned when the node does not implement an RPC
+// method, e.g. eth_chainId on nodes predating EIP-695.
+type methodNotSupportedError struct {
+	method string
+	err    error
+}
+
+func (e *methodNotSupportedError) Error() string {
+	return "method " + e.method + " not supported by node: " + e.err.Error()
+}
+
+// IsMethodNotSupported reports whether the error was caused by the node not
+// implementing the called RPC method, as opposed to e.g. a connection failure.
+func IsMethodNotSupported(err error) bool {
+	_, ok := err.(*methodNotSupportedError)
+	return ok
+}
+
+// methodError wraps the error of an RPC call into a methodNotSupportedError if
+// the node does not know the method.
+func methodError(method string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if rpcErr, ok := err.(rpc.Error); ok && rpcErr.ErrorCode() == methodNotFoundCode {
+		return &methodNotSupportedError{method, err}
+	}
+	// Some nodes and proxies answer with a generic error code
+	msg := strings.ToLower(err.Error())
+	if strings.Contains(msg, "method not found") || strings.Contains(msg, "does not exist/is not available") {
+		return &methodNotSupportedError{method, err}
+	}
+	return err
+}
+
+// chainIDBackend is the part of the client API used to identify the chain.
+type chainIDBackend interface {
+	ChainID(ctx context.Context) (*big.Int, error)
+	NetworkID(ctx context.Context) (*big.Int, error)
+}
+
+// chainIDCache caches the chain and network IDs after the first successful query.
+// Failures are not cached.
+type chainIDCache struct {
+	lock      sync.Mutex
+	chainID   *big.Int
+	networkID *big.Int
+}
+
+func (c *chainIDCache) getChainID(ctx context.Context, backend chainIDBackend) (*big.Int, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.chainID == nil {
+		chainID, err := backend.ChainID(ctx)
+		if err != nil {
+			return nil, methodError("eth_chainId", err)
+		}
+		c.chainID = chainID
+	}
+	return new(big.Int).Set(c.chainID), nil
+}
+
+func (c *chainIDCache) getNetworkID(ctx context.Context, backend chainIDBackend) (*big.Int, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.networkID == nil {
+		networkID, err := backend.NetworkID(ctx)
+		if err != nil {
+			return nil, methodError("net_version", err)
+		}
+		c.networkID = networkID
+	}
+	return new(big.Int).Set(c.networkID), nil
+}
+
+func (c *chainIDCache) invalidate() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.chainID, c.networkID = nil, nil
+}
+
+// ChainID retrieves the EIP-155 chain ID of the node via eth_chainId, used to
+// sign transactions. The result is cached after the first success. Nodes
+// predating EIP-695 fail with an error IsMethodNotSupported reports.
+func (ec *EthereumClient) ChainID(ctx *Context) (_ *BigInt, err error) {
+	defer recoverError(&err)
+	chainID, err := ec.ids.getChainID(ctx.context, ec.client)
+	if err != nil {
+		return nil, err
+	}
+	return &BigInt{chainID}, nil
+}
+
+// NetworkID retrieves the network ID of the node via net_version, which may
+// differ from the chain ID. The result is cached after the first success.
+func (ec *EthereumClient) NetworkID(ctx *Context) (_ *BigInt, err error) {
+	defer recoverError(&err)
+	networkID, err := ec.ids.getNetworkID(ctx.context, ec.client)
+	if err != nil {
+		return nil, err
+	}
+	return &BigInt{networkID}, nil
+}
+
+// InvalidateChainID drops the cached chain and network IDs, so they are queried
+// again on next use, e.g. after reconnecting to a different node.
+func (ec *EthereumClient) InvalidateChainID() {
+	ec.ids.invalidate()
+}
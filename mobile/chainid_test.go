@@ -0,0 +1,93 @@
+package web3go
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// testChainIDBackend serves fixed identifiers, or an error, and counts queries.
+type testChainIDBackend struct {
+	chainID, networkID *big.Int
+	err                error
+
+	queries int
+}
+
+func (b *testChainIDBackend) ChainID(ctx context.Context) (*big.Int, error) {
+	b.queries++
+	if b.err != nil {
+		return nil, b.err
+	}
+	return b.chainID, nil
+}
+
+func (b *testChainIDBackend) NetworkID(ctx context.Context) (*big.Int, error) {
+	b.queries++
+	if b.err != nil {
+		return nil, b.err
+	}
+	return b.networkID, nil
+}
+
+// testRPCError mimics a JSON-RPC error response.
+type testRPCError struct {
+	msg  string
+	code int
+}
+
+func (e *testRPCError) Error() string  { return e.msg }
+func (e *testRPCError) ErrorCode() int { return e.code }
+
+func TestChainIDCache(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		cache   chainIDCache
+		backend = &testChainIDBackend{chainID: big.NewInt(1337), networkID: big.NewInt(5777), err: errors.New("connection refused")}
+	)
+	if _, err := cache.getChainID(ctx, backend); err == nil || IsMethodNotSupported(err) {
+		t.Fatalf("connection error mismatch: %v", err)
+	}
+	backend.err = nil
+	for i := 0; i < 2; i++ {
+		chainID, err := cache.getChainID(ctx, backend)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if chainID.Int64() != 1337 {
+			t.Errorf("chain ID mismatch: have %v, want 1337", chainID)
+		}
+		chainID.SetInt64(1)
+		networkID, err := cache.getNetworkID(ctx, backend)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if networkID.Int64() != 5777 {
+			t.Errorf("network ID mismatch: have %v, want 5777", networkID)
+		}
+	}
+	if backend.queries != 3 {
+		t.Errorf("identifiers not cached: %d queries", backend.queries)
+	}
+	cache.invalidate()
+	backend.chainID = big.NewInt(10)
+	if chainID, _ := cache.getChainID(ctx, backend); chainID.Int64() != 10 {
+		t.Errorf("chain ID not queried again after invalidation: %v", chainID)
+	}
+
+	for _, err := range []error{
+		&testRPCError{"the method eth_chainId does not exist/is not available", methodNotFoundCode},
+		&testRPCError{"Method not found", -32000},
+	} {
+		cache.invalidate()
+		backend.err = err
+		if _, err := cache.getChainID(ctx, backend); !IsMethodNotSupported(err) {
+			t.Errorf("unsupported method error mismatch: %v", err)
+		}
+	}
+	backend.err = &testRPCError{"internal error", -32603}
+	if _, err := cache.getNetworkID(ctx, backend); err == nil || IsMethodNotSupported(err) {
+		t.Errorf("internal error mismatch: %v", err)
+	}
+}
@@ -1,6 +1,8 @@
 package web3go
 
 import (
+	"errors"
+
 	"github.com/ethereum/go-ethereum/common"
 )
 
@@ -8,3 +10,31 @@ import (
 func LeftPadBytes(slice []byte, l int) []byte {
 	return common.LeftPadBytes(slice, l)
 }
+
+// Binaries represents a slice of byte slices.
+type Binaries struct{ binaries [][]byte }
+
+// NewBinaries creates an empty slice of byte slices.
+func NewBinaries() *Binaries {
+	return &Binaries{
+		binaries: make([][]byte, 0),
+	}
+}
+
+// Size returns the number of byte slices in the slice.
+func (b *Binaries) Size() int {
+	return len(b.binaries)
+}
+
+// Get returns a copy of the byte slice at the given index from the slice.
+func (b *Binaries) Get(index int) (binary []byte, _ error) {
+	if index < 0 || index >= len(b.binaries) {
+		return nil, errors.New("index out of bounds")
+	}
+	return common.CopyBytes(b.binaries[index]), nil
+}
+
+// Append adds a copy of the byte slice to the end of the slice.
+func (b *Binaries) Append(binary []byte) {
+	b.binaries = append(b.binaries, common.CopyBytes(binary))
+}
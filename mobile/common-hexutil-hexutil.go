@@ -1,17 +1,80 @@
 package web3go
 
 import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
-// Decode ...
-func Decode(input string) ([]byte, error) {
-	return hexutil.Decode(input)
+// Decode decodes a hex string, with or without 0x prefix. Odd length input and
+// non-hex characters are rejected, reporting the position of the offending
+// character in the input.
+func Decode(input string) (_ []byte, err error) {
+	defer recoverError(&err)
+	return decodeHex(input)
 }
 
-// MustDecode ...
+// MustDecode decodes a hex string like Decode. It panics for invalid input and is
+// meant for tests and constants only.
 func MustDecode(s string) []byte {
-	return hexutil.MustDecode(s)
+	b, err := decodeHex(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Has0xPrefix reports whether the string starts with 0x or 0X.
+func Has0xPrefix(s string) bool {
+	return len(s) >= 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X')
+}
+
+// IsHex reports whether the string is valid input for Decode: an even number of
+// hex digits, with or without 0x prefix.
+func IsHex(s string) bool {
+	_, err := decodeHex(s)
+	return err == nil
+}
+
+// IsHexAddress reports whether the string is a hex encoded address, with or
+// without 0x prefix. The EIP-55 checksum is not verified.
+func IsHexAddress(s string) bool {
+	return common.IsHexAddress(s)
+}
+
+// decodeHex decodes a hex string with optional 0x prefix, reporting the position
+// of the first invalid character within the whole input.
+func decodeHex(s string) ([]byte, error) {
+	offset := 0
+	if Has0xPrefix(s) {
+		offset = 2
+	}
+	digits := s[offset:]
+	for i := 0; i < len(digits); i++ {
+		if c := digits[i]; !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return nil, fmt.Errorf("invalid hex character %q at position %d", c, offset+i)
+		}
+	}
+	if len(digits)%2 != 0 {
+		return nil, fmt.Errorf("odd length hex string: %d digits", len(digits))
+	}
+	b := make([]byte, len(digits)/2)
+	for i := range b {
+		b[i] = hexNibble(digits[2*i])<<4 | hexNibble(digits[2*i+1])
+	}
+	return b, nil
+}
+
+// hexNibble returns the value of a valid hex digit.
+func hexNibble(c byte) byte {
+	switch {
+	case c >= 'a':
+		return c - 'a' + 10
+	case c >= 'A':
+		return c - 'A' + 10
+	}
+	return c - '0'
 }
 
 // Encode ...
@@ -32,7 +95,8 @@ func Encode(b []byte) string {
 //}
 
 // DecodeBig ...
-func DecodeBig(s string) (*BigInt, error) {
+func DecodeBig(s string) (_ *BigInt, err error) {
+	defer recoverError(&err)
 	bigint, err := hexutil.DecodeBig(s)
 	if err == nil {
 		return &BigInt{bigint: bigint}, nil
@@ -0,0 +1,91 @@
+package web3go
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []byte
+	}{
+		{"0x", []byte{}},
+		{"", []byte{}},
+		{"0x0102ff", []byte{0x01, 0x02, 0xff}},
+		{"0102FF", []byte{0x01, 0x02, 0xff}},
+		{"0XaBcD", []byte{0xab, 0xcd}},
+	}
+	for _, tt := range tests {
+		have, err := Decode(tt.input)
+		if err != nil {
+			t.Errorf("%q: %v", tt.input, err)
+			continue
+		}
+		if !bytes.Equal(have, tt.want) {
+			t.Errorf("%q: have %x, want %x", tt.input, have, tt.want)
+		}
+		if !IsHex(tt.input) {
+			t.Errorf("%q: not reported as hex", tt.input)
+		}
+	}
+	invalid := []struct {
+		input string
+		err   string
+	}{
+		{"0x123", "odd length"},
+		{"abc", "odd length"},
+		{"0x12g4", "position 4"},
+		{"12g4", "position 2"},
+		{"0x 1", "position 2"},
+		{"0x0x12", "position 3"},
+	}
+	for _, tt := range invalid {
+		if _, err := Decode(tt.input); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%q: error mismatch: have %v, want %q", tt.input, err, tt.err)
+		}
+		if IsHex(tt.input) {
+			t.Errorf("%q: reported as hex", tt.input)
+		}
+	}
+	if !bytes.Equal(MustDecode("0x01"), []byte{1}) {
+		t.Error("MustDecode mismatch")
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("MustDecode did not panic on invalid input")
+			}
+		}()
+		MustDecode("0xzz")
+	}()
+}
+
+func TestHexValidation(t *testing.T) {
+	prefixes := map[string]bool{"0x12": true, "0X12": true, "12": false, "0": false, "": false, "x12": false}
+	for input, want := range prefixes {
+		if Has0xPrefix(input) != want {
+			t.Errorf("%q: prefix mismatch: want %v", input, want)
+		}
+	}
+	addresses := map[string]bool{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed":   true,
+		"5aaeb6053f3e94c9b9a09f33669435e7ef1beaed":     true,
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA":     false,
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeg":   false,
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed00": false,
+	}
+	for input, want := range addresses {
+		if IsHexAddress(input) != want {
+			t.Errorf("%q: address mismatch: want %v", input, want)
+		}
+	}
+	// Hash and address parsing report the same errors
+	if _, err := NewHashFromHex("0x" + strings.Repeat("0", 63) + "g"); err == nil || !strings.Contains(err.Error(), "position 65") {
+		t.Errorf("hash error mismatch: %v", err)
+	}
+	if _, err := NewTransactionFromRawHex("0xf8zz"); err == nil || !strings.Contains(err.Error(), "position 4") {
+		t.Errorf("raw transaction error mismatch: %v", err)
+	}
+}
@@ -19,7 +19,8 @@
 package web3go
 
 import (
-	"encoding/hex"
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -59,21 +60,21 @@ func (h *Hash) SetBytes(hash []byte) error {
 	return nil
 }
 
-// GetBytes retrieves the byte representation of the hash.
+// GetBytes retrieves a copy of the byte representation of the hash.
 func (h *Hash) GetBytes() []byte {
-	return h.hash[:]
+	return common.CopyBytes(h.hash[:])
 }
 
 // SetHex sets the specified hex string as the hash value.
 func (h *Hash) SetHex(hash string) error {
-	hash = strings.ToLower(hash)
-	if len(hash) >= 2 && hash[:2] == "0x" {
-		hash = hash[2:]
+	digits := hash
+	if Has0xPrefix(digits) {
+		digits = digits[2:]
 	}
-	if length := len(hash); length != 2*common.HashLength {
+	if length := len(digits); length != 2*common.HashLength {
 		return fmt.Errorf("invalid hash hex length: %v != %v", length, 2*common.HashLength)
 	}
-	bin, err := hex.DecodeString(hash)
+	bin, err := decodeHex(hash)
 	if err != nil {
 		return err
 	}
@@ -86,6 +87,21 @@ func (h *Hash) GetHex() string {
 	return h.hash.Hex()
 }
 
+// Equals reports whether two hashes are the same. Two nil hashes are equal, a nil
+// and a non-nil one are not.
+func (h *Hash) Equals(other *Hash) bool {
+	if h == nil || other == nil {
+		return h == other
+	}
+	return h.hash == other.hash
+}
+
+// IsZero reports whether the hash is all zeroes. A nil hash is not considered
+// zero.
+func (h *Hash) IsZero() bool {
+	return h != nil && h.hash == (common.Hash{})
+}
+
 // Hashes represents a slice of hashes.
 type Hashes struct{ hashes []common.Hash }
 
@@ -128,6 +144,42 @@ func (h *Hashes) Append(hash *Hash) {
 	h.hashes = append(h.hashes, hash.hash)
 }
 
+// Contains reports whether the slice holds the given hash.
+func (h *Hashes) Contains(hash *Hash) bool {
+	if hash == nil {
+		return false
+	}
+	for _, have := range h.hashes {
+		if have == hash.hash {
+			return true
+		}
+	}
+	return false
+}
+
+// NewHashesFromJSON parses a slice of hashes from a JSON array of hex strings.
+func NewHashesFromJSON(data string) (_ *Hashes, err error) {
+	defer recoverError(&err)
+	h := NewHashesEmpty()
+	if err := json.Unmarshal([]byte(data), &h.hashes); err != nil {
+		return nil, err
+	}
+	if h.hashes == nil {
+		h.hashes = make([]common.Hash, 0)
+	}
+	return h, nil
+}
+
+// EncodeJSON encodes a slice of hashes into a JSON array of hex strings.
+func (h *Hashes) EncodeJSON() (_ string, err error) {
+	defer recoverError(&err)
+	if h.hashes == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(h.hashes)
+	return string(data), err
+}
+
 // Address represents the 20 byte address of an Ethereum account.
 type Address struct {
 	address common.Address
@@ -142,7 +194,9 @@ func NewAddressFromBytes(binary []byte) (address *Address, _ error) {
 	return a, nil
 }
 
-// NewAddressFromHex converts a hex string to a address value.
+// NewAddressFromHex converts a hex string to a address value. Mixed-case input
+// must carry a valid EIP-55 checksum, while all-lowercase or all-uppercase input
+// is accepted without one.
 func NewAddressFromHex(hex string) (address *Address, _ error) {
 	a := new(Address)
 	if err := a.SetHex(hex); err != nil {
@@ -160,9 +214,39 @@ func (a *Address) SetBytes(address []byte) error {
 	return nil
 }
 
-// GetBytes retrieves the byte representation of the address.
+// GetBytes retrieves a copy of the byte representation of the address.
 func (a *Address) GetBytes() []byte {
-	return a.address[:]
+	return common.CopyBytes(a.address[:])
+}
+
+// Equals reports whether two addresses are the same, irrespective of the casing
+// they were parsed from. Two nil addresses are equal, a nil and a non-nil one are
+// not.
+func (a *Address) Equals(other *Address) bool {
+	if a == nil || other == nil {
+		return a == other
+	}
+	return a.address == other.address
+}
+
+// IsZero reports whether the address is the zero address. A nil address is not
+// considered zero.
+func (a *Address) IsZero() bool {
+	return a != nil && a.address == (common.Address{})
+}
+
+// Compare orders two addresses by their bytes, returning -1, 0 or +1. A nil
+// address sorts before any other address.
+func (a *Address) Compare(other *Address) int {
+	switch {
+	case a == nil && other == nil:
+		return 0
+	case a == nil:
+		return -1
+	case other == nil:
+		return 1
+	}
+	return bytes.Compare(a.address[:], other.address[:])
 }
 
 // GetHash retrives the Hash representation of the address.
@@ -171,28 +255,42 @@ func (a *Address) GetHash() *Hash {
 	return &Hash{h}
 }
 
-// SetHex sets the specified hex string as the address value.
+// SetHex sets the specified hex string as the address value. Mixed-case input
+// must carry a valid EIP-55 checksum, see NewAddressFromHex.
 func (a *Address) SetHex(address string) error {
-	address = strings.ToLower(address)
-	if len(address) >= 2 && address[:2] == "0x" {
+	if Has0xPrefix(address) {
 		address = address[2:]
 	}
 	if length := len(address); length != 2*common.AddressLength {
 		return fmt.Errorf("invalid address hex length: %v != %v", length, 2*common.AddressLength)
 	}
-	bin, err := hex.DecodeString(address)
+	bin, err := decodeHex(address)
 	if err != nil {
 		return err
 	}
-	copy(a.address[:], bin)
+	var addr common.Address
+	copy(addr[:], bin)
+
+	lower, upper := strings.ToLower(address), strings.ToUpper(address)
+	if address != lower && address != upper && "0x"+address != addr.Hex() {
+		return fmt.Errorf("invalid address checksum: 0x%s, want %s", address, addr.Hex())
+	}
+	a.address = addr
 	return nil
 }
 
-// GetHex retrieves the hex string representation of the address.
+// GetHex retrieves the hex string representation of the address, in its EIP-55
+// checksummed form.
 func (a *Address) GetHex() string {
 	return a.address.Hex()
 }
 
+// GetChecksumHex retrieves the EIP-55 checksummed hex string representation of
+// the address.
+func (a *Address) GetChecksumHex() string {
+	return a.address.Hex()
+}
+
 // Addresses represents a slice of addresses.
 type Addresses struct{ addresses []common.Address }
 
@@ -234,3 +332,40 @@ func (a *Addresses) Set(index int, address *Address) error {
 func (a *Addresses) Append(address *Address) {
 	a.addresses = append(a.addresses, address.address)
 }
+
+// Contains reports whether the slice holds the given address.
+func (a *Addresses) Contains(address *Address) bool {
+	if address == nil {
+		return false
+	}
+	for _, have := range a.addresses {
+		if have == address.address {
+			return true
+		}
+	}
+	return false
+}
+
+// NewAddressesFromJSON parses a slice of addresses from a JSON array of hex
+// strings.
+func NewAddressesFromJSON(data string) (_ *Addresses, err error) {
+	defer recoverError(&err)
+	a := NewAddressesEmpty()
+	if err := json.Unmarshal([]byte(data), &a.addresses); err != nil {
+		return nil, err
+	}
+	if a.addresses == nil {
+		a.addresses = make([]common.Address, 0)
+	}
+	return a, nil
+}
+
+// EncodeJSON encodes a slice of addresses into a JSON array of hex strings.
+func (a *Addresses) EncodeJSON() (_ string, err error) {
+	defer recoverError(&err)
+	if a.addresses == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(a.addresses)
+	return string(data), err
+}
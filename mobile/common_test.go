@@ -0,0 +1,224 @@
+package web3go
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestAddressChecksum(t *testing.T) {
+	// Test vectors from EIP-55
+	vectors := []string{
+		// All caps
+		"0x52908400098527886E0F7030069857D2E4169EE7",
+		"0x8617E340B3D01FA5F11F306F4090FD50E238070D",
+		// All Lower
+		"0xde709f2102306220921060314715629080e2fb77",
+		"0x27b1fdb04752bbc536007a920d24acb045561c26",
+		// Normal
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+	}
+	for _, vector := range vectors {
+		addr, err := NewAddressFromHex(vector)
+		if err != nil {
+			t.Errorf("%s: %v", vector, err)
+			continue
+		}
+		if have := addr.GetChecksumHex(); have != vector {
+			t.Errorf("checksum mismatch: have %s, want %s", have, vector)
+		}
+		// Case-insensitive forms are accepted without a checksum
+		for _, s := range []string{strings.ToLower(vector), "0x" + strings.ToUpper(vector[2:]), vector[2:]} {
+			if _, err := NewAddressFromHex(s); err != nil {
+				t.Errorf("%s: %v", s, err)
+			}
+		}
+	}
+	invalid := []string{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD",   // Checksum typo
+		"0x5AAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",   // Checksum typo
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA",     // Too short
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed00", // Too long
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeg",   // Non-hex character
+		"",
+	}
+	for _, s := range invalid {
+		if _, err := NewAddressFromHex(s); err == nil {
+			t.Errorf("%q: expected error", s)
+		}
+	}
+}
+
+func TestAddressEquality(t *testing.T) {
+	parse := func(s string) *Address {
+		addr, err := NewAddressFromHex(s)
+		if err != nil {
+			t.Fatalf("%s: %v", s, err)
+		}
+		return addr
+	}
+	var (
+		checksum = parse("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+		lower    = parse("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+		upper    = parse("0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED")
+		other    = parse("0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359")
+		zero     = parse("0x0000000000000000000000000000000000000000")
+	)
+	tests := []struct {
+		a, b    *Address
+		equals  bool
+		compare int
+	}{
+		{checksum, lower, true, 0},
+		{lower, upper, true, 0},
+		{checksum, other, false, -1},
+		{other, checksum, false, 1},
+		{zero, checksum, false, -1},
+		{zero, new(Address), true, 0},
+		{nil, nil, true, 0},
+		{nil, zero, false, -1},
+		{checksum, nil, false, 1},
+	}
+	for i, tt := range tests {
+		if have := tt.a.Equals(tt.b); have != tt.equals {
+			t.Errorf("test %d: equality mismatch: have %v, want %v", i, have, tt.equals)
+		}
+		if have := tt.a.Compare(tt.b); have != tt.compare {
+			t.Errorf("test %d: comparison mismatch: have %d, want %d", i, have, tt.compare)
+		}
+	}
+	zeros := []struct {
+		addr *Address
+		zero bool
+	}{
+		{zero, true},
+		{new(Address), true},
+		{checksum, false},
+		{nil, false},
+	}
+	for i, tt := range zeros {
+		if have := tt.addr.IsZero(); have != tt.zero {
+			t.Errorf("test %d: zero mismatch: have %v, want %v", i, have, tt.zero)
+		}
+	}
+	// Mutating the returned bytes must not corrupt the address
+	checksum.GetBytes()[0] = 0xff
+	if !checksum.Equals(lower) {
+		t.Error("GetBytes aliases the address")
+	}
+}
+
+func TestHashConstructors(t *testing.T) {
+	const hex = "0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b"
+
+	fromHex, err := NewHashFromHex(hex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromBytes, err := NewHashFromBytes(fromHex.GetBytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	upper, err := NewHashFromHex(strings.ToUpper(hex[2:]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fromHex.Equals(fromBytes) || !fromHex.Equals(upper) || fromBytes.GetHex() != hex {
+		t.Errorf("hash mismatch: have %s and %s, want %s", fromHex.GetHex(), fromBytes.GetHex(), hex)
+	}
+	// Mutating the returned bytes must not corrupt the hash
+	fromHex.GetBytes()[0] = 0xff
+	if fromHex.GetHex() != hex {
+		t.Error("GetBytes aliases the hash")
+	}
+	invalidHex := []struct {
+		input string
+		err   string
+	}{
+		{hex[:64], "62"},
+		{hex + "00", "66"},
+		{"", "0"},
+		{hex[:65] + "g", "invalid hex character"},
+	}
+	for _, tt := range invalidHex {
+		if _, err := NewHashFromHex(tt.input); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%q: error mismatch: have %v, want %q", tt.input, err, tt.err)
+		}
+	}
+	for _, n := range []int{0, 20, 31, 33} {
+		if _, err := NewHashFromBytes(make([]byte, n)); err == nil || !strings.Contains(err.Error(), fmt.Sprint(n)) {
+			t.Errorf("%d bytes: error mismatch: %v", n, err)
+		}
+	}
+	zero, _ := NewHashFromBytes(make([]byte, 32))
+	tests := []struct {
+		a, b   *Hash
+		equals bool
+	}{
+		{fromHex, fromBytes, true},
+		{fromHex, zero, false},
+		{zero, new(Hash), true},
+		{nil, nil, true},
+		{nil, zero, false},
+		{fromHex, nil, false},
+	}
+	for i, tt := range tests {
+		if have := tt.a.Equals(tt.b); have != tt.equals {
+			t.Errorf("test %d: equality mismatch: have %v, want %v", i, have, tt.equals)
+		}
+	}
+	if !zero.IsZero() || fromHex.IsZero() || (*Hash)(nil).IsZero() {
+		t.Error("zero check mismatch")
+	}
+}
+
+func TestCollections(t *testing.T) {
+	addrs, err := NewAddressesFromJSON(`["0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed","0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359","0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addrs.Size() != 3 {
+		t.Errorf("duplicates dropped: size %d, want 3", addrs.Size())
+	}
+	member, _ := NewAddressFromHex("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	stranger, _ := NewAddressFromHex("0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB")
+	if !addrs.Contains(member) || addrs.Contains(stranger) || addrs.Contains(nil) {
+		t.Error("address membership mismatch")
+	}
+	addrs.Append(stranger)
+	if _, err := addrs.Get(4); err == nil {
+		t.Error("expected error for out of bounds index")
+	}
+	data, err := addrs.EncodeJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `["0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed","0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359","0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed","0xdbf03b407c01e7cd3cbea99509d93f8dddc8c6fb"]`
+	if data != want {
+		t.Errorf("address JSON mismatch:\nhave %s\nwant %s", data, want)
+	}
+	if _, err := NewAddressesFromJSON(`["0x5aaeb6053f3e94c9b9a09f33669435e7ef1bea"]`); err == nil {
+		t.Error("expected error for short address")
+	}
+
+	hashes, err := NewHashesFromJSON(`["0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b"]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, _ := hashes.Get(0)
+	if !hashes.Contains(first) || hashes.Contains(new(Hash)) || hashes.Contains(nil) {
+		t.Error("hash membership mismatch")
+	}
+	if data, _ := NewHashesEmpty().EncodeJSON(); data != "[]" {
+		t.Errorf("empty hashes JSON mismatch: have %s", data)
+	}
+	if data, _ := (&Hashes{}).EncodeJSON(); data != "[]" {
+		t.Errorf("nil hashes JSON mismatch: have %s", data)
+	}
+	if dec, err := NewHashesFromJSON("null"); err != nil || dec.Size() != 0 {
+		t.Errorf("null hashes decoding mismatch: %v", err)
+	}
+}
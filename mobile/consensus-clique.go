@@ -0,0 +1,41 @@
+package web3go
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/clique"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// cliqueExtraSeal is the number of extra-data suffix bytes reserved for the
+// signer seal in clique headers.
+const cliqueExtraSeal = crypto.SignatureLength
+
+// GetSealHash returns the hash of a clique header prior to it being sealed, i.e.
+// without the seal in the extra-data. It returns nil if the extra-data is too
+// short to contain a seal.
+func (h *Header) GetSealHash() *Hash {
+	defer recoverZero()
+	if len(h.header.Extra) < cliqueExtraSeal {
+		return nil
+	}
+	return &Hash{clique.SealHash(h.header)}
+}
+
+// RecoverCliqueSigner returns the address of the authority that sealed a clique
+// (proof-of-authority) header, recovered from the signature in its extra-data.
+func (h *Header) RecoverCliqueSigner() (_ *Address, err error) {
+	defer recoverError(&err)
+	if len(h.header.Extra) < cliqueExtraSeal {
+		return nil, errors.New("extra-data 65 byte signature suffix missing")
+	}
+	signature := h.header.Extra[len(h.header.Extra)-cliqueExtraSeal:]
+	pubkey, err := crypto.Ecrecover(clique.SealHash(h.header).Bytes(), signature)
+	if err != nil {
+		return nil, err
+	}
+	var signer common.Address
+	copy(signer[:], crypto.Keccak256(pubkey[1:])[12:])
+	return &Address{signer}, nil
+}
@@ -0,0 +1,59 @@
+package web3go
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/clique"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Generated clique header of block 1000 sealed by testKeyHex, RLP encoded. The
+// recovered signer is cross-checked with the clique engine of go-ethereum, so
+// the test does not rely on the recovery code under test alone.
+const testCliqueHeader = "0xf9025aa088df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944ba01dcc4de8dec75d7aab85b567b6ccd41ad312451b948a7413f0a142fd40d49347940000000000000000000000000000000000000000a056e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421a056e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421a056e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421b9010000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000028203e88401c9c38080846553f100b86177656233676f20636c69717565000000000000000000000000000000000000001d08bae108086742d080ec7656a98e082f24128d98c03433b19d0ad3bc1a4c8518d48c888f1ecc11138d87295ca20e832ba0af1a1cef5278e53db8dad4fc4f6e01a0000000000000000000000000000000000000000000000000000000000000000088000000000000000007"
+
+func TestRecoverCliqueSigner(t *testing.T) {
+	header, err := NewHeaderFromRLP(hexutil.MustDecode(testCliqueHeader))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash := header.GetSealHash(); hash == nil || hash.GetHex() != "0x74cbd1b1d065e0008b29493039a79a23dd015ae5b1f8efe26206073d75cc4da0" {
+		t.Errorf("seal hash mismatch: have %v", hash)
+	}
+	signer, err := header.RecoverCliqueSigner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signer.GetHex() != testAddress.GetHex() {
+		t.Errorf("signer mismatch: have %s, want %s", signer.GetHex(), testAddress.GetHex())
+	}
+	engine := clique.New(&params.CliqueConfig{Period: 15, Epoch: 30000}, rawdb.NewMemoryDatabase())
+	if author, err := engine.Author(header.header); err != nil || author != signer.address {
+		t.Errorf("signer differs from clique engine: have %s, engine %x (%v)", signer.GetHex(), author, err)
+	}
+	// Tampering with the header changes the recovered signer
+	header.SetTime(header.GetTime() + 1)
+	tampered, err := header.RecoverCliqueSigner()
+	if err == nil && tampered.GetHex() == testAddress.GetHex() {
+		t.Error("tampered header recovered the original signer")
+	}
+	if author, _ := engine.Author(header.header); err == nil && author != tampered.address {
+		t.Errorf("tampered signer differs from clique engine: have %s, engine %x", tampered.GetHex(), author)
+	}
+	header.SetExtra(make([]byte, 64))
+	if header.GetSealHash() != nil {
+		t.Error("expected nil seal hash for short extra-data")
+	}
+	if _, err := header.RecoverCliqueSigner(); err == nil {
+		t.Error("expected error for short extra-data")
+	}
+	// An invalid recovery ID yields no key
+	extra := make([]byte, 32+65)
+	extra[len(extra)-1] = 5
+	header.SetExtra(extra)
+	if _, err := header.RecoverCliqueSigner(); err == nil {
+		t.Error("expected error for invalid signature")
+	}
+}
@@ -0,0 +1,85 @@
+package web3go
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// allowedFutureBlockTime is the default number of seconds a header timestamp may
+// be ahead of the local clock.
+const allowedFutureBlockTime = 15
+
+var (
+	errHeaderNumberMissing     = errors.New("header number missing")
+	errHeaderDifficultyMissing = errors.New("header difficulty missing")
+	errHeaderGasUsedExceeded   = errors.New("header gas used exceeds gas limit")
+	errHeaderFutureBlock       = errors.New("header timestamp too far in the future")
+)
+
+// ValidateBasic runs the intrinsic sanity checks on the header, allowing its
+// timestamp to be up to 15 seconds ahead of the local clock. See
+// ValidateBasicWithDrift.
+func (h *Header) ValidateBasic() error {
+	return h.ValidateBasicWithDrift(allowedFutureBlockTime)
+}
+
+// ValidateBasicWithDrift runs the intrinsic sanity checks on the header, without
+// looking at its parent or the state:
+//   - the number and difficulty are set
+//   - the extra-data is at most 32 bytes, plus a 65 byte clique seal
+//   - the gas limit is within the protocol bounds and not exceeded by gas used
+//   - the timestamp is at most maxDrift seconds ahead of the local clock
+//
+// Each failing check returns a distinct error message.
+func (h *Header) ValidateBasicWithDrift(maxDrift int64) (err error) {
+	defer recoverError(&err)
+	header := h.header
+
+	if header.Number == nil {
+		return errHeaderNumberMissing
+	}
+	if header.Difficulty == nil {
+		return errHeaderDifficultyMissing
+	}
+	if limit := params.MaximumExtraDataSize + cliqueExtraSeal; uint64(len(header.Extra)) > limit {
+		return fmt.Errorf("header extra-data too long: %d > %d", len(header.Extra), limit)
+	}
+	if header.GasLimit < params.MinGasLimit || header.GasLimit > params.MaxGasLimit {
+		return fmt.Errorf("header gas limit out of bounds: %d not in [%d, %d]", header.GasLimit, params.MinGasLimit, params.MaxGasLimit)
+	}
+	if header.GasUsed > header.GasLimit {
+		return errHeaderGasUsedExceeded
+	}
+	if header.Time > uint64(time.Now().Unix()+maxDrift) {
+		return errHeaderFutureBlock
+	}
+	return nil
+}
+
+// ValidateChain checks that the headers form a contiguous chain: every header is
+// the child of the previous one, numbered one higher and not older. The index and
+// reason of the first violation are reported.
+func (h *Headers) ValidateChain() (err error) {
+	defer recoverError(&err)
+	for i := 1; i < len(h.headers); i++ {
+		parent, header := h.headers[i-1], h.headers[i]
+		if header.ParentHash != parent.Hash() {
+			return fmt.Errorf("header %d: parent hash mismatch: have %x, want %x", i, header.ParentHash, parent.Hash())
+		}
+		if parent.Number == nil || header.Number == nil {
+			return fmt.Errorf("header %d: %v", i, errHeaderNumberMissing)
+		}
+		if new(big.Int).Sub(header.Number, parent.Number).Cmp(common.Big1) != 0 {
+			return fmt.Errorf("header %d: non-contiguous number: have %v, want %v", i, header.Number, new(big.Int).Add(parent.Number, common.Big1))
+		}
+		if header.Time < parent.Time {
+			return fmt.Errorf("header %d: timestamp older than parent: have %d, want >= %d", i, header.Time, parent.Time)
+		}
+	}
+	return nil
+}
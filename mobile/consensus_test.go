@@ -0,0 +1,92 @@
+package web3go
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHeaderValidateBasic(t *testing.T) {
+	valid := func() *Header {
+		header := NewEmptyHeader()
+		header.SetNumber(1)
+		header.SetGasLimit(30000000)
+		header.SetGasUsed(21000)
+		header.SetTime(time.Now().Unix())
+		header.SetExtra(make([]byte, 32+65))
+		return header
+	}
+	if err := valid().ValidateBasic(); err != nil {
+		t.Fatalf("valid header rejected: %v", err)
+	}
+	tests := []struct {
+		mutate func(*Header)
+		err    string
+	}{
+		{func(h *Header) { h.header.Number = nil }, "header number missing"},
+		{func(h *Header) { h.header.Difficulty = nil }, "header difficulty missing"},
+		{func(h *Header) { h.SetExtra(make([]byte, 32+65+1)) }, "header extra-data too long"},
+		{func(h *Header) { h.SetGasLimit(4999) }, "header gas limit out of bounds"},
+		{func(h *Header) { h.SetGasLimit(-1) }, "header gas limit out of bounds"},
+		{func(h *Header) { h.SetGasUsed(30000001) }, "header gas used exceeds gas limit"},
+		{func(h *Header) { h.SetTime(time.Now().Unix() + 60) }, "header timestamp too far in the future"},
+	}
+	for i, tt := range tests {
+		header := valid()
+		tt.mutate(header)
+		if err := header.ValidateBasic(); err == nil || !strings.HasPrefix(err.Error(), tt.err) {
+			t.Errorf("test %d: error mismatch: have %v, want %q", i, err, tt.err)
+		}
+	}
+	header := valid()
+	header.SetTime(time.Now().Unix() + 60)
+	if err := header.ValidateBasicWithDrift(120); err != nil {
+		t.Errorf("header within drift rejected: %v", err)
+	}
+}
+
+func TestHeadersValidateChain(t *testing.T) {
+	chain := func() *Headers {
+		headers := NewHeaders()
+		for i := int64(0); i < 5; i++ {
+			header := NewEmptyHeader()
+			header.SetNumber(100 + i)
+			header.SetTime(1700000000 + 12*i)
+			if i > 0 {
+				parent, _ := headers.GetLast()
+				header.SetParentHash(parent.GetHash())
+			}
+			headers.Append(header)
+		}
+		return headers
+	}
+	headers := chain()
+	if err := headers.ValidateChain(); err != nil {
+		t.Fatal(err)
+	}
+	if first, _ := headers.GetFirst(); first.GetNumberBig().GetInt64() != 100 {
+		t.Errorf("first header mismatch: have %v", first.GetNumberBig())
+	}
+	if last, _ := headers.GetLast(); last.GetNumberBig().GetInt64() != 104 {
+		t.Errorf("last header mismatch: have %v", last.GetNumberBig())
+	}
+	if _, err := NewHeaders().GetLast(); err == nil {
+		t.Error("expected error for empty headers")
+	}
+	tests := []struct {
+		mutate func(*Headers)
+		err    string
+	}{
+		{func(h *Headers) { h.headers[3].ParentHash = h.headers[1].Hash() }, "header 3: parent hash mismatch"},
+		{func(h *Headers) { h.headers = append(h.headers[:2], h.headers[3:]...) }, "header 2: parent hash mismatch"},
+		{func(h *Headers) { h.headers[0].Number.SetInt64(50); h.headers[1].ParentHash = h.headers[0].Hash() }, "header 1: non-contiguous number"},
+		{func(h *Headers) { h.headers[4].Time = 1 }, "header 4: timestamp older than parent"},
+	}
+	for i, tt := range tests {
+		headers := chain()
+		tt.mutate(headers)
+		if err := headers.ValidateChain(); err == nil || !strings.HasPrefix(err.Error(), tt.err) {
+			t.Errorf("test %d: error mismatch: have %v, want %q", i, err, tt.err)
+		}
+	}
+}
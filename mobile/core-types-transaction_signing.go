@@ -1,16 +1,24 @@
 package web3go
 
 import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
 // SignTx ...
-func SignTx(wtx *Transaction, ws *Signer2, wprv *PrivateKey) (*Transaction, error) {
+func SignTx(wtx *Transaction, ws *Signer2, wprv *PrivateKey) (_ *Transaction, err error) {
+	defer recoverError(&err)
 	tx := wtx.tx
 	s := ws.signer
-	prv := wprv.privateKey
+	prv, err := wprv.ecdsaKey()
+	if err != nil {
+		return nil, err
+	}
 
-	tx, err := types.SignTx(tx, s, prv)
+	tx, err = types.SignTx(tx, s, prv)
 	if err == nil {
 		return &Transaction{tx: tx}, nil
 	}
@@ -18,7 +26,8 @@ func SignTx(wtx *Transaction, ws *Signer2, wprv *PrivateKey) (*Transaction, erro
 }
 
 // Sender ...
-func Sender(ws *Signer2, wtx *Transaction) (*Address, error) {
+func Sender(ws *Signer2, wtx *Transaction) (_ *Address, err error) {
+	defer recoverError(&err)
 	s := ws.signer
 	tx := wtx.tx
 
@@ -43,3 +52,66 @@ type HomesteadSigner struct {
 func NewHomesteadSigner() *Signer2 {
 	return &Signer2{types.HomesteadSigner{}}
 }
+
+// SignTransaction signs an unsigned transaction with the given hex encoded
+// secp256k1 private key, see SignTransactionWithHexKey.
+func SignTransaction(tx *Transaction, privKeyHex string, chainID *BigInt) (*Transaction, error) {
+	return SignTransactionWithHexKey(tx, privKeyHex, chainID, false)
+}
+
+// SignTransactionWithHexKey signs the transaction with the given hex encoded
+// secp256k1 private key, picking the signer matching the transaction type and
+// chain ID. Already signed transactions are rejected unless force is set.
+//
+// The key is only held for the duration of the call and is never logged.
+func SignTransactionWithHexKey(tx *Transaction, privKeyHex string, chainID *BigInt, force bool) (_ *Transaction, err error) {
+	defer recoverError(&err)
+	if !force && isSigned(tx.tx) {
+		return nil, errors.New("transaction already signed")
+	}
+	signer, err := transactionSigner(tx.tx, chainID)
+	if err != nil {
+		return nil, err
+	}
+	key, err := parsePrivateKeyHex(privKeyHex)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroKey(key)
+
+	signed, err := types.SignTx(tx.tx, signer, key)
+	if err != nil {
+		return nil, err
+	}
+	return &Transaction{tx: signed}, nil
+}
+
+// transactionSigner returns the signer for the given transaction. Legacy
+// transactions without a chain ID use the Homestead rules, typed ones require the
+// chain ID they were created for.
+func transactionSigner(tx *types.Transaction, chainID *BigInt) (types.Signer, error) {
+	if chainID == nil {
+		if tx.Type() != types.LegacyTxType {
+			return nil, errors.New("chainID required for typed transactions")
+		}
+		return types.HomesteadSigner{}, nil
+	}
+	if tx.Type() != types.LegacyTxType && tx.ChainId().Cmp(chainID.bigint) != 0 {
+		return nil, fmt.Errorf("chainID mismatch: transaction has %v, want %v", tx.ChainId(), chainID.bigint)
+	}
+	return types.LatestSignerForChainID(chainID.bigint), nil
+}
+
+// isSigned reports whether the transaction carries a signature.
+func isSigned(tx *types.Transaction) bool {
+	_, r, s := tx.RawSignatureValues()
+	return r.Sign() != 0 || s.Sign() != 0
+}
+
+// zeroKey overwrites the private key scalar in memory.
+func zeroKey(key *ecdsa.PrivateKey) {
+	b := key.D.Bits()
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -7,7 +7,14 @@ package web3go
 
 import (
 	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
@@ -20,34 +27,120 @@ type PublicKey struct {
 	publicKey *ecdsa.PublicKey
 }
 
-// Public ...
+// Public returns the public key of the private key, or nil once it was cleared.
 func (priv *PrivateKey) Public() *PublicKey {
 	privateKey := priv.privateKey
+	if privateKey == nil {
+		return nil
+	}
 	return &PublicKey{&privateKey.PublicKey}
 }
 
-// PrivateKey ...
+// PrivateKey is a secp256k1 private key. Call Clear as soon as it is no longer
+// needed, to not keep the secret in memory until garbage collection.
 type PrivateKey struct {
 	privateKey *ecdsa.PrivateKey
 }
 
-// Keccak256 ...
-// TODO: input type of original Keccak256 is ...[]byte.
-//       K changed to []byte.
-//       have to check if this modification is fine or not.
+// ErrKeyCleared is returned when using a private key after its Clear method was
+// called.
+var ErrKeyCleared = errors.New("private key cleared")
+
+// Clear overwrites the private key scalar in memory. All signing functions
+// taking the key are safe to call before Clear and fail with ErrKeyCleared
+// afterwards. Clearing a key twice is a no-op.
+func (priv *PrivateKey) Clear() {
+	if priv.privateKey != nil {
+		zeroKey(priv.privateKey)
+		priv.privateKey = nil
+	}
+}
+
+// ecdsaKey returns the wrapped key, failing if it was cleared.
+func (priv *PrivateKey) ecdsaKey() (*ecdsa.PrivateKey, error) {
+	if priv.privateKey == nil {
+		return nil, ErrKeyCleared
+	}
+	return priv.privateKey, nil
+}
+
+// NewPrivateKeyFromBytes creates a private key from its 32 byte big-endian
+// scalar, see ValidatePrivateKeyHex. The bytes are copied, callers should
+// overwrite their own buffer afterwards.
+func NewPrivateKeyFromBytes(key []byte) (_ *PrivateKey, err error) {
+	defer recoverError(&err)
+	if err := validatePrivateKey(key); err != nil {
+		return nil, err
+	}
+	priv, err := crypto.ToECDSA(common.CopyBytes(key))
+	if err != nil {
+		return nil, err
+	}
+	return &PrivateKey{priv}, nil
+}
+
+// ValidatePrivateKeyHex checks that the string, with optional 0x prefix, is a
+// valid hex encoded secp256k1 private key: exactly 64 hex digits encoding a
+// non-zero scalar below the curve order.
+func ValidatePrivateKeyHex(s string) error {
+	digits := s
+	if Has0xPrefix(s) {
+		digits = s[2:]
+	}
+	if len(digits) != 64 {
+		return fmt.Errorf("invalid private key length: have %d hex digits, want 64", len(digits))
+	}
+	key, err := decodeHex(digits)
+	if err != nil {
+		return fmt.Errorf("invalid private key: %v", err)
+	}
+	return validatePrivateKey(key)
+}
+
+// validatePrivateKey checks that the bytes are a valid private key scalar.
+func validatePrivateKey(key []byte) error {
+	if len(key) != 32 {
+		return fmt.Errorf("invalid private key length: have %d bytes, want 32", len(key))
+	}
+	d := new(big.Int).SetBytes(key)
+	if d.Sign() == 0 {
+		return errors.New("invalid private key: zero")
+	}
+	if d.Cmp(crypto.S256().Params().N) >= 0 {
+		return errors.New("invalid private key: not below the curve order")
+	}
+	return nil
+}
+
+// parsePrivateKeyHex parses a hex encoded private key, with optional 0x prefix,
+// failing with the reason ValidatePrivateKeyHex reports. Callers should clear
+// the key with zeroKey once done.
+func parsePrivateKeyHex(s string) (*ecdsa.PrivateKey, error) {
+	if err := ValidatePrivateKeyHex(s); err != nil {
+		return nil, err
+	}
+	return crypto.HexToECDSA(s[len(s)-64:])
+}
+
+// Keccak256 calculates and returns the 32 byte Keccak256 hash of the data. Use
+// Keccak256Concat to hash multiple parts without concatenating them.
 func Keccak256(data []byte) []byte {
 	return crypto.Keccak256(data)
 }
 
-// Keccak256Hash ...
-// TODO: input type of original Keccak256Hash is ...[]byte.
-//       K changed to []byte.
-//       have to check if this modification is fine or not.
+// Keccak256Hash calculates the Keccak256 hash of the data, converting it to an
+// internal Hash data structure.
 func Keccak256Hash(data []byte) *Hash {
 	h := crypto.Keccak256Hash(data)
 	return &Hash{hash: h}
 }
 
+// Keccak256Concat calculates and returns the Keccak256 hash of the concatenation
+// of all parts, like the variadic Keccak256 of go-ethereum.
+func Keccak256Concat(parts *Binaries) []byte {
+	return crypto.Keccak256(parts.binaries...)
+}
+
 // Keccak512 ...
 // TODO: input type of original Keccak512 is ...[]byte.
 //       K changed to []byte.
@@ -56,7 +149,8 @@ func Keccak512(data []byte) []byte {
 	return crypto.Keccak512(data)
 }
 
-// CreateAddress ...
+// CreateAddress returns the address of the contract deployed with CREATE by the
+// given account at the given nonce, i.e. keccak256(rlp([deployer, nonce]))[12:].
 func CreateAddress(wb *Address, wnonce int64) *Address {
 	b := wb.address
 	nonce := uint64(wnonce)
@@ -64,12 +158,22 @@ func CreateAddress(wb *Address, wnonce int64) *Address {
 	return &Address{address: a}
 }
 
-// TODO
-//func CreateAddress2 {
-//}
+// CreateAddress2 returns the address of the contract deployed with CREATE2 by the
+// given account, as defined by EIP-1014, i.e.
+// keccak256(0xff ++ deployer ++ salt ++ keccak256(initCode))[12:].
+func CreateAddress2(deployer *Address, salt *Hash, initCodeHash *Hash) *Address {
+	return &Address{address: crypto.CreateAddress2(deployer.address, salt.hash, initCodeHash.hash[:])}
+}
+
+// CreateAddress2FromCode returns the address of the contract deployed with
+// CREATE2 like CreateAddress2, hashing the init code itself.
+func CreateAddress2FromCode(deployer *Address, salt *Hash, initCode []byte) *Address {
+	return &Address{address: crypto.CreateAddress2(deployer.address, salt.hash, crypto.Keccak256(initCode))}
+}
 
 // ToECDSA ...
-func ToECDSA(d []byte) (*PrivateKey, error) {
+func ToECDSA(d []byte) (_ *PrivateKey, err error) {
+	defer recoverError(&err)
 	priv, err := crypto.ToECDSA(d)
 	if err == nil {
 		return &PrivateKey{priv}, nil
@@ -83,14 +187,16 @@ func ToECDSAUnsafe(d []byte) *PrivateKey {
 	return &PrivateKey{priv}
 }
 
-// FromECDSA ...
+// FromECDSA exports the private key scalar as 32 bytes, or nil once the key was
+// cleared.
 func FromECDSA(priv *PrivateKey) []byte {
 	privateKey := priv.privateKey
 	return crypto.FromECDSA(privateKey)
 }
 
 // UnmarshalPubkey ...
-func UnmarshalPubkey(pub []byte) (*PublicKey, error) {
+func UnmarshalPubkey(pub []byte) (_ *PublicKey, err error) {
+	defer recoverError(&err)
 	publicKey, err := crypto.UnmarshalPubkey(pub)
 	if err == nil {
 		return &PublicKey{publicKey}, nil
@@ -106,7 +212,8 @@ func FromECDSAPub(pub *PublicKey) []byte {
 }
 
 // HexToECDSA ...
-func HexToECDSA(hexkey string) (*PrivateKey, error) {
+func HexToECDSA(hexkey string) (_ *PrivateKey, err error) {
+	defer recoverError(&err)
 	privateKey, err := crypto.HexToECDSA(hexkey)
 	if err == nil {
 		return &PrivateKey{privateKey}, nil
@@ -116,7 +223,8 @@ func HexToECDSA(hexkey string) (*PrivateKey, error) {
 }
 
 // LoadECDSA ...
-func LoadECDSA(file string) (*PrivateKey, error) {
+func LoadECDSA(file string) (_ *PrivateKey, err error) {
+	defer recoverError(&err)
 	privateKey, err := crypto.LoadECDSA(file)
 	if err == nil {
 		return &PrivateKey{privateKey}, nil
@@ -125,13 +233,18 @@ func LoadECDSA(file string) (*PrivateKey, error) {
 }
 
 // SaveECDSA ...
-func SaveECDSA(file string, wpriv *PrivateKey) error {
-	priv := wpriv.privateKey
+func SaveECDSA(file string, wpriv *PrivateKey) (err error) {
+	defer recoverError(&err)
+	priv, err := wpriv.ecdsaKey()
+	if err != nil {
+		return err
+	}
 	return crypto.SaveECDSA(file, priv)
 }
 
 // GenerateKey ...
-func GenerateKey() (*PrivateKey, error) {
+func GenerateKey() (_ *PrivateKey, err error) {
+	defer recoverError(&err)
 	privateKey, err := crypto.GenerateKey()
 	if err == nil {
 		return &PrivateKey{privateKey}, nil
@@ -139,6 +252,35 @@ func GenerateKey() (*PrivateKey, error) {
 	return nil, err
 }
 
+// keySeedLabel is the HMAC message prefix of GenerateKeyFromSeed.
+const keySeedLabel = "web3go secp256k1 key"
+
+// GenerateKeyFromSeed derives a private key from seed material of any non-zero
+// length. The same seed always yields the same key. Candidate i, starting at 0,
+// is HMAC-SHA256(key = seed, message = "web3go secp256k1 key" || uint32be(i)),
+// and the first candidate in the range [1, n-1] of the curve order is used.
+//
+// Keys derived from guessable seeds are as weak as the seed, use GenerateKey for
+// wallets.
+func GenerateKeyFromSeed(seed []byte) (_ *PrivateKey, err error) {
+	defer recoverError(&err)
+	if len(seed) == 0 {
+		return nil, errors.New("empty seed")
+	}
+	mac := hmac.New(sha256.New, seed)
+	for i := uint32(0); ; i++ {
+		var counter [4]byte
+		binary.BigEndian.PutUint32(counter[:], i)
+
+		mac.Reset()
+		mac.Write([]byte(keySeedLabel))
+		mac.Write(counter[:])
+		if key, err := crypto.ToECDSA(mac.Sum(nil)); err == nil {
+			return &PrivateKey{key}, nil
+		}
+	}
+}
+
 // TODO; this function does not work in ios binding, byte is not suppotred!!
 //func ValidateSignatureValues(v byte, wr, ws *BigInt, homestead bool) bool {
 //	r := wr.bigint
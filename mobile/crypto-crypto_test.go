@@ -0,0 +1,204 @@
+package web3go
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestKeccak256(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"", "0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"},
+		{"abc", "0x4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45"},
+		{"Transfer(address,address,uint256)", "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"},
+	}
+	for _, tt := range tests {
+		if have := hexutil.Encode(Keccak256([]byte(tt.input))); have != tt.want {
+			t.Errorf("%q: have %s, want %s", tt.input, have, tt.want)
+		}
+		if have := Keccak256Hash([]byte(tt.input)).GetHex(); have != tt.want {
+			t.Errorf("%q: hash mismatch: have %s, want %s", tt.input, have, tt.want)
+		}
+	}
+	// Hashing in parts equals hashing the concatenation
+	parts := NewBinaries()
+	for _, part := range []string{"Transfer(", "address,address,", "", "uint256)"} {
+		parts.Append([]byte(part))
+	}
+	if have := hexutil.Encode(Keccak256Concat(parts)); have != tests[2].want {
+		t.Errorf("concatenated hash mismatch: have %s, want %s", have, tests[2].want)
+	}
+	if have := hexutil.Encode(Keccak256Concat(NewBinaries())); have != tests[0].want {
+		t.Errorf("empty concatenation mismatch: have %s, want %s", have, tests[0].want)
+	}
+	if parts.Size() != 4 {
+		t.Errorf("size mismatch: have %d, want 4", parts.Size())
+	}
+	if _, err := parts.Get(4); err == nil {
+		t.Error("expected error for out of bounds index")
+	}
+}
+
+func TestCreateAddress(t *testing.T) {
+	tests := []struct {
+		deployer string
+		nonce    int64
+		want     string
+	}{
+		{"0x6ac7ea33f8831ea9dcc53393aaa88b25a785dbf0", 0, "0xcd234A471b72ba2F1Ccf0A70FCABA648a5eeCD8d"},
+		{"0x6ac7ea33f8831ea9dcc53393aaa88b25a785dbf0", 1, "0x343c43A37D37dfF08AE8C4A11544c718AbB4fCF8"},
+		// Deployment of the deterministic deployment proxy by its keyless deployer
+		{"0x3fab184622dc19b6109349b94811493bf2a45362", 0, "0x4e59b44847b379578588920cA78FbF26c0B4956C"},
+	}
+	for _, tt := range tests {
+		deployer, _ := NewAddressFromHex(tt.deployer)
+		if have := CreateAddress(deployer, tt.nonce).GetHex(); have != tt.want {
+			t.Errorf("%s nonce %d: have %s, want %s", tt.deployer, tt.nonce, have, tt.want)
+		}
+	}
+}
+
+func TestCreateAddress2(t *testing.T) {
+	// Test vectors from EIP-1014
+	tests := []struct {
+		deployer string
+		salt     string
+		initCode string
+		want     string
+	}{
+		{"0x0000000000000000000000000000000000000000", "0x0000000000000000000000000000000000000000000000000000000000000000", "0x00", "0x4D1A2e2bB4F88F0250f26Ffff098B0b30B26BF38"},
+		{"0xdeadbeef00000000000000000000000000000000", "0x0000000000000000000000000000000000000000000000000000000000000000", "0x00", "0xB928f69Bb1D91Cd65274e3c79d8986362984fDA3"},
+		{"0xdeadbeef00000000000000000000000000000000", "0x000000000000000000000000feed000000000000000000000000000000000000", "0x00", "0xD04116cDd17beBE565EB2422F2497E06cC1C9833"},
+		{"0x0000000000000000000000000000000000000000", "0x0000000000000000000000000000000000000000000000000000000000000000", "0xdeadbeef", "0x70f2b2914A2a4b783FaEFb75f459A580616Fcb5e"},
+		{"0x00000000000000000000000000000000deadbeef", "0x00000000000000000000000000000000000000000000000000000000cafebabe", "0xdeadbeef", "0x60f3f640a8508fC6a86d45DF051962668E1e8AC7"},
+		{"0x00000000000000000000000000000000deadbeef", "0x00000000000000000000000000000000000000000000000000000000cafebabe", "0xdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef", "0x1d8bfDC5D46DC4f61D6b6115972536eBE6A8854C"},
+		{"0x0000000000000000000000000000000000000000", "0x0000000000000000000000000000000000000000000000000000000000000000", "0x", "0xE33C0C7F7df4809055C3ebA6c09CFe4BaF1BD9e0"},
+	}
+	for i, tt := range tests {
+		deployer, _ := NewAddressFromHex(tt.deployer)
+		salt, _ := NewHashFromHex(tt.salt)
+		initCode := MustDecode(tt.initCode)
+
+		if have := CreateAddress2FromCode(deployer, salt, initCode).GetHex(); have != tt.want {
+			t.Errorf("vector %d: have %s, want %s", i, have, tt.want)
+		}
+		if have := CreateAddress2(deployer, salt, Keccak256Hash(initCode)).GetHex(); have != tt.want {
+			t.Errorf("vector %d: hashed init code mismatch: have %s, want %s", i, have, tt.want)
+		}
+	}
+}
+
+func TestGenerateKeyFromSeed(t *testing.T) {
+	// Golden values, the derivation must never change
+	tests := []struct {
+		seed    string
+		key     string
+		address string
+	}{
+		{"web3go", "6f80b87b76b08ab6baa42c231af7adfe0dda2f35a1460c27a9e3aedc0d5b8c9a", "0xdeBAAdBc6d037db9a97101b8744b92861b6812d1"},
+		{"a", "39887b6f341e5db3c7ebcbf9779c83d6b892ec39ba071c2111ee4e967aa47d8c", "0x6764fdE5814FEC0695bDe1121BDC69687BC3F1bB"},
+		{"deterministic deployment", "874d3b5bef2b0a73868abdd8f5cb4e182b215b3e4991283eca803f237fb1cb27", "0x1d411396869FF96a71297E81c86934d7b8996fFb"},
+	}
+	for _, tt := range tests {
+		key, err := GenerateKeyFromSeed([]byte(tt.seed))
+		if err != nil {
+			t.Fatalf("%q: %v", tt.seed, err)
+		}
+		if have := hexutil.Encode(FromECDSA(key)); have != "0x"+tt.key {
+			t.Errorf("%q: key mismatch: have %s, want %s", tt.seed, have, tt.key)
+		}
+		if have := PubkeyToAddress(key.Public()).GetHex(); have != tt.address {
+			t.Errorf("%q: address mismatch: have %s, want %s", tt.seed, have, tt.address)
+		}
+	}
+	if _, err := GenerateKeyFromSeed(nil); err == nil {
+		t.Error("expected error for empty seed")
+	}
+}
+
+func TestPrivateKeyValidation(t *testing.T) {
+	valid := []string{
+		testKeyHex,
+		"0x" + testKeyHex,
+		"0X" + testKeyHex,
+		"0000000000000000000000000000000000000000000000000000000000000001",
+		"fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140",
+	}
+	for _, key := range valid {
+		if err := ValidatePrivateKeyHex(key); err != nil {
+			t.Errorf("%s: unexpected error: %v", key, err)
+		}
+		if parsed, err := parsePrivateKeyHex(key); err != nil {
+			t.Errorf("%s: unexpected parse error: %v", key, err)
+		} else if have := hexutil.Encode(FromECDSA(&PrivateKey{parsed})); have != "0x"+key[len(key)-64:] {
+			t.Errorf("%s: parsed key mismatch: have %s", key, have)
+		}
+		priv, err := NewPrivateKeyFromBytes(hexutil.MustDecode("0x" + key[len(key)-64:]))
+		if err != nil {
+			t.Errorf("%s: unexpected construction error: %v", key, err)
+		} else if have := hexutil.Encode(FromECDSA(priv)); have != "0x"+key[len(key)-64:] {
+			t.Errorf("%s: key mismatch: have %s", key, have)
+		}
+	}
+	invalid := map[string]string{
+		"":                "length",
+		"0x1234":          "length",
+		testKeyHex + "00": "length",
+		"0000000000000000000000000000000000000000000000000000000000000000": "zero",
+		"fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141": "curve order",
+		"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff": "curve order",
+		"zz" + testKeyHex[2:]: "invalid hex character",
+	}
+	for key, want := range invalid {
+		if err := ValidatePrivateKeyHex(key); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: error mismatch: have %v, want %q", key, err, want)
+		}
+		// Signing paths report the same detailed reason
+		if _, err := SignHash(Keccak256Hash(nil), key); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: signing error mismatch: have %v, want %q", key, err, want)
+		}
+		if raw, err := hex.DecodeString(strings.TrimPrefix(key, "0x")); err == nil {
+			if _, err := NewPrivateKeyFromBytes(raw); err == nil {
+				t.Errorf("%q: expected construction error", key)
+			}
+		}
+	}
+}
+
+func TestPrivateKeyClear(t *testing.T) {
+	key, _ := GenerateKeyFromSeed([]byte("clear"))
+	scalar := key.privateKey.D
+	hash := Keccak256Hash([]byte("message"))
+
+	if _, err := SignHashWithKey(hash, key); err != nil {
+		t.Fatalf("signing before clear failed: %v", err)
+	}
+	tx := NewTransaction(0, testAddress, NewBigInt(1), 21000, NewBigInt(1), nil)
+	if _, err := SignTx(tx, NewHomesteadSigner(), key); err != nil {
+		t.Fatalf("transaction signing before clear failed: %v", err)
+	}
+	key.Clear()
+	key.Clear()
+	for _, word := range scalar.Bits() {
+		if word != 0 {
+			t.Fatal("key scalar not zeroed")
+		}
+	}
+	if _, err := SignHashWithKey(hash, key); err != ErrKeyCleared {
+		t.Errorf("hash signing error mismatch: %v", err)
+	}
+	if _, err := Sign(hash.GetBytes(), key); err != ErrKeyCleared {
+		t.Errorf("signing error mismatch: %v", err)
+	}
+	if _, err := SignTx(tx, NewHomesteadSigner(), key); err != ErrKeyCleared {
+		t.Errorf("transaction signing error mismatch: %v", err)
+	}
+	if FromECDSA(key) != nil || key.Public() != nil {
+		t.Error("cleared key still exported")
+	}
+}
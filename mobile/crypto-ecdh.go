@@ -0,0 +1,70 @@
+package web3go
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// SharedSecret computes the secp256k1 ECDH shared secret between the hex encoded
+// private key and the peer public key, given in the 33 byte compressed or the
+// 65 byte uncompressed format. The result is the 32 byte x-coordinate of the
+// shared point, which both parties derive alike. It is not uniformly random, use
+// SharedSecretSHA256 to obtain a symmetric key.
+func SharedSecret(privKeyHex string, peerPubkey []byte) (_ []byte, err error) {
+	defer recoverError(&err)
+	key, err := parsePrivateKeyHex(privKeyHex)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroKey(key)
+
+	peer, err := parsePeerPubkey(peerPubkey)
+	if err != nil {
+		return nil, err
+	}
+	x, y := crypto.S256().ScalarMult(peer.X, peer.Y, math.PaddedBigBytes(key.D, 32))
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return nil, errors.New("shared secret is the point at infinity")
+	}
+	return math.PaddedBigBytes(x, 32), nil
+}
+
+// SharedSecretSHA256 computes the ECDH shared secret like SharedSecret and hashes
+// it with SHA-256, yielding a 32 byte key for direct use with symmetric ciphers.
+func SharedSecretSHA256(privKeyHex string, peerPubkey []byte) ([]byte, error) {
+	secret, err := SharedSecret(privKeyHex, peerPubkey)
+	if err != nil {
+		return nil, err
+	}
+	key := sha256.Sum256(secret)
+	return key[:], nil
+}
+
+// parsePeerPubkey decodes a compressed or uncompressed public key, rejecting
+// points not on the curve.
+func parsePeerPubkey(pubkey []byte) (*ecdsa.PublicKey, error) {
+	switch len(pubkey) {
+	case compressedPubkeyLength:
+		pub, err := DecompressPubkey(pubkey)
+		if err != nil {
+			return nil, err
+		}
+		return pub.publicKey, nil
+	case 65:
+		if pubkey[0] != 0x04 {
+			return nil, fmt.Errorf("invalid uncompressed public key prefix %#x", pubkey[0])
+		}
+		pub, err := crypto.UnmarshalPubkey(pubkey)
+		if err != nil {
+			return nil, errors.New("invalid public key: point not on curve")
+		}
+		return pub, nil
+	default:
+		return nil, fmt.Errorf("invalid public key length %d, want 33 or 65", len(pubkey))
+	}
+}
@@ -0,0 +1,69 @@
+package web3go
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const testGeneratorPoint = "0x0479be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8"
+
+func TestSharedSecretVector(t *testing.T) {
+	// 2·G has the well known x-coordinate below
+	priv := "0x0000000000000000000000000000000000000000000000000000000000000002"
+	want := "0xc6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee5"
+
+	uncompressed := hexutil.MustDecode(testGeneratorPoint)
+	compressed := append([]byte{0x02}, uncompressed[1:33]...)
+	for _, peer := range [][]byte{uncompressed, compressed} {
+		secret, err := SharedSecret(priv, peer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have := hexutil.Encode(secret); have != want {
+			t.Errorf("secret mismatch: have %s, want %s", have, want)
+		}
+		key, err := SharedSecretSHA256(priv, peer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have := hexutil.Encode(key); have != "0x0135da2f8acf7b9e3090939432e47684eb888ea38c2173054d4eedffdf152ca5" {
+			t.Errorf("derived key mismatch: have %s", have)
+		}
+	}
+	offCurve := hexutil.MustDecode(testGeneratorPoint)
+	offCurve[64]++
+	for name, peer := range map[string][]byte{
+		"off curve":  offCurve,
+		"identity":   make([]byte, 65),
+		"bad prefix": append([]byte{0x05}, compressed[1:]...),
+		"length":     uncompressed[1:],
+	} {
+		if _, err := SharedSecret(priv, peer); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+	if _, err := SharedSecret("0x1234", uncompressed); err == nil {
+		t.Error("expected error for invalid key")
+	}
+}
+
+func TestSharedSecretSymmetry(t *testing.T) {
+	for i := 0; i < 8; i++ {
+		a, _ := GenerateKey()
+		b, _ := GenerateKey()
+		ab, err := SharedSecret(hex.EncodeToString(FromECDSA(a)), FromECDSAPub(b.Public()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ba, err := SharedSecret(hex.EncodeToString(FromECDSA(b)), CompressPubkey(a.Public()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(ab, ba) || len(ab) != 32 {
+			t.Fatalf("secrets differ: %x != %x", ab, ba)
+		}
+	}
+}
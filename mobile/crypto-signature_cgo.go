@@ -1,49 +1,148 @@
 package web3go
 
 import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
-// Ecrecover ...
-func Ecrecover(hash, sig []byte) ([]byte, error) {
-	return crypto.Ecrecover(hash, sig)
+// compressedPubkeyLength is the length of a compressed secp256k1 public key.
+const compressedPubkeyLength = 33
+
+// Ecrecover returns the uncompressed public key that created the 65 byte
+// [R || S || V] signature of the hash. V may be given either as 0/1 or as 27/28.
+// Malleable signatures with an S value in the upper half of the curve order are
+// rejected, as go-ethereum does for transactions since Homestead.
+func Ecrecover(hash *Hash, sig []byte) (_ []byte, err error) {
+	defer recoverError(&err)
+	normalized, err := normalizeSignature(sig)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Ecrecover(hash.hash[:], normalized)
 }
 
-// SigToPub ...
-func SigToPub(hash, sig []byte) (*PublicKey, error) {
-	pub, err := crypto.SigToPub(hash, sig)
-	if err == nil {
-		return &PublicKey{pub}, nil
+// SigToPub returns the public key that created the signature of the hash, see
+// Ecrecover. The result can be converted to an address with PubkeyToAddress.
+func SigToPub(hash *Hash, sig []byte) (_ *PublicKey, err error) {
+	defer recoverError(&err)
+	normalized, err := normalizeSignature(sig)
+	if err != nil {
+		return nil, err
 	}
-	return nil, err
+	pub, err := crypto.SigToPub(hash.hash[:], normalized)
+	if err != nil {
+		return nil, err
+	}
+	return &PublicKey{pub}, nil
 }
 
-// Sign ...
-func Sign(hash []byte, wprv *PrivateKey) ([]byte, error) {
-	prv := wprv.privateKey
+// Sign calculates an ECDSA signature of the 32 byte hash, see SignHashWithKey.
+func Sign(hash []byte, wprv *PrivateKey) (_ []byte, err error) {
+	defer recoverError(&err)
+	prv, err := wprv.ecdsaKey()
+	if err != nil {
+		return nil, err
+	}
 	return crypto.Sign(hash, prv)
 }
 
-// VerifySignature ...
-func VerifySignature(pubkey, hash, sig []byte) bool {
-	return crypto.VerifySignature(pubkey, hash, sig)
+// SignHash signs the hash with the given hex encoded private key, returning the
+// 65 byte [R || S || V] signature with V being 0 or 1. This is the format taken
+// by Transaction.WithSignature and Ecrecover. Signatures in the personal_sign
+// format, see SignPersonalMessage, instead carry V as 27 or 28, i.e. 27 plus the
+// recovery ID returned here.
+func SignHash(hash *Hash, privKeyHex string) (_ []byte, err error) {
+	defer recoverError(&err)
+	key, err := parsePrivateKeyHex(privKeyHex)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroKey(key)
+
+	return crypto.Sign(hash.hash[:], key)
+}
+
+// SignHashWithKey signs the hash with the given private key, returning the
+// signature in the same format as SignHash.
+func SignHashWithKey(hash *Hash, key *PrivateKey) (_ []byte, err error) {
+	defer recoverError(&err)
+	prv, err := key.ecdsaKey()
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Sign(hash.hash[:], prv)
+}
+
+// VerifySignature checks that the signature of the hash was created by the given
+// compressed or uncompressed public key. The signature may be given as 64 byte
+// [R || S] or with a trailing recovery ID, which is ignored. Malleable signatures
+// with a high S value are rejected.
+func VerifySignature(pubkey []byte, hash *Hash, sig []byte) bool {
+	if len(sig) == crypto.SignatureLength {
+		sig = sig[:crypto.RecoveryIDOffset]
+	}
+	if len(sig) != crypto.RecoveryIDOffset {
+		return false
+	}
+	return crypto.VerifySignature(pubkey, hash.hash[:], sig)
+}
+
+// normalizeSignature validates a 65 byte [R || S || V] signature, returning a
+// copy with V converted from 27/28 to 0/1 if needed. Signatures with a high S
+// value are rejected like in go-ethereum's Homestead transaction validation.
+func normalizeSignature(sig []byte) ([]byte, error) {
+	if len(sig) != crypto.SignatureLength {
+		return nil, fmt.Errorf("invalid signature length: have %d, want %d", len(sig), crypto.SignatureLength)
+	}
+	normalized := common.CopyBytes(sig)
+	if v := normalized[crypto.RecoveryIDOffset]; v == 27 || v == 28 {
+		normalized[crypto.RecoveryIDOffset] -= 27
+	}
+	r, s := new(big.Int).SetBytes(normalized[:32]), new(big.Int).SetBytes(normalized[32:64])
+	if !crypto.ValidateSignatureValues(normalized[crypto.RecoveryIDOffset], r, s, true) {
+		return nil, errors.New("invalid signature values")
+	}
+	return normalized, nil
 }
 
-// DecompressPubkey ...
-func DecompressPubkey(pubkey []byte) (*PublicKey, error) {
+// DecompressPubkey parses a 33 byte compressed public key, failing for other
+// lengths, unknown prefixes and points that are not on the secp256k1 curve.
+func DecompressPubkey(pubkey []byte) (_ *PublicKey, err error) {
+	defer recoverError(&err)
+	if len(pubkey) != compressedPubkeyLength {
+		return nil, fmt.Errorf("invalid compressed public key length: have %d, want %d", len(pubkey), compressedPubkeyLength)
+	}
+	if pubkey[0] != 0x02 && pubkey[0] != 0x03 {
+		return nil, fmt.Errorf("invalid compressed public key prefix %#x", pubkey[0])
+	}
 	pub, err := crypto.DecompressPubkey(pubkey)
-	if err == nil {
-		return &PublicKey{pub}, nil
+	if err != nil {
+		return nil, errors.New("invalid compressed public key: point not on curve")
 	}
-	return nil, err
+	return &PublicKey{pub}, nil
 }
 
-// CompressPubkey ...
+// CompressPubkey encodes a public key to the 33 byte compressed format.
 func CompressPubkey(wpubkey *PublicKey) []byte {
 	pubkey := wpubkey.publicKey
 	return crypto.CompressPubkey(pubkey)
 }
 
+// CompressedPubkeyToAddress returns the address of a 33 byte compressed public
+// key, see DecompressPubkey.
+func CompressedPubkeyToAddress(pubkey []byte) (_ *Address, err error) {
+	defer recoverError(&err)
+	pub, err := DecompressPubkey(pubkey)
+	if err != nil {
+		return nil, err
+	}
+	return &Address{crypto.PubkeyToAddress(*pub.publicKey)}, nil
+}
+
 // TODO
 //func S256 {
 //}
@@ -0,0 +1,171 @@
+package web3go
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestSignatureRecovery(t *testing.T) {
+	key, _ := HexToECDSA(testKeyHex)
+	pubkey := FromECDSAPub(key.Public())
+	hash := Keccak256Hash([]byte("hello"))
+
+	sig, err := crypto.Sign(hash.GetBytes(), key.privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eip191 := common.CopyBytes(sig)
+	eip191[64] += 27
+
+	for _, s := range [][]byte{sig, eip191} {
+		recovered, err := Ecrecover(hash, s)
+		if err != nil {
+			t.Fatalf("v=%d: %v", s[64], err)
+		}
+		if !bytes.Equal(recovered, pubkey) {
+			t.Errorf("v=%d: recovered key mismatch", s[64])
+		}
+		pub, err := SigToPub(hash, s)
+		if err != nil {
+			t.Fatalf("v=%d: %v", s[64], err)
+		}
+		if !PubkeyToAddress(pub).Equals(testAddress) {
+			t.Errorf("v=%d: recovered address mismatch", s[64])
+		}
+		if !VerifySignature(pubkey, hash, s) {
+			t.Errorf("v=%d: 65 byte signature rejected", s[64])
+		}
+	}
+	if !VerifySignature(pubkey, hash, sig[:64]) {
+		t.Error("64 byte signature rejected")
+	}
+	if !VerifySignature(CompressPubkey(key.Public()), hash, sig[:64]) {
+		t.Error("signature rejected for compressed key")
+	}
+	if VerifySignature(pubkey, Keccak256Hash([]byte("world")), sig[:64]) {
+		t.Error("signature accepted for other hash")
+	}
+	// The malleable twin (r, n-s, v^1) recovers the same key but must be rejected
+	s := new(big.Int).SetBytes(sig[32:64])
+	malleable := common.CopyBytes(sig)
+	copy(malleable[32:64], common.LeftPadBytes(new(big.Int).Sub(crypto.S256().Params().N, s).Bytes(), 32))
+	malleable[64] ^= 1
+	if _, err := Ecrecover(hash, malleable); err == nil {
+		t.Error("high-s signature accepted by Ecrecover")
+	}
+	if _, err := SigToPub(hash, malleable); err == nil {
+		t.Error("high-s signature accepted by SigToPub")
+	}
+	if VerifySignature(pubkey, hash, malleable) {
+		t.Error("high-s signature accepted by VerifySignature")
+	}
+	for _, bad := range [][]byte{sig[:64], append(common.CopyBytes(sig), 0), nil} {
+		if _, err := Ecrecover(hash, bad); err == nil {
+			t.Errorf("%d byte signature accepted", len(bad))
+		}
+	}
+	invalidV := common.CopyBytes(sig)
+	invalidV[64] = 2
+	if _, err := Ecrecover(hash, invalidV); err == nil {
+		t.Error("invalid recovery ID accepted")
+	}
+}
+
+func TestPubkeyCompression(t *testing.T) {
+	// The fixed key of the generate_wallet example
+	key, _ := HexToECDSA("e09ae607ff4fb3320133e73a76d4fc8e5b784663b2f34662fb910f3ff5d8d5ef")
+	compressed := CompressPubkey(key.Public())
+	if len(compressed) != 33 {
+		t.Fatalf("compressed length mismatch: have %d, want 33", len(compressed))
+	}
+	addr, err := CompressedPubkeyToAddress(compressed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := addr.GetHex(), "0x15d48078AB8532b8857e0568311fc3792a5562ab"; have != want {
+		t.Errorf("address mismatch: have %s, want %s", have, want)
+	}
+	for i := 0; i < 50; i++ {
+		key, err := GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub, err := DecompressPubkey(CompressPubkey(key.Public()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(FromECDSAPub(pub), FromECDSAPub(key.Public())) {
+			t.Fatalf("key %d: round-trip mismatch", i)
+		}
+	}
+	offCurve := common.CopyBytes(compressed)
+	offCurve[1], offCurve[32] = 0xff, 0xff
+	for i := 0; i < 256; i++ {
+		// Find an x coordinate without a matching point on the curve
+		if _, err := crypto.DecompressPubkey(offCurve); err != nil {
+			break
+		}
+		offCurve[31]++
+	}
+	invalid := map[string][]byte{
+		"uncompressed": FromECDSAPub(key.Public()),
+		"short":        compressed[:32],
+		"empty":        nil,
+		"bad prefix":   append([]byte{0x04}, compressed[1:]...),
+		"off curve":    offCurve,
+	}
+	for name, data := range invalid {
+		if _, err := DecompressPubkey(data); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+		if _, err := CompressedPubkeyToAddress(data); err == nil {
+			t.Errorf("%s: expected address error", name)
+		}
+	}
+}
+
+func TestSignHash(t *testing.T) {
+	chainID := NewBigInt(1)
+	txs := []*Transaction{
+		NewTransaction(0, testAddress, NewBigInt(1), 21000, NewBigInt(1), nil),
+		newTestDynamicFeeTx(chainID, 1, testAddress, NewBigInt(1), 21000, NewBigInt(1), NewBigInt(2), nil),
+	}
+	key, _ := HexToECDSA(testKeyHex)
+	for i, tx := range txs {
+		hash, err := tx.GetSigningHash(chainID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, err := SignHash(hash, "0x"+testKeyHex)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(sig) != 65 || sig[64] > 1 {
+			t.Fatalf("tx %d: invalid signature format %x", i, sig)
+		}
+		if withKey, _ := SignHashWithKey(hash, key); !bytes.Equal(withKey, sig) {
+			t.Errorf("tx %d: signature mismatch between variants", i)
+		}
+		signed, err := tx.WithSignature(sig, chainID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		from, err := signed.GetFrom(chainID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !from.Equals(testAddress) {
+			t.Errorf("tx %d: sender mismatch: have %s, want %s", i, from.GetHex(), testAddress.GetHex())
+		}
+		if recovered, err := Ecrecover(hash, sig); err != nil || !bytes.Equal(recovered, FromECDSAPub(key.Public())) {
+			t.Errorf("tx %d: recovery mismatch: %v", i, err)
+		}
+	}
+	if _, err := SignHash(Keccak256Hash(nil), "0x1234"); err == nil {
+		t.Error("expected error for invalid key")
+	}
+}
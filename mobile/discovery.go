@@ -0,0 +1,119 @@
+// Copyright 2019 The bcl-chain Authors. All rights reserved.
+
+// Contains the BIP-44 discovery of used HD accounts.
+
+package web3go
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// maxDiscoveryBatch is the maximum number of addresses looked up in one batch.
+const maxDiscoveryBatch = 50
+
+// discoveryBackend is the part of the RPC client API used to discover accounts.
+type discoveryBackend interface {
+	BatchCallContext(ctx context.Context, b []rpc.BatchElem) error
+}
+
+// DiscoverAccounts scans the consecutive accounts m/44'/60'/0'/0/i of a BIP-39
+// mnemonic without password, and returns the ones having a balance or a nonce
+// in order. The scan stops after gapLimit consecutive unused accounts, as BIP-44
+// account discovery does; 20 is the customary limit.
+//
+// Balances and nonces are looked up in batches of RPC calls. Use a context with
+// a timeout, the scan fails as soon as the context is done.
+func (ec *EthereumClient) DiscoverAccounts(ctx *Context, mnemonic string, gapLimit int) (_ *Accounts, err error) {
+	defer recoverError(&err)
+	return discoverAccounts(ctx.context, ec.client.Client(), mnemonic, gapLimit)
+}
+
+func discoverAccounts(ctx context.Context, backend discoveryBackend, mnemonic string, gapLimit int) (*Accounts, error) {
+	if gapLimit <= 0 {
+		return nil, fmt.Errorf("invalid gap limit %d", gapLimit)
+	}
+	wallet, err := newHDWallet(mnemonic, "")
+	if err != nil {
+		return nil, err
+	}
+	base, _ := parseBIP44Path(DefaultBaseDerivationPath)
+
+	var (
+		used   []accounts.Account
+		unused int
+	)
+	for index := uint32(0); unused < gapLimit && index < hardenedKeyStart; {
+		// Derive the next window of accounts, no larger than needed to reach the
+		// gap limit
+		size := gapLimit - unused
+		if size > maxDiscoveryBatch {
+			size = maxDiscoveryBatch
+		}
+		if remaining := hardenedKeyStart - index; uint32(size) > remaining {
+			size = int(remaining)
+		}
+		window := make([]accounts.Account, size)
+		for i := range window {
+			path := append(accounts.DerivationPath{}, base...)
+			path[len(path)-1] = index + uint32(i)
+			account, err := wallet.Derive(path, false)
+			if err != nil {
+				return nil, err
+			}
+			window[i] = accounts.Account{Address: account.Address, URL: accounts.URL{Path: path.String()}}
+		}
+		index += uint32(size)
+
+		// Look up the balances and nonces of the window in a single batch
+		active, err := discoverActivity(ctx, backend, window)
+		if err != nil {
+			return nil, err
+		}
+		for i, account := range window {
+			if active[i] {
+				used, unused = append(used, account), 0
+			} else {
+				unused++
+			}
+		}
+	}
+	return &Accounts{used}, nil
+}
+
+// discoverActivity reports for each account whether it has a balance or sent
+// transactions at the latest block.
+func discoverActivity(ctx context.Context, backend discoveryBackend, window []accounts.Account) ([]bool, error) {
+	var (
+		balances = make([]hexutil.Big, len(window))
+		nonces   = make([]hexutil.Uint64, len(window))
+		batch    = make([]rpc.BatchElem, 0, 2*len(window))
+	)
+	for i, account := range window {
+		batch = append(batch,
+			rpc.BatchElem{Method: "eth_getBalance", Args: []interface{}{account.Address, "latest"}, Result: &balances[i]},
+			rpc.BatchElem{Method: "eth_getTransactionCount", Args: []interface{}{account.Address, "latest"}, Result: &nonces[i]},
+		)
+	}
+	if err := backend.BatchCallContext(ctx, batch); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	active := make([]bool, len(window))
+	for i, elem := range batch {
+		if elem.Error != nil {
+			address := window[i/2].Address
+			return nil, fmt.Errorf("%s of %s failed: %v", elem.Method, address.Hex(), elem.Error)
+		}
+	}
+	for i := range window {
+		active[i] = balances[i].ToInt().Sign() > 0 || nonces[i] > 0
+	}
+	return active, nil
+}
@@ -0,0 +1,90 @@
+package web3go
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// testDiscoveryBackend serves balances and nonces of a few addresses and counts
+// the batches and calls served.
+type testDiscoveryBackend struct {
+	balances map[common.Address]*big.Int
+	nonces   map[common.Address]uint64
+	block    bool
+
+	batches, calls int
+}
+
+func (b *testDiscoveryBackend) BatchCallContext(ctx context.Context, batch []rpc.BatchElem) error {
+	if b.block {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	b.batches++
+	for i := range batch {
+		b.calls++
+		address := batch[i].Args[0].(common.Address)
+		switch batch[i].Method {
+		case "eth_getBalance":
+			balance := new(big.Int)
+			if b.balances[address] != nil {
+				balance.Set(b.balances[address])
+			}
+			*batch[i].Result.(*hexutil.Big) = hexutil.Big(*balance)
+		case "eth_getTransactionCount":
+			*batch[i].Result.(*hexutil.Uint64) = hexutil.Uint64(b.nonces[address])
+		default:
+			batch[i].Error = errors.New("method not found")
+		}
+	}
+	return nil
+}
+
+func TestDiscoverAccounts(t *testing.T) {
+	addresses, err := DeriveAddresses(testMnemonic, 0, 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := func(index int) common.Address { return addresses.addresses[index] }
+	backend := &testDiscoveryBackend{
+		balances: map[common.Address]*big.Int{address(0): big.NewInt(1), address(5): big.NewInt(1000)},
+		nonces:   map[common.Address]uint64{address(1): 3, address(5): 1},
+	}
+	found, err := discoverAccounts(context.Background(), backend, testMnemonic, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.Size() != 3 {
+		t.Fatalf("discovered account count mismatch: have %d, want 3", found.Size())
+	}
+	for i, index := range []int{0, 1, 5} {
+		account, _ := found.Get(i)
+		if account.account.Address != address(index) {
+			t.Errorf("account %d mismatch: have %s, want %s", i, account.GetAddress().GetHex(), address(index).Hex())
+		}
+	}
+	if url, _ := found.Get(2); url.GetURL() != "m/44'/60'/0'/0/5" {
+		t.Errorf("account path mismatch: have %s", url.GetURL())
+	}
+	// Windows only cover the accounts needed to reach the gap limit: 0-4, 5-6
+	// and 7-10
+	if backend.batches != 3 || backend.calls != 2*11 {
+		t.Errorf("lookups not batched: %d batches of %d calls", backend.batches, backend.calls)
+	}
+
+	if _, err := discoverAccounts(context.Background(), backend, testMnemonic, 0); err == nil {
+		t.Error("expected error for zero gap limit")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := discoverAccounts(ctx, &testDiscoveryBackend{block: true}, testMnemonic, 5); err != context.DeadlineExceeded {
+		t.Errorf("timeout error mismatch: %v", err)
+	}
+}
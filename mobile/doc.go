@@ -55,6 +55,14 @@
 // are generated as output arguments in ObjC. To avoid weird generated names like
 // ret_0 for them, please always assign names to output variables if tuples.
 //
+// Integer values are exposed as int64, the widest integer gomobile supports.
+// Unsigned 64 bit fields (gas, nonces, timestamps) above 2^63-1 would wrap
+// negative in the plain getters, which are kept for compatibility; their
+// Checked variants return an error instead. Block numbers are always checked.
+// Values that are routinely large, like balances and fees in wei, are returned
+// as BigInt, whose GetInt64 is undefined out of range: use GetInt64Checked or
+// FitsInt64, or the string conversions, before narrowing them.
+//
 // Note, a panic *cannot* cross over language boundaries, instead will result in
 // an undebuggable SEGFAULT in the process. For error handling only ever use error
 // returns, which may be the only or the second return.
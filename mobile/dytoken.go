@@ -51,7 +51,7 @@ func (DyToken *DyToken_) Transfer(opts *TransactOpts, to *Address, value *BigInt
 	if err != nil {
 		return nil, err
 	}
-	return &Transaction{tx}, nil
+	return &Transaction{tx: tx}, nil
 }
 
 func (DyToken *DyToken_) BuildTransfer(opts *TransactOpts, to *Address, value *BigInt) (*Transaction, error) {
@@ -69,7 +69,7 @@ func (DyToken *DyToken_) BuildTransfer(opts *TransactOpts, to *Address, value *B
 	if err != nil {
 		return nil, err
 	}
-	return &Transaction{signedTx}, nil
+	return &Transaction{tx: signedTx}, nil
 }
 
 //增发功能
@@ -79,7 +79,7 @@ func (DyToken *DyToken_) Mint(opts *TransactOpts, to *Address, value *BigInt) (*
     if err != nil {
       return nil, err
     }
-    return &Transaction{tx},err
+    return &Transaction{tx: tx},err
 }
 
 func (DyToken *DyToken_) BuildMint(opts *TransactOpts, to *Address, value *BigInt) (*Transaction, error) {
@@ -97,7 +97,7 @@ func (DyToken *DyToken_) BuildMint(opts *TransactOpts, to *Address, value *BigIn
 	if err != nil {
 		return nil, err
 	}
-	return &Transaction{signedTx}, nil
+	return &Transaction{tx: signedTx}, nil
 }
 
 //销毁功能
@@ -107,7 +107,7 @@ func (DyToken *DyToken_) Burn(opts *TransactOpts, value *BigInt) (*Transaction,
     if err != nil {
       return nil, err
     }
-    return &Transaction{tx},err
+    return &Transaction{tx: tx},err
 }
 
 func (DyToken *DyToken_) BuildBurn(opts *TransactOpts, value *BigInt) (*Transaction, error) {
@@ -125,7 +125,7 @@ func (DyToken *DyToken_) BuildBurn(opts *TransactOpts, value *BigInt) (*Transact
 	if err != nil {
 		return nil, err
 	}
-	return &Transaction{signedTx}, nil
+	return &Transaction{tx: signedTx}, nil
 }
 
 //转移权限功能
@@ -135,7 +135,7 @@ func (DyToken *DyToken_) TransferGRCOwnership (opts *TransactOpts, to *Address)(
   if err != nil {
     return nil, err
   }
-  return &Transaction{tx},err
+  return &Transaction{tx: tx},err
 }
 
 func (DyToken *DyToken_) BuildTransferGRCOwnership(opts *TransactOpts, to *Address) (*Transaction, error) {
@@ -153,7 +153,7 @@ func (DyToken *DyToken_) BuildTransferGRCOwnership(opts *TransactOpts, to *Addre
 	if err != nil {
 		return nil, err
 	}
-	return &Transaction{signedTx}, nil
+	return &Transaction{tx: signedTx}, nil
 }
 
 //禁用功能
@@ -163,7 +163,7 @@ func (DyToken *DyToken_) Pause(opts *TransactOpts) (*Transaction, error){
     if err != nil {
       return nil, err
     }
-    return &Transaction{tx},err
+    return &Transaction{tx: tx},err
 }
 
 func (DyToken *DyToken_) BuildPause(opts *TransactOpts) (*Transaction, error) {
@@ -181,7 +181,7 @@ func (DyToken *DyToken_) BuildPause(opts *TransactOpts) (*Transaction, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Transaction{signedTx}, nil
+	return &Transaction{tx: signedTx}, nil
 }
 
 //启用功能
@@ -191,7 +191,7 @@ func (DyToken *DyToken_) UnPause(opts *TransactOpts) (*Transaction, error){
     if err != nil {
       return nil, err
     }
-    return &Transaction{tx},err
+    return &Transaction{tx: tx},err
 }
 
 func (DyToken *DyToken_) BuildUnPause(opts *TransactOpts) (*Transaction, error) {
@@ -209,7 +209,7 @@ func (DyToken *DyToken_) BuildUnPause(opts *TransactOpts) (*Transaction, error)
 	if err != nil {
 		return nil, err
 	}
-	return &Transaction{signedTx}, nil
+	return &Transaction{tx: signedTx}, nil
 }
 
 
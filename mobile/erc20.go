@@ -58,7 +58,7 @@ func (erc20 *ERC20) BuildTransfer(opts *TransactOpts, to *Address, value *BigInt
 	if err != nil {
 		return nil, err
 	}
-	return &Transaction{signedTx}, nil
+	return &Transaction{tx: signedTx}, nil
 }
 
 func (erc20 *ERC20) Transfer(opts *TransactOpts, to *Address, value *BigInt) (*Transaction, error) {
@@ -66,5 +66,5 @@ func (erc20 *ERC20) Transfer(opts *TransactOpts, to *Address, value *BigInt) (*T
 	if err != nil {
 		return nil, err
 	}
-	return &Transaction{tx}, nil
+	return &Transaction{tx: tx}, nil
 }
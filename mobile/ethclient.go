@@ -28,40 +28,42 @@ import (
 // EthereumClient provides access to the Ethereum APIs.
 type EthereumClient struct {
 	client *ethclient.Client
+	ids    chainIDCache
 }
 
 // NewEthereumClient connects a client to the given URL.
-func NewEthereumClient(rawurl string) (client *EthereumClient, _ error) {
+func NewEthereumClient(rawurl string) (client *EthereumClient, err error) {
+	defer recoverError(&err)
 	rawClient, err := ethclient.Dial(rawurl)
-	return &EthereumClient{rawClient}, err
+	return &EthereumClient{client: rawClient}, err
 }
 
 // GetBlockByHash returns the given full block.
-func (ec *EthereumClient) GetBlockByHash(ctx *Context, hash *Hash) (block *Block, _ error) {
-	rawBlock, err := ec.client.BlockByHash(ctx.context, hash.hash)
-	return &Block{rawBlock}, err
+//
+// Deprecated: use BlockByHash, which this method calls.
+func (ec *EthereumClient) GetBlockByHash(ctx *Context, hash *Hash) (block *Block, err error) {
+	return ec.BlockByHash(ctx, hash)
 }
 
 // GetBlockByNumber returns a block from the current canonical chain. If number is <0, the
 // latest known block is returned.
-func (ec *EthereumClient) GetBlockByNumber(ctx *Context, number int64) (block *Block, _ error) {
-	if number < 0 {
-		rawBlock, err := ec.client.BlockByNumber(ctx.context, nil)
-		return &Block{rawBlock}, err
-	}
-	rawBlock, err := ec.client.BlockByNumber(ctx.context, big.NewInt(number))
-	return &Block{rawBlock}, err
+//
+// Deprecated: use BlockByNumber, which this method calls.
+func (ec *EthereumClient) GetBlockByNumber(ctx *Context, number int64) (block *Block, err error) {
+	return ec.BlockByNumber(ctx, number)
 }
 
 // GetHeaderByHash returns the block header with the given hash.
-func (ec *EthereumClient) GetHeaderByHash(ctx *Context, hash *Hash) (header *Header, _ error) {
+func (ec *EthereumClient) GetHeaderByHash(ctx *Context, hash *Hash) (header *Header, err error) {
+	defer recoverError(&err)
 	rawHeader, err := ec.client.HeaderByHash(ctx.context, hash.hash)
 	return &Header{rawHeader}, err
 }
 
 // GetHeaderByNumber returns a block header from the current canonical chain. If number is <0,
 // the latest known header is returned.
-func (ec *EthereumClient) GetHeaderByNumber(ctx *Context, number int64) (header *Header, _ error) {
+func (ec *EthereumClient) GetHeaderByNumber(ctx *Context, number int64) (header *Header, err error) {
+	defer recoverError(&err)
 	if number < 0 {
 		rawHeader, err := ec.client.HeaderByNumber(ctx.context, nil)
 		return &Header{rawHeader}, err
@@ -71,48 +73,55 @@ func (ec *EthereumClient) GetHeaderByNumber(ctx *Context, number int64) (header
 }
 
 // GetTransactionByHash returns the transaction with the given hash.
-func (ec *EthereumClient) GetTransactionByHash(ctx *Context, hash *Hash) (tx *Transaction, _ error) {
+func (ec *EthereumClient) GetTransactionByHash(ctx *Context, hash *Hash) (tx *Transaction, err error) {
+	defer recoverError(&err)
 	// TODO(karalabe): handle isPending
 	rawTx, _, err := ec.client.TransactionByHash(ctx.context, hash.hash)
-	return &Transaction{rawTx}, err
+	return &Transaction{tx: rawTx}, err
 }
 
 // GetTransactionByHashIsPending returns if the transaction pending or not.
-func (ec *EthereumClient) GetTransactionByHashIsPending(ctx *Context, hash *Hash) (bool, error) {
+func (ec *EthereumClient) GetTransactionByHashIsPending(ctx *Context, hash *Hash) (_ bool, err error) {
+	defer recoverError(&err)
 	_, isPending, err := ec.client.TransactionByHash(ctx.context, hash.hash)
 	return isPending, err
 }
 
 // GetTransactionSender returns the sender address of a transaction. The transaction must
 // be included in blockchain at the given block and index.
-func (ec *EthereumClient) GetTransactionSender(ctx *Context, tx *Transaction, blockhash *Hash, index int) (sender *Address, _ error) {
+func (ec *EthereumClient) GetTransactionSender(ctx *Context, tx *Transaction, blockhash *Hash, index int) (sender *Address, err error) {
+	defer recoverError(&err)
 	addr, err := ec.client.TransactionSender(ctx.context, tx.tx, blockhash.hash, uint(index))
 	return &Address{addr}, err
 }
 
 // GetTransactionCount returns the total number of transactions in the given block.
-func (ec *EthereumClient) GetTransactionCount(ctx *Context, hash *Hash) (count int, _ error) {
+func (ec *EthereumClient) GetTransactionCount(ctx *Context, hash *Hash) (count int, err error) {
+	defer recoverError(&err)
 	rawCount, err := ec.client.TransactionCount(ctx.context, hash.hash)
 	return int(rawCount), err
 }
 
 // GetTransactionInBlock returns a single transaction at index in the given block.
-func (ec *EthereumClient) GetTransactionInBlock(ctx *Context, hash *Hash, index int) (tx *Transaction, _ error) {
+func (ec *EthereumClient) GetTransactionInBlock(ctx *Context, hash *Hash, index int) (tx *Transaction, err error) {
+	defer recoverError(&err)
 	rawTx, err := ec.client.TransactionInBlock(ctx.context, hash.hash, uint(index))
-	return &Transaction{rawTx}, err
+	return &Transaction{tx: rawTx}, err
 
 }
 
 // GetTransactionReceipt returns the receipt of a transaction by transaction hash.
 // Note that the receipt is not available for pending transactions.
-func (ec *EthereumClient) GetTransactionReceipt(ctx *Context, hash *Hash) (receipt *Receipt, _ error) {
+func (ec *EthereumClient) GetTransactionReceipt(ctx *Context, hash *Hash) (receipt *Receipt, err error) {
+	defer recoverError(&err)
 	rawReceipt, err := ec.client.TransactionReceipt(ctx.context, hash.hash)
 	return &Receipt{rawReceipt}, err
 }
 
 // SyncProgress retrieves the current progress of the sync algorithm. If there's
 // no sync currently running, it returns nil.
-func (ec *EthereumClient) SyncProgress(ctx *Context) (progress *SyncProgress, _ error) {
+func (ec *EthereumClient) SyncProgress(ctx *Context) (progress *SyncProgress, err error) {
+	defer recoverError(&err)
 	rawProgress, err := ec.client.SyncProgress(ctx.context)
 	if rawProgress == nil {
 		return nil, err
@@ -129,7 +138,8 @@ type NewHeadHandler interface {
 
 // SubscribeNewHead subscribes to notifications about the current blockchain head
 // on the given channel.
-func (ec *EthereumClient) SubscribeNewHead(ctx *Context, handler NewHeadHandler, buffer int) (sub *Subscription, _ error) {
+func (ec *EthereumClient) SubscribeNewHead(ctx *Context, handler NewHeadHandler, buffer int) (sub *Subscription, err error) {
+	defer recoverError(&err)
 	// Subscribe to the event internally
 	ch := make(chan *types.Header, buffer)
 	rawSub, err := ec.client.SubscribeNewHead(ctx.context, ch)
@@ -158,48 +168,48 @@ func (ec *EthereumClient) SubscribeNewHead(ctx *Context, handler NewHeadHandler,
 
 // GetBalanceAt returns the wei balance of the given account.
 // The block number can be <0, in which case the balance is taken from the latest known block.
-func (ec *EthereumClient) GetBalanceAt(ctx *Context, account *Address, number int64) (balance *BigInt, _ error) {
-	if number < 0 {
-		rawBalance, err := ec.client.BalanceAt(ctx.context, account.address, nil)
-		return &BigInt{rawBalance}, err
-	}
-	rawBalance, err := ec.client.BalanceAt(ctx.context, account.address, big.NewInt(number))
-	return &BigInt{rawBalance}, err
+//
+// Deprecated: use BalanceAt, which this method calls.
+func (ec *EthereumClient) GetBalanceAt(ctx *Context, account *Address, number int64) (balance *BigInt, err error) {
+	defer recoverError(&err)
+	return balanceAt(ctx.context, ec.client, account.address, number)
 }
 
 // GetStorageAt returns the value of key in the contract storage of the given account.
 // The block number can be <0, in which case the value is taken from the latest known block.
-func (ec *EthereumClient) GetStorageAt(ctx *Context, account *Address, key *Hash, number int64) (storage []byte, _ error) {
-	if number < 0 {
-		return ec.client.StorageAt(ctx.context, account.address, key.hash, nil)
-	}
-	return ec.client.StorageAt(ctx.context, account.address, key.hash, big.NewInt(number))
+//
+// Deprecated: use StorageAt, which this method calls.
+func (ec *EthereumClient) GetStorageAt(ctx *Context, account *Address, key *Hash, number int64) (storage []byte, err error) {
+	defer recoverError(&err)
+	return storageAt(ctx.context, ec.client, account.address, key.hash, number)
 }
 
 // GetCodeAt returns the contract code of the given account.
 // The block number can be <0, in which case the code is taken from the latest known block.
-func (ec *EthereumClient) GetCodeAt(ctx *Context, account *Address, number int64) (code []byte, _ error) {
-	if number < 0 {
-		return ec.client.CodeAt(ctx.context, account.address, nil)
-	}
-	return ec.client.CodeAt(ctx.context, account.address, big.NewInt(number))
+//
+// Deprecated: use CodeAt, which this method calls.
+func (ec *EthereumClient) GetCodeAt(ctx *Context, account *Address, number int64) (code []byte, err error) {
+	defer recoverError(&err)
+	return codeAt(ctx.context, ec.client, account.address, number)
 }
 
 // GetNonceAt returns the account nonce of the given account.
 // The block number can be <0, in which case the nonce is taken from the latest known block.
-func (ec *EthereumClient) GetNonceAt(ctx *Context, account *Address, number int64) (nonce int64, _ error) {
-	if number < 0 {
-		rawNonce, err := ec.client.NonceAt(ctx.context, account.address, nil)
-		return int64(rawNonce), err
-	}
-	rawNonce, err := ec.client.NonceAt(ctx.context, account.address, big.NewInt(number))
-	return int64(rawNonce), err
+//
+// Deprecated: use NonceAt, which this method calls.
+func (ec *EthereumClient) GetNonceAt(ctx *Context, account *Address, number int64) (nonce int64, err error) {
+	defer recoverError(&err)
+	return nonceAt(ctx.context, ec.client, account.address, number)
 }
 
 // Filters
 
 // FilterLogs executes a filter query.
-func (ec *EthereumClient) FilterLogs(ctx *Context, query *FilterQuery) (logs *Logs, _ error) {
+func (ec *EthereumClient) FilterLogs(ctx *Context, query *FilterQuery) (logs *Logs, err error) {
+	defer recoverError(&err)
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
 	rawLogs, err := ec.client.FilterLogs(ctx.context, query.query)
 	if err != nil {
 		return nil, err
@@ -220,7 +230,11 @@ type FilterLogsHandler interface {
 }
 
 // SubscribeFilterLogs subscribes to the results of a streaming filter query.
-func (ec *EthereumClient) SubscribeFilterLogs(ctx *Context, query *FilterQuery, handler FilterLogsHandler, buffer int) (sub *Subscription, _ error) {
+func (ec *EthereumClient) SubscribeFilterLogs(ctx *Context, query *FilterQuery, handler FilterLogsHandler, buffer int) (sub *Subscription, err error) {
+	defer recoverError(&err)
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
 	// Subscribe to the event internally
 	ch := make(chan types.Log, buffer)
 	rawSub, err := ec.client.SubscribeFilterLogs(ctx.context, query.query, ch)
@@ -248,75 +262,78 @@ func (ec *EthereumClient) SubscribeFilterLogs(ctx *Context, query *FilterQuery,
 // Pending State
 
 // GetPendingBalanceAt returns the wei balance of the given account in the pending state.
-func (ec *EthereumClient) GetPendingBalanceAt(ctx *Context, account *Address) (balance *BigInt, _ error) {
-	rawBalance, err := ec.client.PendingBalanceAt(ctx.context, account.address)
-	return &BigInt{rawBalance}, err
+//
+// Deprecated: use PendingBalanceAt, which this method calls.
+func (ec *EthereumClient) GetPendingBalanceAt(ctx *Context, account *Address) (balance *BigInt, err error) {
+	return ec.PendingBalanceAt(ctx, account)
 }
 
 // GetPendingStorageAt returns the value of key in the contract storage of the given account in the pending state.
-func (ec *EthereumClient) GetPendingStorageAt(ctx *Context, account *Address, key *Hash) (storage []byte, _ error) {
-	return ec.client.PendingStorageAt(ctx.context, account.address, key.hash)
+//
+// Deprecated: use PendingStorageAt, which this method calls.
+func (ec *EthereumClient) GetPendingStorageAt(ctx *Context, account *Address, key *Hash) (storage []byte, err error) {
+	return ec.PendingStorageAt(ctx, account, key)
 }
 
 // GetPendingCodeAt returns the contract code of the given account in the pending state.
-func (ec *EthereumClient) GetPendingCodeAt(ctx *Context, account *Address) (code []byte, _ error) {
-	return ec.client.PendingCodeAt(ctx.context, account.address)
+//
+// Deprecated: use PendingCodeAt, which this method calls.
+func (ec *EthereumClient) GetPendingCodeAt(ctx *Context, account *Address) (code []byte, err error) {
+	return ec.PendingCodeAt(ctx, account)
 }
 
 // GetPendingNonceAt returns the account nonce of the given account in the pending state.
 // This is the nonce that should be used for the next transaction.
-func (ec *EthereumClient) GetPendingNonceAt(ctx *Context, account *Address) (nonce int64, _ error) {
-	rawNonce, err := ec.client.PendingNonceAt(ctx.context, account.address)
-	return int64(rawNonce), err
+//
+// Deprecated: use PendingNonceAt, which this method calls.
+func (ec *EthereumClient) GetPendingNonceAt(ctx *Context, account *Address) (nonce int64, err error) {
+	return ec.PendingNonceAt(ctx, account)
 }
 
 // GetPendingTransactionCount returns the total number of transactions in the pending state.
-func (ec *EthereumClient) GetPendingTransactionCount(ctx *Context) (count int, _ error) {
+func (ec *EthereumClient) GetPendingTransactionCount(ctx *Context) (count int, err error) {
+	defer recoverError(&err)
 	rawCount, err := ec.client.PendingTransactionCount(ctx.context)
 	return int(rawCount), err
 }
 
-// Contract Calling
-
-// CallContract executes a message call transaction, which is directly executed in the VM
-// of the node, but never mined into the blockchain.
-//
-// blockNumber selects the block height at which the call runs. It can be <0, in which
-// case the code is taken from the latest known block. Note that state from very old
-// blocks might not be available.
-func (ec *EthereumClient) CallContract(ctx *Context, msg *CallMsg, number int64) (output []byte, _ error) {
-	if number < 0 {
-		return ec.client.CallContract(ctx.context, msg.msg, nil)
+// SendTransactionWithSigner signs the transaction through an external signer,
+// see SignTransactionWithSigner, and injects it into the pending pool. The
+// signed transaction is returned to track it by hash. A nil chainID signs for
+// the chain ID of the node, see ChainID.
+func (ec *EthereumClient) SendTransactionWithSigner(ctx *Context, tx *Transaction, signer HashSigner, chainID *BigInt) (_ *Transaction, err error) {
+	defer recoverError(&err)
+	if chainID == nil {
+		if chainID, err = ec.ChainID(ctx); err != nil {
+			return nil, err
+		}
 	}
-	return ec.client.CallContract(ctx.context, msg.msg, big.NewInt(number))
-}
-
-// PendingCallContract executes a message call transaction using the EVM.
-// The state seen by the contract call is the pending state.
-func (ec *EthereumClient) PendingCallContract(ctx *Context, msg *CallMsg) (output []byte, _ error) {
-	return ec.client.PendingCallContract(ctx.context, msg.msg)
-}
-
-// SuggestGasPrice retrieves the currently suggested gas price to allow a timely
-// execution of a transaction.
-func (ec *EthereumClient) SuggestGasPrice(ctx *Context) (price *BigInt, _ error) {
-	rawPrice, err := ec.client.SuggestGasPrice(ctx.context)
-	return &BigInt{rawPrice}, err
+	signed, err := SignTransactionWithSigner(tx, signer, chainID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ec.client.SendTransaction(ctx.context, signed.tx); err != nil {
+		return nil, err
+	}
+	return signed, nil
 }
 
-// EstimateGas tries to estimate the gas needed to execute a specific transaction based on
-// the current pending state of the backend blockchain. There is no guarantee that this is
-// the true gas limit requirement as other transactions may be added or removed by miners,
-// but it should provide a basis for setting a reasonable default.
-func (ec *EthereumClient) EstimateGas(ctx *Context, msg *CallMsg) (gas int64, _ error) {
-	rawGas, err := ec.client.EstimateGas(ctx.context, msg.msg)
-	return int64(rawGas), err
+// SignTransaction signs the transaction with the given hex encoded private key
+// for the chain ID of the node, see ChainID and SignTransaction.
+func (ec *EthereumClient) SignTransaction(ctx *Context, tx *Transaction, privKeyHex string) (_ *Transaction, err error) {
+	defer recoverError(&err)
+	chainID, err := ec.ChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return SignTransaction(tx, privKeyHex, chainID)
 }
 
 // SendTransaction injects a signed transaction into the pending pool for execution.
 //
 // If the transaction was a contract creation use the TransactionReceipt method to get the
 // contract address after the transaction has been mined.
-func (ec *EthereumClient) SendTransaction(ctx *Context, tx *Transaction) error {
+func (ec *EthereumClient) SendTransaction(ctx *Context, tx *Transaction) (err error) {
+	defer recoverError(&err)
 	return ec.client.SendTransaction(ctx.context, tx.tx)
 }
@@ -19,10 +19,19 @@
 package web3go
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
 
 	ethereum "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 // Subscription represents an event subscription where events are
@@ -54,10 +63,16 @@ func (msg *CallMsg) GetFrom() *Address { return &Address{msg.msg.From} }
 func (msg *CallMsg) GetGas() int64 { return int64(msg.msg.Gas) }
 
 // GetGasPrice ...
-func (msg *CallMsg) GetGasPrice() *BigInt { return &BigInt{msg.msg.GasPrice} }
+func (msg *CallMsg) GetGasPrice() *BigInt { return &BigInt{copyBig(msg.msg.GasPrice)} }
+
+// GetGasTipCap ...
+func (msg *CallMsg) GetGasTipCap() *BigInt { return &BigInt{copyBig(msg.msg.GasTipCap)} }
+
+// GetGasFeeCap ...
+func (msg *CallMsg) GetGasFeeCap() *BigInt { return &BigInt{copyBig(msg.msg.GasFeeCap)} }
 
 // GetValue ...
-func (msg *CallMsg) GetValue() *BigInt { return &BigInt{msg.msg.Value} }
+func (msg *CallMsg) GetValue() *BigInt { return &BigInt{copyBig(msg.msg.Value)} }
 
 // GetData ...
 func (msg *CallMsg) GetData() []byte { return msg.msg.Data }
@@ -77,10 +92,16 @@ func (msg *CallMsg) SetFrom(address *Address) { msg.msg.From = address.address }
 func (msg *CallMsg) SetGas(gas int64) { msg.msg.Gas = uint64(gas) }
 
 // SetGasPrice ...
-func (msg *CallMsg) SetGasPrice(price *BigInt) { msg.msg.GasPrice = price.bigint }
+func (msg *CallMsg) SetGasPrice(price *BigInt) { msg.msg.GasPrice = copyBig(price.bigint) }
+
+// SetGasTipCap ...
+func (msg *CallMsg) SetGasTipCap(tipCap *BigInt) { msg.msg.GasTipCap = copyBig(tipCap.bigint) }
+
+// SetGasFeeCap ...
+func (msg *CallMsg) SetGasFeeCap(feeCap *BigInt) { msg.msg.GasFeeCap = copyBig(feeCap.bigint) }
 
 // SetValue ...
-func (msg *CallMsg) SetValue(value *BigInt) { msg.msg.Value = value.bigint }
+func (msg *CallMsg) SetValue(value *BigInt) { msg.msg.Value = copyBig(value.bigint) }
 
 // SetData ...
 func (msg *CallMsg) SetData(data []byte) { msg.msg.Data = common.CopyBytes(data) }
@@ -94,6 +115,22 @@ func (msg *CallMsg) SetTo(address *Address) {
 	msg.msg.To = &address.address
 }
 
+// AddAccessTuple adds the address and its storage slots to the EIP-2930 access
+// list of the call.
+func (msg *CallMsg) AddAccessTuple(address *Address, storageKeys *Hashes) {
+	tuple := types.AccessTuple{Address: address.address, StorageKeys: []common.Hash{}}
+	if storageKeys != nil {
+		tuple.StorageKeys = append(tuple.StorageKeys, storageKeys.hashes...)
+	}
+	msg.msg.AccessList = append(msg.msg.AccessList, tuple)
+}
+
+// GetAccessListSize returns the number of addresses in the access list of the call.
+func (msg *CallMsg) GetAccessListSize() int { return len(msg.msg.AccessList) }
+
+// ClearAccessList removes all entries from the access list of the call.
+func (msg *CallMsg) ClearAccessList() { msg.msg.AccessList = nil }
+
 // SyncProgress gives progress indications when the node is synchronising with
 // the Ethereum network.
 type SyncProgress struct {
@@ -157,7 +194,8 @@ func (t *Topics) Append(topics *Hashes) {
 	t.topics = append(t.topics, topics.hashes)
 }
 
-// FilterQuery contains options for contract log filtering.
+// FilterQuery contains options for contract log filtering. A query selects
+// either a block range or a single block by hash, never both.
 type FilterQuery struct {
 	query ethereum.FilterQuery
 }
@@ -168,10 +206,19 @@ func NewFilterQuery() *FilterQuery {
 }
 
 // GetFromBlock ...
-func (fq *FilterQuery) GetFromBlock() *BigInt { return &BigInt{fq.query.FromBlock} }
+func (fq *FilterQuery) GetFromBlock() *BigInt { return &BigInt{copyBig(fq.query.FromBlock)} }
 
 // GetToBlock ...
-func (fq *FilterQuery) GetToBlock() *BigInt { return &BigInt{fq.query.ToBlock} }
+func (fq *FilterQuery) GetToBlock() *BigInt { return &BigInt{copyBig(fq.query.ToBlock)} }
+
+// GetBlockHash returns the hash of the single block the query is restricted to,
+// or nil if it selects a block range.
+func (fq *FilterQuery) GetBlockHash() *Hash {
+	if fq.query.BlockHash == nil {
+		return nil
+	}
+	return &Hash{*fq.query.BlockHash}
+}
 
 // GetAddresses ...
 func (fq *FilterQuery) GetAddresses() *Addresses { return &Addresses{fq.query.Addresses} }
@@ -179,14 +226,168 @@ func (fq *FilterQuery) GetAddresses() *Addresses { return &Addresses{fq.query.Ad
 // GetTopics ...
 func (fq *FilterQuery) GetTopics() *Topics { return &Topics{fq.query.Topics} }
 
-// SetFromBlock ...
-func (fq *FilterQuery) SetFromBlock(fromBlock *BigInt) { fq.query.FromBlock = fromBlock.bigint }
+// SetFromBlock sets the first block of the range to filter, inclusive.
+func (fq *FilterQuery) SetFromBlock(number int64) { fq.query.FromBlock = big.NewInt(number) }
+
+// SetToBlock sets the last block of the range to filter, inclusive.
+func (fq *FilterQuery) SetToBlock(number int64) { fq.query.ToBlock = big.NewInt(number) }
 
-// SetToBlock ...
-func (fq *FilterQuery) SetToBlock(toBlock *BigInt) { fq.query.ToBlock = toBlock.bigint }
+// SetToLatest makes the range to filter end at the latest block.
+func (fq *FilterQuery) SetToLatest() { fq.query.ToBlock = nil }
+
+// SetBlockHash restricts the query to the single block with the given hash. A
+// nil hash clears the restriction.
+func (fq *FilterQuery) SetBlockHash(hash *Hash) {
+	if hash == nil {
+		fq.query.BlockHash = nil
+		return
+	}
+	h := hash.hash
+	fq.query.BlockHash = &h
+}
+
+// AddAddress adds a contract to the set of log emitters to match.
+func (fq *FilterQuery) AddAddress(address *Address) {
+	fq.query.Addresses = append(fq.query.Addresses, address.address)
+}
 
 // SetAddresses ...
 func (fq *FilterQuery) SetAddresses(addresses *Addresses) { fq.query.Addresses = addresses.addresses }
 
+// SetTopic sets the topics to match at the given position: a log matches if its
+// topic at that position equals any of them. A nil or empty set acts as a
+// wildcard. Lower positions not set yet are filled with wildcards.
+func (fq *FilterQuery) SetTopic(position int, topics *Hashes) error {
+	if position < 0 {
+		return errors.New("negative topic position")
+	}
+	for len(fq.query.Topics) <= position {
+		fq.query.Topics = append(fq.query.Topics, nil)
+	}
+	if topics == nil || len(topics.hashes) == 0 {
+		fq.query.Topics[position] = nil
+	} else {
+		fq.query.Topics[position] = append([]common.Hash(nil), topics.hashes...)
+	}
+	return nil
+}
+
 // SetTopics ...
 func (fq *FilterQuery) SetTopics(topics *Topics) { fq.query.Topics = topics.topics }
+
+// Validate checks that the query does not select both a block range and a block
+// hash, and that the block range is well formed.
+func (fq *FilterQuery) Validate() error {
+	from, to := fq.query.FromBlock, fq.query.ToBlock
+	if fq.query.BlockHash != nil {
+		if from != nil || to != nil {
+			return errors.New("filter query cannot specify both a block range and a block hash")
+		}
+		return nil
+	}
+	if (from != nil && from.Sign() < 0) || (to != nil && to.Sign() < 0) {
+		return errors.New("filter query block number negative")
+	}
+	if from != nil && to != nil && from.Cmp(to) > 0 {
+		return fmt.Errorf("filter query block range inverted: from %v > to %v", from, to)
+	}
+	return nil
+}
+
+// EncodeJSON encodes the query into the JSON parameter object of eth_getLogs, as
+// sent to the node. An open range starts at the genesis block and ends at the
+// latest block.
+func (fq *FilterQuery) EncodeJSON() (_ string, err error) {
+	defer recoverError(&err)
+	if err := fq.Validate(); err != nil {
+		return "", err
+	}
+	arg := map[string]interface{}{
+		"address": fq.query.Addresses,
+		"topics":  fq.query.Topics,
+	}
+	if fq.query.Addresses == nil {
+		arg["address"] = []common.Address{}
+	}
+	if fq.query.Topics == nil {
+		arg["topics"] = [][]common.Hash{}
+	}
+	if fq.query.BlockHash != nil {
+		arg["blockHash"] = *fq.query.BlockHash
+	} else {
+		arg["fromBlock"] = "0x0"
+		if fq.query.FromBlock != nil {
+			arg["fromBlock"] = hexutil.EncodeBig(fq.query.FromBlock)
+		}
+		arg["toBlock"] = "latest"
+		if fq.query.ToBlock != nil {
+			arg["toBlock"] = hexutil.EncodeBig(fq.query.ToBlock)
+		}
+	}
+	data, err := json.Marshal(arg)
+	return string(data), err
+}
+
+// eventSignature matches an event name followed by its parenthesized parameter
+// list.
+var eventSignature = regexp.MustCompile(`^([A-Za-z_$][A-Za-z0-9_$]*)\((.*)\)$`)
+
+// TopicFromEventSignature returns the topic identifying an event, i.e. the
+// keccak256 of its canonical signature such as "Transfer(address,address,uint256)".
+// Whitespace is removed before hashing, but parameter names, the indexed keyword
+// and type aliases like uint are rejected, as they would yield a different hash.
+func TopicFromEventSignature(sig string) (_ *Hash, err error) {
+	defer recoverError(&err)
+	match := eventSignature.FindStringSubmatch(strings.TrimSpace(sig))
+	if match == nil {
+		return nil, fmt.Errorf("invalid event signature %q", sig)
+	}
+	depth := 0
+	for _, token := range strings.FieldsFunc(match[2], func(r rune) bool {
+		switch r {
+		case '(':
+			depth++
+			return true
+		case ')':
+			depth--
+			return true
+		}
+		return r == ','
+	}) {
+		token = strings.TrimSpace(token)
+		if strings.ContainsAny(token, " \t\r\n") {
+			return nil, fmt.Errorf("invalid event signature %q: parameter names are not allowed", sig)
+		}
+		if base := typedDataArray.ReplaceAllString(token, ""); base != "" && (!typedDataPrimitive.MatchString(base) || base == "int" || base == "uint") {
+			return nil, fmt.Errorf("invalid event signature %q: non-canonical type %q", sig, token)
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("invalid event signature %q: unbalanced parentheses", sig)
+	}
+	canonical := strings.Join(strings.Fields(sig), "")
+	return &Hash{crypto.Keccak256Hash([]byte(canonical))}, nil
+}
+
+// TopicFromAddress returns the topic of an indexed address parameter, i.e. the
+// address left-padded to 32 bytes.
+func TopicFromAddress(addr *Address) *Hash {
+	return &Hash{common.BytesToHash(addr.address[:])}
+}
+
+// TopicFromBigInt returns the topic of an indexed integer parameter, i.e. the
+// big-endian 32 byte encoding of the value, with negative values in two's
+// complement as for int256.
+func TopicFromBigInt(v *BigInt) (_ *Hash, err error) {
+	defer recoverError(&err)
+	if v.bigint.Sign() >= 0 && v.bigint.BitLen() > 256 || v.bigint.Sign() < 0 && new(big.Int).Not(v.bigint).BitLen() > 255 {
+		return nil, fmt.Errorf("integer %v does not fit in a topic", v.bigint)
+	}
+	return &Hash{common.BytesToHash(math.U256Bytes(new(big.Int).Set(v.bigint)))}, nil
+}
+
+// TopicFromHash returns a copy of the hash for use as a topic, e.g. for indexed
+// bytes32 parameters or the keccak256 of indexed dynamic values.
+func TopicFromHash(hash *Hash) *Hash {
+	return &Hash{hash.hash}
+}
@@ -0,0 +1,143 @@
+package web3go
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestFilterQuery(t *testing.T) {
+	token, _ := NewAddressFromHex("0xdac17f958d2ee523a2206206994597c13d831ec7")
+	transfer, _ := NewHashFromHex("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+	approval, _ := NewHashFromHex("0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925")
+	recipient, _ := NewHashFromHex("0x000000000000000000000000a9d1e08c7793af67e9d92fe308d5697fb81d3e43")
+
+	events := NewHashesEmpty()
+	events.Append(transfer)
+	events.Append(approval)
+	recipients := NewHashesEmpty()
+	recipients.Append(recipient)
+
+	query := NewFilterQuery()
+	query.SetFromBlock(100)
+	query.SetToBlock(200)
+	query.AddAddress(token)
+	if err := query.SetTopic(2, recipients); err != nil {
+		t.Fatal(err)
+	}
+	if err := query.SetTopic(0, events); err != nil {
+		t.Fatal(err)
+	}
+	data, err := query.EncodeJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"address":["0xdac17f958d2ee523a2206206994597c13d831ec7"],"fromBlock":"0x64","toBlock":"0xc8","topics":[["0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef","0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925"],null,["0x000000000000000000000000a9d1e08c7793af67e9d92fe308d5697fb81d3e43"]]}`
+	if data != want {
+		t.Errorf("JSON mismatch:\nhave %s\nwant %s", data, want)
+	}
+	// Clearing a position turns it into a wildcard
+	query.SetTopic(2, NewHashesEmpty())
+	query.SetToLatest()
+	data, _ = query.EncodeJSON()
+	want = `{"address":["0xdac17f958d2ee523a2206206994597c13d831ec7"],"fromBlock":"0x64","toBlock":"latest","topics":[["0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef","0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925"],null,null]}`
+	if data != want {
+		t.Errorf("JSON mismatch:\nhave %s\nwant %s", data, want)
+	}
+	if err := query.SetTopic(-1, events); err == nil {
+		t.Error("expected error for negative topic position")
+	}
+	// A block hash excludes a block range
+	query.SetBlockHash(transfer)
+	if err := query.Validate(); err == nil {
+		t.Error("expected error for block hash with block range")
+	}
+	if _, err := query.EncodeJSON(); err == nil {
+		t.Error("expected encoding error for block hash with block range")
+	}
+	byHash := NewFilterQuery()
+	byHash.SetBlockHash(transfer)
+	data, err = byHash.EncodeJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = `{"address":[],"blockHash":"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef","topics":[]}`
+	if data != want {
+		t.Errorf("JSON mismatch:\nhave %s\nwant %s", data, want)
+	}
+	if byHash.GetBlockHash().GetHex() != transfer.GetHex() {
+		t.Error("block hash mismatch")
+	}
+	inverted := NewFilterQuery()
+	inverted.SetFromBlock(10)
+	inverted.SetToBlock(5)
+	if err := inverted.Validate(); err == nil {
+		t.Error("expected error for inverted range")
+	}
+}
+
+func TestTopicHelpers(t *testing.T) {
+	tests := []struct {
+		sig  string
+		want string
+	}{
+		{"Transfer(address,address,uint256)", "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"},
+		{" Transfer( address, address,\tuint256 ) ", "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"},
+		{"Approval(address,address,uint256)", "0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925"},
+	}
+	for _, tt := range tests {
+		topic, err := TopicFromEventSignature(tt.sig)
+		if err != nil {
+			t.Errorf("%q: %v", tt.sig, err)
+			continue
+		}
+		if topic.GetHex() != tt.want {
+			t.Errorf("%q: have %s, want %s", tt.sig, topic.GetHex(), tt.want)
+		}
+	}
+	for _, sig := range []string{
+		"Transfer(address from,address to,uint256 value)",
+		"Transfer(address indexed,address,uint256)",
+		"Transfer(address,address,uint)",
+		"Transfer(address,address,uint256",
+		"Transfer(address,(address,uint256),uint256",
+		"(address)",
+		"Transfer",
+	} {
+		if _, err := TopicFromEventSignature(sig); err == nil {
+			t.Errorf("%q: expected error", sig)
+		}
+	}
+	if _, err := TopicFromEventSignature("Batch((address,uint256)[],bytes32[2])"); err != nil {
+		t.Errorf("tuple signature rejected: %v", err)
+	}
+	addr, _ := NewAddressFromHex("0xa9d1e08c7793af67e9d92fe308d5697fb81d3e43")
+	if have, want := TopicFromAddress(addr).GetHex(), "0x000000000000000000000000a9d1e08c7793af67e9d92fe308d5697fb81d3e43"; have != want {
+		t.Errorf("address topic mismatch: have %s, want %s", have, want)
+	}
+	ints := []struct {
+		v    *BigInt
+		want string
+	}{
+		{NewBigInt(1000000), "0x00000000000000000000000000000000000000000000000000000000000f4240"},
+		{NewBigInt(-1), "0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"},
+	}
+	for _, tt := range ints {
+		topic, err := TopicFromBigInt(tt.v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if topic.GetHex() != tt.want {
+			t.Errorf("integer topic mismatch: have %s, want %s", topic.GetHex(), tt.want)
+		}
+	}
+	if _, err := TopicFromBigInt(&BigInt{new(big.Int).Lsh(big.NewInt(1), 256)}); err == nil {
+		t.Error("expected error for 257 bit integer")
+	}
+	if _, err := TopicFromBigInt(&BigInt{new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 255))}); err != nil {
+		t.Errorf("minimum int256 rejected: %v", err)
+	}
+	hash, _ := NewHashFromHex("0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925")
+	if copied := TopicFromHash(hash); copied == hash || copied.GetHex() != hash.GetHex() {
+		t.Error("hash topic mismatch")
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2019 The bcl-chain Authors. All rights reserved.
+
+// Contains the replay of failed transactions to recover their revert reason.
+
+package web3go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	errTransactionPending   = errors.New("transaction is pending")
+	errTransactionSucceeded = errors.New("transaction did not fail")
+	errReplaySucceeded      = errors.New("transaction did not revert when replayed, it may have run out of gas or depended on earlier transactions in its block")
+)
+
+// prunedStateErrors are fragments of the errors nodes return when the state
+// needed to execute a call has been pruned.
+var prunedStateErrors = []string{
+	"missing trie node",
+	"state not available",
+	"historical state",
+	"state histories",
+	"pruned",
+}
+
+// failureBackend is the part of the client API used to look up failed
+// transactions. The replay itself goes through the raw eth_call of the
+// callBackend, as the client drops the fee caps and the access list.
+type failureBackend interface {
+	TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error)
+	TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error)
+	TransactionSender(ctx context.Context, tx *types.Transaction, block common.Hash, index uint) (common.Address, error)
+}
+
+// FailureReason explains why a mined transaction failed, by replaying it with its
+// original sender, gas, value and data on top of the state of the parent of its
+// block, and decoding the revert reason.
+//
+// Distinct errors are returned if the transaction is pending or succeeded, if
+// the node no longer has the state needed for the replay, if the replay does not
+// revert, and if the revert data cannot be decoded.
+func (ec *EthereumClient) FailureReason(ctx *Context, txHash *Hash) (_ string, err error) {
+	defer recoverError(&err)
+	return failureReason(ctx.context, ec.client, ec.client.Client(), txHash.hash)
+}
+
+func failureReason(ctx context.Context, backend failureBackend, caller callBackend, hash common.Hash) (string, error) {
+	tx, pending, err := backend.TransactionByHash(ctx, hash)
+	if err != nil {
+		return "", err
+	}
+	if pending {
+		return "", errTransactionPending
+	}
+	receipt, err := backend.TransactionReceipt(ctx, hash)
+	if err != nil {
+		return "", err
+	}
+	if receipt.Status == types.ReceiptStatusSuccessful {
+		return "", errTransactionSucceeded
+	}
+	if receipt.BlockNumber == nil || receipt.BlockNumber.Sign() == 0 {
+		return "", errors.New("receipt block number missing")
+	}
+	from, err := backend.TransactionSender(ctx, tx, receipt.BlockHash, receipt.TransactionIndex)
+	if err != nil {
+		return "", err
+	}
+	msg := ethereum.CallMsg{
+		From:       from,
+		To:         tx.To(),
+		Gas:        tx.Gas(),
+		Value:      tx.Value(),
+		Data:       tx.Data(),
+		AccessList: tx.AccessList(),
+	}
+	if tx.Type() == types.LegacyTxType || tx.Type() == types.AccessListTxType {
+		msg.GasPrice = tx.GasPrice()
+	} else {
+		msg.GasFeeCap, msg.GasTipCap = tx.GasFeeCap(), tx.GasTipCap()
+	}
+	parent := new(big.Int).Sub(receipt.BlockNumber, common.Big1)
+	if _, err = callContract(ctx, caller, msg, hexutil.EncodeBig(parent)); err == nil {
+		return "", errReplaySucceeded
+	}
+	for _, fragment := range prunedStateErrors {
+		if strings.Contains(err.Error(), fragment) {
+			return "", fmt.Errorf("state of block %v not available, the node may have pruned it: %v", parent, err)
+		}
+	}
+	dataErr, ok := err.(rpc.DataError)
+	if !ok {
+		return "", err
+	}
+	hex, _ := dataErr.ErrorData().(string)
+	data, decErr := decodeHex(hex)
+	if decErr != nil {
+		return "", fmt.Errorf("%v: invalid revert data %q", err, hex)
+	}
+	return DecodeRevertReason(data)
+}
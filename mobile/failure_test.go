@@ -0,0 +1,127 @@
+package web3go
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// testFailureBackend serves a single mined transaction.
+type testFailureBackend struct {
+	tx      *types.Transaction
+	pending bool
+	receipt *types.Receipt
+}
+
+func (b *testFailureBackend) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	return b.tx, b.pending, nil
+}
+
+func (b *testFailureBackend) TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	return b.receipt, nil
+}
+
+func (b *testFailureBackend) TransactionSender(ctx context.Context, tx *types.Transaction, block common.Hash, index uint) (common.Address, error) {
+	return types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+}
+
+// testReplayService mocks eth_call, recording the replayed call as it arrives
+// over the wire and failing it with the configured error.
+type testReplayService struct {
+	err   error
+	args  map[string]interface{}
+	block rpc.BlockNumberOrHash
+}
+
+func (s *testReplayService) Call(args map[string]interface{}, block rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	s.args, s.block = args, block
+	return nil, s.err
+}
+
+func TestFailureReason(t *testing.T) {
+	key, _ := crypto.HexToECDSA(testKeyHex)
+	to := common.HexToAddress("0x095e7baea6a6c7c4c2dfeb977efac326af552d87")
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(common.Big1), &types.DynamicFeeTx{
+		ChainID:    common.Big1,
+		Nonce:      2,
+		GasTipCap:  big.NewInt(1500000000),
+		GasFeeCap:  big.NewInt(30000000000),
+		Gas:        60000,
+		To:         &to,
+		Value:      big.NewInt(2),
+		Data:       []byte{0xde, 0xad},
+		AccessList: types.AccessList{{Address: to, StorageKeys: []common.Hash{{0x01}}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	newBackend := func(status uint64) *testFailureBackend {
+		return &testFailureBackend{
+			tx:      tx,
+			receipt: &types.Receipt{Status: status, BlockNumber: big.NewInt(100)},
+		}
+	}
+	service := &testReplayService{err: &testDataError{"execution reverted", testRevertPanic}}
+	caller := newTestRPCClient(t, service).Client()
+
+	reason, err := failureReason(context.Background(), newBackend(types.ReceiptStatusFailed), caller, tx.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reason != "arithmetic underflow or overflow" {
+		t.Errorf("reason mismatch: have %q", reason)
+	}
+	// The replay must run with the original parameters on the parent state
+	if number, ok := service.block.Number(); !ok || number != 99 {
+		t.Errorf("replay block mismatch: have %v, want 99", service.block)
+	}
+	want := map[string]interface{}{
+		"from":                 testAddress.address.Hex(),
+		"to":                   to.Hex(),
+		"gas":                  "0xea60",
+		"value":                "0x2",
+		"input":                "0xdead",
+		"maxFeePerGas":         "0x6fc23ac00",
+		"maxPriorityFeePerGas": "0x59682f00",
+	}
+	for field, value := range want {
+		if have, _ := service.args[field].(string); !strings.EqualFold(have, value.(string)) {
+			t.Errorf("replayed %s mismatch: have %v, want %v", field, service.args[field], value)
+		}
+	}
+	if _, ok := service.args["gasPrice"]; ok {
+		t.Errorf("replayed gas price alongside the fee caps: %v", service.args["gasPrice"])
+	}
+	accessList, _ := service.args["accessList"].([]interface{})
+	if len(accessList) != 1 {
+		t.Fatalf("replayed access list mismatch: have %v", service.args["accessList"])
+	}
+	if tuple, _ := accessList[0].(map[string]interface{}); tuple == nil || !strings.EqualFold(tuple["address"].(string), to.Hex()) {
+		t.Errorf("replayed access list mismatch: have %v", accessList[0])
+	}
+	failures := []struct {
+		backend *testFailureBackend
+		callErr error
+		err     string
+	}{
+		{newBackend(types.ReceiptStatusSuccessful), nil, "did not fail"},
+		{&testFailureBackend{tx: tx, pending: true}, nil, "pending"},
+		{newBackend(types.ReceiptStatusFailed), errors.New("missing trie node 1a2b (path ) <nil>"), "not available"},
+		{newBackend(types.ReceiptStatusFailed), nil, "did not revert"},
+		{newBackend(types.ReceiptStatusFailed), &testDataError{"execution reverted", "0xe450d38c"}, "unrecognized revert data 0xe450d38c"},
+	}
+	for i, tt := range failures {
+		service.err = tt.callErr
+		if _, err := failureReason(context.Background(), tt.backend, caller, tx.Hash()); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("failure %d: error mismatch: have %v, want %q", i, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2019 The bcl-chain Authors. All rights reserved.
+
+// Contains the retrieval of the fee history of recent blocks through the client.
+
+package web3go
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// FeeHistoryResult represents the base fees, gas usage and priority fee rewards
+// of a range of consecutive blocks, as returned by eth_feeHistory.
+type FeeHistoryResult struct {
+	oldestBlock  int64
+	baseFees     []*big.Int
+	gasUsedRatio []float64
+	rewards      [][]*big.Int
+}
+
+// GetOldestBlock returns the number of the first block of the range.
+func (fh *FeeHistoryResult) GetOldestBlock() int64 { return fh.oldestBlock }
+
+// GetBaseFees returns the base fee per gas of each block of the range, followed
+// by the one of the block after the newest, so it holds one entry more than the
+// range has blocks. Blocks predating London have a zero base fee.
+func (fh *FeeHistoryResult) GetBaseFees() *BigInts {
+	return &BigInts{copyBigs(fh.baseFees)}
+}
+
+// GetGasUsedRatios returns the ratio of gas used to the gas limit of each block
+// of the range.
+func (fh *FeeHistoryResult) GetGasUsedRatios() *Floats {
+	return &Floats{append([]float64{}, fh.gasUsedRatio...)}
+}
+
+// GetRewards returns the priority fee per gas at each requested percentile of
+// the block at the given index of the range, or nil if the index is out of
+// range. The rewards are empty if no percentiles were requested.
+func (fh *FeeHistoryResult) GetRewards(blockIndex int) *BigInts {
+	if blockIndex < 0 || blockIndex >= len(fh.gasUsedRatio) {
+		return nil
+	}
+	if blockIndex >= len(fh.rewards) {
+		return NewBigIntsEmpty()
+	}
+	return &BigInts{copyBigs(fh.rewards[blockIndex])}
+}
+
+// FeeHistory retrieves the fee history of blockCount blocks ending with the
+// given block. A negative number ends the range at the latest block, or at the
+// block of a tag like PendingBlockNumber. Nodes may return fewer blocks than
+// requested.
+//
+// The rewards are sampled at the given percentiles of the priority fees paid in
+// each block, weighted by gas used. Percentiles must lie between 0 and 100 in
+// ascending order; nil or empty percentiles skip the rewards.
+func (ec *EthereumClient) FeeHistory(ctx *Context, blockCount int, lastBlock int64, rewardPercentiles *Floats) (_ *FeeHistoryResult, err error) {
+	defer recoverError(&err)
+	var percentiles []float64
+	if rewardPercentiles != nil {
+		percentiles = rewardPercentiles.floats
+	}
+	return feeHistory(ctx.context, ec.client.Client(), blockCount, lastBlock, percentiles)
+}
+
+func feeHistory(ctx context.Context, backend callBackend, blockCount int, lastBlock int64, percentiles []float64) (*FeeHistoryResult, error) {
+	if blockCount < 1 {
+		return nil, fmt.Errorf("invalid block count %d", blockCount)
+	}
+	if err := checkRewardPercentiles(percentiles); err != nil {
+		return nil, err
+	}
+	if percentiles == nil {
+		percentiles = []float64{}
+	}
+	var result json.RawMessage
+	if err := backend.CallContext(ctx, &result, "eth_feeHistory", hexutil.Uint(blockCount), blockNumberParam(lastBlock), percentiles); err != nil {
+		return nil, err
+	}
+	return decodeFeeHistory(result, len(percentiles))
+}
+
+// checkRewardPercentiles verifies the percentiles lie between 0 and 100 in
+// ascending order, which nodes reject otherwise.
+func checkRewardPercentiles(percentiles []float64) error {
+	for i, p := range percentiles {
+		if p < 0 || p > 100 {
+			return fmt.Errorf("reward percentile %d out of range: %v", i, p)
+		}
+		if i > 0 && p < percentiles[i-1] {
+			return fmt.Errorf("reward percentile %d not ascending: %v after %v", i, p, percentiles[i-1])
+		}
+	}
+	return nil
+}
+
+// rpcFeeHistory is the JSON encoding of the result of eth_feeHistory.
+type rpcFeeHistory struct {
+	OldestBlock  *hexutil.Big     `json:"oldestBlock"`
+	Reward       [][]*hexutil.Big `json:"reward"`
+	BaseFee      []*hexutil.Big   `json:"baseFeePerGas"`
+	GasUsedRatio []float64        `json:"gasUsedRatio"`
+}
+
+// decodeFeeHistory decodes the result of eth_feeHistory requested with the given
+// number of reward percentiles. Nodes omit the reward field if none were
+// requested, and serve zero base fees for blocks predating London.
+func decodeFeeHistory(data []byte, percentiles int) (*FeeHistoryResult, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, errors.New("missing fee history")
+	}
+	var dec rpcFeeHistory
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return nil, err
+	}
+	if dec.OldestBlock == nil {
+		return nil, errors.New("fee history: missing oldest block")
+	}
+	oldest := (*big.Int)(dec.OldestBlock)
+	if !oldest.IsInt64() {
+		return nil, fmt.Errorf("fee history: oldest block %v overflows int64", oldest)
+	}
+	blocks := len(dec.GasUsedRatio)
+	if blocks > 0 && len(dec.BaseFee) != blocks+1 {
+		return nil, fmt.Errorf("fee history: %d base fees for %d blocks", len(dec.BaseFee), blocks)
+	}
+	fh := &FeeHistoryResult{
+		oldestBlock:  oldest.Int64(),
+		baseFees:     make([]*big.Int, len(dec.BaseFee)),
+		gasUsedRatio: dec.GasUsedRatio,
+	}
+	for i, fee := range dec.BaseFee {
+		if fee == nil {
+			return nil, fmt.Errorf("fee history: missing base fee %d", i)
+		}
+		fh.baseFees[i] = (*big.Int)(fee)
+	}
+	if percentiles == 0 {
+		return fh, nil
+	}
+	if len(dec.Reward) != blocks {
+		return nil, fmt.Errorf("fee history: %d rewards for %d blocks", len(dec.Reward), blocks)
+	}
+	fh.rewards = make([][]*big.Int, blocks)
+	for i, reward := range dec.Reward {
+		if len(reward) != percentiles {
+			return nil, fmt.Errorf("fee history: block %d has %d rewards for %d percentiles", i, len(reward), percentiles)
+		}
+		fh.rewards[i] = make([]*big.Int, percentiles)
+		for j, fee := range reward {
+			if fee == nil {
+				return nil, fmt.Errorf("fee history: missing reward %d of block %d", j, i)
+			}
+			fh.rewards[i][j] = (*big.Int)(fee)
+		}
+	}
+	return fh, nil
+}
@@ -0,0 +1,211 @@
+package web3go
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Generated eth_feeHistory results of three mainnet blocks, requested with the
+// percentiles 25 and 75, without percentiles and across the London fork.
+const (
+	testFeeHistoryRewards = `{
+		"oldestBlock": "0x112a87e",
+		"reward": [
+			["0x5f5e100", "0x77359400"],
+			["0x3b9aca00", "0x9502f900"],
+			["0x2faf080", "0x3b9aca00"]
+		],
+		"baseFeePerGas": ["0x4a817c800", "0x4b4e1f0a0", "0x4a1a7f8c2", "0x4c5d3a1f4"],
+		"gasUsedRatio": [0.5131, 0.4629, 0.6012]
+	}`
+	testFeeHistoryNoRewards = `{
+		"oldestBlock": "0x112a87e",
+		"baseFeePerGas": ["0x4a817c800", "0x4b4e1f0a0", "0x4a1a7f8c2", "0x4c5d3a1f4"],
+		"gasUsedRatio": [0.5131, 0.4629, 0.6012]
+	}`
+	testFeeHistoryLondon = `{
+		"oldestBlock": "0xc5d488",
+		"reward": [["0x0"], ["0x3b9aca00"]],
+		"baseFeePerGas": ["0x0", "0x3b9aca00", "0x3a699d00"],
+		"gasUsedRatio": [0.9972, 0.0141]
+	}`
+)
+
+func TestDecodeFeeHistory(t *testing.T) {
+	tests := []struct {
+		data        string
+		percentiles int
+		oldest      int64
+		baseFees    []string
+		ratios      []float64
+		rewards     [][]string
+	}{
+		{
+			data: testFeeHistoryRewards, percentiles: 2, oldest: 17999998,
+			baseFees: []string{"20000000000", "20214575264", "19892009154", "20498850292"},
+			ratios:   []float64{0.5131, 0.4629, 0.6012},
+			rewards:  [][]string{{"100000000", "2000000000"}, {"1000000000", "2500000000"}, {"50000000", "1000000000"}},
+		},
+		{
+			data: testFeeHistoryNoRewards, oldest: 17999998,
+			baseFees: []string{"20000000000", "20214575264", "19892009154", "20498850292"},
+			ratios:   []float64{0.5131, 0.4629, 0.6012},
+			rewards:  [][]string{{}, {}, {}},
+		},
+		{
+			data: testFeeHistoryLondon, percentiles: 1, oldest: 12965000,
+			baseFees: []string{"0", "1000000000", "980000000"},
+			ratios:   []float64{0.9972, 0.0141},
+			rewards:  [][]string{{"0"}, {"1000000000"}},
+		},
+	}
+	for i, tt := range tests {
+		fh, err := decodeFeeHistory([]byte(tt.data), tt.percentiles)
+		if err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+		if fh.GetOldestBlock() != tt.oldest {
+			t.Errorf("test %d: oldest block mismatch: have %d, want %d", i, fh.GetOldestBlock(), tt.oldest)
+		}
+		baseFees := fh.GetBaseFees()
+		if baseFees.Size() != len(tt.baseFees) {
+			t.Fatalf("test %d: base fee count mismatch: have %d, want %d", i, baseFees.Size(), len(tt.baseFees))
+		}
+		for j, want := range tt.baseFees {
+			if fee, _ := baseFees.Get(j); fee.String() != want {
+				t.Errorf("test %d: base fee %d mismatch: have %v, want %s", i, j, fee, want)
+			}
+		}
+		ratios := fh.GetGasUsedRatios()
+		if ratios.Size() != len(tt.ratios) {
+			t.Fatalf("test %d: ratio count mismatch: have %d, want %d", i, ratios.Size(), len(tt.ratios))
+		}
+		for j, want := range tt.ratios {
+			if ratio, _ := ratios.Get(j); ratio != want {
+				t.Errorf("test %d: ratio %d mismatch: have %v, want %v", i, j, ratio, want)
+			}
+		}
+		for j, want := range tt.rewards {
+			rewards := fh.GetRewards(j)
+			if rewards == nil || rewards.Size() != len(want) {
+				t.Fatalf("test %d: block %d reward count mismatch: have %v, want %d", i, j, rewards, len(want))
+			}
+			for k, reward := range want {
+				if have, _ := rewards.Get(k); have.String() != reward {
+					t.Errorf("test %d: block %d reward %d mismatch: have %v, want %s", i, j, k, have, reward)
+				}
+			}
+		}
+		if fh.GetRewards(-1) != nil || fh.GetRewards(len(tt.ratios)) != nil {
+			t.Errorf("test %d: rewards out of range not nil", i)
+		}
+		// Mutating the returned wrappers must not corrupt the result
+		fee, _ := fh.GetBaseFees().Get(0)
+		fee.SetInt64(1)
+		if have, _ := fh.GetBaseFees().Get(0); have.String() != tt.baseFees[0] {
+			t.Errorf("test %d: base fee corrupted: have %v", i, have)
+		}
+	}
+}
+
+func TestDecodeFeeHistoryInvalid(t *testing.T) {
+	tests := []struct {
+		data        string
+		percentiles int
+	}{
+		{`null`, 0},
+		{`{"baseFeePerGas": ["0x1", "0x1"], "gasUsedRatio": [0.5]}`, 0},
+		{`{"oldestBlock": "0x1", "baseFeePerGas": ["0x1"], "gasUsedRatio": [0.5]}`, 0},
+		{`{"oldestBlock": "0x1", "baseFeePerGas": ["0x1", null], "gasUsedRatio": [0.5]}`, 0},
+		{`{"oldestBlock": "0x1", "baseFeePerGas": ["0x1", "0x1"], "gasUsedRatio": [0.5]}`, 1},
+		{`{"oldestBlock": "0x1", "reward": [["0x1"]], "baseFeePerGas": ["0x1", "0x1"], "gasUsedRatio": [0.5]}`, 2},
+		{`{"oldestBlock": "0x1", "reward": [[null]], "baseFeePerGas": ["0x1", "0x1"], "gasUsedRatio": [0.5]}`, 1},
+		{`{"oldestBlock": "0x8000000000000000", "baseFeePerGas": ["0x1", "0x1"], "gasUsedRatio": [0.5]}`, 0},
+		{`{"oldestBlock": "1", "baseFeePerGas": ["0x1", "0x1"], "gasUsedRatio": [0.5]}`, 0},
+	}
+	for i, tt := range tests {
+		if _, err := decodeFeeHistory([]byte(tt.data), tt.percentiles); err == nil {
+			t.Errorf("test %d: invalid fee history accepted", i)
+		}
+	}
+}
+
+// testFeeHistoryService mocks eth_feeHistory, serving the recorded result
+// matching the number of requested percentiles.
+type testFeeHistoryService struct {
+	blockCount  hexutil.Uint
+	lastBlock   rpc.BlockNumber
+	percentiles []float64
+}
+
+func (s *testFeeHistoryService) FeeHistory(blockCount hexutil.Uint, lastBlock rpc.BlockNumber, percentiles []float64) json.RawMessage {
+	s.blockCount, s.lastBlock, s.percentiles = blockCount, lastBlock, percentiles
+	if len(percentiles) == 0 {
+		return json.RawMessage(testFeeHistoryNoRewards)
+	}
+	return json.RawMessage(testFeeHistoryRewards)
+}
+
+func TestFeeHistory(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		service = new(testFeeHistoryService)
+		client  = newTestRPCClient(t, service).Client()
+	)
+	fh, err := feeHistory(ctx, client, 3, 18000000, []float64{25, 75})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if service.blockCount != 3 || service.lastBlock != 18000000 || len(service.percentiles) != 2 {
+		t.Errorf("request mismatch: count %d, last block %d, percentiles %v", service.blockCount, service.lastBlock, service.percentiles)
+	}
+	if fh.GetOldestBlock() != 17999998 || fh.GetRewards(2).Size() != 2 {
+		t.Errorf("result mismatch: oldest block %d, rewards %v", fh.GetOldestBlock(), fh.GetRewards(2))
+	}
+	// Percentiles are sent as an empty array, not null
+	fh, err = feeHistory(ctx, client, 3, PendingBlockNumber, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if service.lastBlock != rpc.PendingBlockNumber || service.percentiles == nil {
+		t.Errorf("request mismatch: last block %d, percentiles %v", service.lastBlock, service.percentiles)
+	}
+	if fh.GetRewards(0).Size() != 0 {
+		t.Errorf("rewards without percentiles: %v", fh.GetRewards(0))
+	}
+}
+
+func TestFeeHistoryInvalidArgs(t *testing.T) {
+	var (
+		service = new(testFeeHistoryService)
+		client  = newTestRPCClient(t, service).Client()
+	)
+	tests := []struct {
+		blockCount  int
+		percentiles []float64
+		err         string
+	}{
+		{0, nil, "block count"},
+		{1, []float64{-1}, "out of range"},
+		{1, []float64{50, 100.5}, "out of range"},
+		{1, []float64{75, 25}, "not ascending"},
+	}
+	for i, tt := range tests {
+		_, err := feeHistory(context.Background(), client, tt.blockCount, LatestBlockNumber, tt.percentiles)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("test %d: error mismatch: have %v, want %q", i, err, tt.err)
+		}
+	}
+	if service.blockCount != 0 {
+		t.Error("invalid request sent to the node")
+	}
+	// Equal percentiles are ascending
+	if err := checkRewardPercentiles([]float64{0, 50, 50, 100}); err != nil {
+		t.Errorf("valid percentiles rejected: %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2019 The bcl-chain Authors. All rights reserved.
+
+// Contains generators of hashes, addresses, keys and transactions for tests.
+
+package web3go
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/binary"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// FixtureChainID is the chain ID fixture transactions are signed for.
+const FixtureChainID = 1337
+
+// NewRandomHash returns a hash filled with random bytes.
+func NewRandomHash() *Hash {
+	var hash common.Hash
+	rand.Read(hash[:])
+	return &Hash{hash}
+}
+
+// NewRandomAddress returns an address filled with random bytes.
+func NewRandomAddress() *Address {
+	var address common.Address
+	rand.Read(address[:])
+	return &Address{address}
+}
+
+// NewRandomKey generates a new random secp256k1 private key.
+func NewRandomKey() (*PrivateKey, error) {
+	return GenerateKey()
+}
+
+// NewSeededHash returns a hash derived from the seed. The same seed always yields
+// the same hash, different seeds yield unrelated ones.
+func NewSeededHash(seed int64) *Hash {
+	return &Hash{common.BytesToHash(seededBytes("hash", seed, 0))}
+}
+
+// NewSeededAddress returns an address derived from the seed, see NewSeededHash.
+func NewSeededAddress(seed int64) *Address {
+	return &Address{common.BytesToAddress(seededBytes("address", seed, 0))}
+}
+
+// NewSeededKey returns a private key derived from the seed, see NewSeededHash.
+// Such keys are publicly known and must never hold real funds.
+func NewSeededKey(seed int64) (_ *PrivateKey, err error) {
+	defer recoverError(&err)
+	key, err := seededKey(seed)
+	if err != nil {
+		return nil, err
+	}
+	return &PrivateKey{key}, nil
+}
+
+// NewFixtureTransaction returns a valid EIP-1559 transaction with the given
+// nonce, signed for FixtureChainID by the key of NewSeededKey(seed). Its
+// recipient and value are derived from the seed too, while the fees are fixed
+// at a 1 gwei tip cap and a 30 gwei fee cap, so the transaction and its hash are
+// the same on every call.
+func NewFixtureTransaction(nonce int64, seed int64) (_ *Transaction, err error) {
+	defer recoverError(&err)
+	key, err := seededKey(seed)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroKey(key)
+
+	var (
+		chainID = big.NewInt(FixtureChainID)
+		to      = common.BytesToAddress(seededBytes("recipient", seed, 0))
+		value   = new(big.Int).SetBytes(seededBytes("value", seed, 0)[:8])
+	)
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     uint64(nonce),
+		GasTipCap: big.NewInt(1000000000),
+		GasFeeCap: big.NewInt(30000000000),
+		Gas:       21000,
+		To:        &to,
+		Value:     value,
+	})
+	signed, err := types.SignTx(tx, types.LatestSignerForChainID(chainID), key)
+	if err != nil {
+		return nil, err
+	}
+	return &Transaction{tx: signed}, nil
+}
+
+// seededBytes returns 32 bytes derived from the domain, the seed and a counter.
+// Every generator hashes its own domain, so there is no shared random state.
+func seededBytes(domain string, seed int64, counter uint64) []byte {
+	var buf [16]byte
+	binary.BigEndian.PutUint64(buf[:8], uint64(seed))
+	binary.BigEndian.PutUint64(buf[8:], counter)
+	return crypto.Keccak256([]byte(domain), buf[:])
+}
+
+// seededKey derives a private key from the seed, retrying with the next counter
+// in the unlikely case the derived bytes are not a valid scalar.
+func seededKey(seed int64) (*ecdsa.PrivateKey, error) {
+	var err error
+	for counter := uint64(0); counter < 16; counter++ {
+		var key *ecdsa.PrivateKey
+		if key, err = crypto.ToECDSA(seededBytes("key", seed, counter)); err == nil {
+			return key, nil
+		}
+	}
+	return nil, err
+}
@@ -0,0 +1,74 @@
+package web3go
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestSeededFixtures(t *testing.T) {
+	if !NewSeededHash(1).Equals(NewSeededHash(1)) || NewSeededHash(1).Equals(NewSeededHash(2)) {
+		t.Error("seeded hash not deterministic per seed")
+	}
+	if !NewSeededAddress(1).Equals(NewSeededAddress(1)) || NewSeededAddress(1).Equals(NewSeededAddress(-1)) {
+		t.Error("seeded address not deterministic per seed")
+	}
+	key1, err := NewSeededKey(42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key2, _ := NewSeededKey(42)
+	if !bytes.Equal(FromECDSA(key1), FromECDSA(key2)) {
+		t.Error("seeded key not deterministic")
+	}
+	if NewRandomHash().Equals(NewRandomHash()) || NewRandomAddress().Equals(NewRandomAddress()) {
+		t.Error("random fixtures repeat")
+	}
+	if _, err := NewRandomKey(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFixtureTransaction(t *testing.T) {
+	key, _ := NewSeededKey(7)
+	sender := crypto.PubkeyToAddress(key.privateKey.PublicKey)
+
+	var (
+		wg     sync.WaitGroup
+		hashes = make([]string, 8)
+	)
+	for i := range hashes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tx, err := NewFixtureTransaction(3, 7)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			hashes[i] = tx.GetHash().GetHex()
+		}(i)
+	}
+	wg.Wait()
+	for i := range hashes {
+		if hashes[i] != hashes[0] {
+			t.Fatalf("fixture transaction %d not deterministic: have %s, want %s", i, hashes[i], hashes[0])
+		}
+	}
+	tx, _ := NewFixtureTransaction(3, 7)
+	if tx.GetNonce() != 3 {
+		t.Errorf("nonce mismatch: have %d, want 3", tx.GetNonce())
+	}
+	from, err := tx.GetFrom(NewBigInt(FixtureChainID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from.address != sender {
+		t.Errorf("sender mismatch: have %s, want %s", from.GetHex(), sender.Hex())
+	}
+	if other, _ := NewFixtureTransaction(3, 8); other.GetHash().Equals(tx.GetHash()) {
+		t.Error("different seeds produced the same transaction")
+	}
+}
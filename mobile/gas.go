@@ -0,0 +1,113 @@
+// Copyright 2019 The bcl-chain Authors. All rights reserved.
+
+// Contains the estimation of gas limits and prices through the client.
+
+package web3go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ErrNotSupported is returned by SuggestGasTipCap if the node does not support
+// EIP-1559 tips, e.g. before London, in which case the gas price of
+// SuggestGasPrice should be used instead.
+var ErrNotSupported = errors.New("not supported by node")
+
+// SuggestGasPrice retrieves the currently suggested gas price to allow a timely
+// execution of a transaction. For EIP-1559 transactions it is the suggested tip
+// plus the base fee of the latest block. The price is queried on every call.
+func (ec *EthereumClient) SuggestGasPrice(ctx *Context) (price *BigInt, err error) {
+	defer recoverError(&err)
+	return suggestGasPrice(ctx.context, ec.client.Client())
+}
+
+// SuggestGasTipCap retrieves the currently suggested EIP-1559 priority fee per
+// gas to allow a timely execution of a transaction. Nodes without EIP-1559
+// support fail with ErrNotSupported. The tip is queried on every call.
+func (ec *EthereumClient) SuggestGasTipCap(ctx *Context) (tipCap *BigInt, err error) {
+	defer recoverError(&err)
+	return suggestGasTipCap(ctx.context, ec.client.Client())
+}
+
+func suggestGasPrice(ctx context.Context, backend callBackend) (*BigInt, error) {
+	var price hexutil.Big
+	if err := backend.CallContext(ctx, &price, "eth_gasPrice"); err != nil {
+		return nil, err
+	}
+	return &BigInt{(*big.Int)(&price)}, nil
+}
+
+func suggestGasTipCap(ctx context.Context, backend callBackend) (*BigInt, error) {
+	var tipCap hexutil.Big
+	if err := backend.CallContext(ctx, &tipCap, "eth_maxPriorityFeePerGas"); err != nil {
+		if IsMethodNotSupported(methodError("eth_maxPriorityFeePerGas", err)) {
+			return nil, ErrNotSupported
+		}
+		return nil, err
+	}
+	return &BigInt{(*big.Int)(&tipCap)}, nil
+}
+
+// EstimateGas tries to estimate the gas needed to execute a specific transaction based on
+// the current pending state of the backend blockchain. There is no guarantee that this is
+// the true gas limit requirement as other transactions may be added or removed by miners,
+// but it should provide a basis for setting a reasonable default, see
+// EstimateGasWithBuffer.
+//
+// If the transaction reverts, the decoded revert reason is appended to the returned
+// error. Estimates beyond the int64 range fail.
+func (ec *EthereumClient) EstimateGas(ctx *Context, msg *CallMsg) (gas int64, err error) {
+	defer recoverError(&err)
+	return estimateGas(ctx.context, ec.client.Client(), msg.msg)
+}
+
+// EstimateGasWithBuffer estimates the gas needed by the transaction like
+// EstimateGas, adding bufferPercent percent to the estimate for transactions
+// whose gas usage depends on state changing before their inclusion. The result
+// is capped at the gas limit of the latest block, but never below the estimate.
+func (ec *EthereumClient) EstimateGasWithBuffer(ctx *Context, msg *CallMsg, bufferPercent int) (gas int64, err error) {
+	defer recoverError(&err)
+	return estimateGasWithBuffer(ctx.context, ec.client.Client(), ec.client, msg.msg, bufferPercent)
+}
+
+func estimateGas(ctx context.Context, backend callBackend, msg ethereum.CallMsg) (int64, error) {
+	var gas hexutil.Uint64
+	if err := backend.CallContext(ctx, &gas, "eth_estimateGas", callArg(msg)); err != nil {
+		return 0, withRevertReason(err)
+	}
+	return uint64ToInt64(uint64(gas), "gas estimate")
+}
+
+func estimateGasWithBuffer(ctx context.Context, backend callBackend, headers headerBackend, msg ethereum.CallMsg, bufferPercent int) (int64, error) {
+	if bufferPercent < 0 {
+		return 0, fmt.Errorf("invalid gas buffer %d%%", bufferPercent)
+	}
+	gas, err := estimateGas(ctx, backend, msg)
+	if err != nil {
+		return 0, err
+	}
+	head, err := headerByNumber(ctx, headers, LatestBlockNumber)
+	if err != nil {
+		return 0, err
+	}
+	buffered := new(big.Int).Mul(big.NewInt(gas), big.NewInt(100+int64(bufferPercent)))
+	buffered.Div(buffered, big.NewInt(100))
+
+	limit := new(big.Int).SetUint64(head.header.GasLimit)
+	if buffered.Cmp(limit) > 0 {
+		buffered = limit
+	}
+	if !buffered.IsInt64() {
+		return 0, fmt.Errorf("gas estimate %v overflows int64", buffered)
+	}
+	if buffered.Int64() < gas {
+		return gas, nil
+	}
+	return buffered.Int64(), nil
+}
@@ -0,0 +1,137 @@
+package web3go
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// testGasService mocks eth_estimateGas with a fixed estimate, reverting calls
+// without data.
+type testGasService struct {
+	estimate uint64
+}
+
+func (s *testGasService) EstimateGas(args map[string]interface{}) (hexutil.Uint64, error) {
+	if args["input"] == nil {
+		return 0, &testDataError{"execution reverted", testRevertString}
+	}
+	return hexutil.Uint64(s.estimate), nil
+}
+
+// testPriceService mocks the gas price methods of a London node, raising the
+// prices on every query.
+type testPriceService struct {
+	price int64
+}
+
+func (s *testPriceService) GasPrice() *hexutil.Big {
+	s.price++
+	return (*hexutil.Big)(big.NewInt(s.price * 1000000000))
+}
+
+func (s *testPriceService) MaxPriorityFeePerGas() *hexutil.Big {
+	s.price++
+	return (*hexutil.Big)(big.NewInt(s.price * 100000000))
+}
+
+// testLegacyPriceService mocks a node without EIP-1559 support.
+type testLegacyPriceService struct{}
+
+func (s *testLegacyPriceService) GasPrice() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(20000000000))
+}
+
+// testHeadBackend serves a latest header with the given gas limit.
+type testHeadBackend struct {
+	gasLimit uint64
+}
+
+func (b *testHeadBackend) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	return nil, nil
+}
+
+func (b *testHeadBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return &types.Header{Number: big.NewInt(100), GasLimit: b.gasLimit}, nil
+}
+
+func TestEstimateGasWithBuffer(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		service = &testGasService{estimate: 100000}
+		client  = newTestRPCClient(t, service).Client()
+		msg     = NewCallMsg()
+	)
+	msg.SetData([]byte{0x01})
+
+	tests := []struct {
+		estimate uint64
+		buffer   int
+		gasLimit uint64
+		want     int64
+	}{
+		{100000, 0, 30000000, 100000},
+		{100000, 20, 30000000, 120000},
+		{100001, 50, 30000000, 150001},  // Rounded down
+		{100000, 20, 110000, 110000},    // Capped at the block gas limit
+		{100000, 20, 90000, 100000},     // Never below the estimate
+		{21000, 1000, 30000000, 231000}, // Buffers beyond 100%
+	}
+	for i, tt := range tests {
+		service.estimate = tt.estimate
+		gas, err := estimateGasWithBuffer(ctx, client, &testHeadBackend{tt.gasLimit}, msg.msg, tt.buffer)
+		if err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+		if gas != tt.want {
+			t.Errorf("test %d: gas mismatch: have %d, want %d", i, gas, tt.want)
+		}
+	}
+	if _, err := estimateGasWithBuffer(ctx, client, &testHeadBackend{30000000}, msg.msg, -1); err == nil {
+		t.Error("expected error for negative buffer")
+	}
+	service.estimate = math.MaxUint64
+	if _, err := estimateGas(ctx, client, msg.msg); err == nil || err.Error() != "gas estimate 18446744073709551615 overflows int64" {
+		t.Errorf("overflow error mismatch: %v", err)
+	}
+	// Reverts during estimation must carry the decoded reason
+	_, err := estimateGasWithBuffer(ctx, client, &testHeadBackend{30000000}, NewCallMsg().msg, 20)
+	if err == nil || err.Error() != "execution reverted: insufficient balance" {
+		t.Errorf("revert error mismatch: %v", err)
+	}
+}
+
+func TestSuggestGasPrices(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		client = newTestRPCClient(t, new(testPriceService)).Client()
+	)
+	for i, want := range []string{"1000000000", "2000000000"} {
+		price, err := suggestGasPrice(ctx, client)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if price.String() != want {
+			t.Errorf("query %d: gas price mismatch: have %s, want %s", i, price, want)
+		}
+	}
+	tipCap, err := suggestGasTipCap(ctx, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tipCap.String() != "300000000" {
+		t.Errorf("tip cap mismatch: have %s, want 300000000", tipCap)
+	}
+	legacy := newTestRPCClient(t, new(testLegacyPriceService)).Client()
+	if _, err := suggestGasTipCap(ctx, legacy); err != ErrNotSupported {
+		t.Errorf("unsupported tip error mismatch: %v", err)
+	}
+	if price, err := suggestGasPrice(ctx, legacy); err != nil || price.String() != "20000000000" {
+		t.Errorf("fallback gas price mismatch: have %v (%v)", price, err)
+	}
+}
@@ -1,18 +1,37 @@
 package web3go
 
 import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
 	native "github.com/miguelmota/go-ethereum-hdwallet"
 )
 
+// DefaultBaseDerivationPath is the BIP-44 path of the first Ethereum account, as
+// used by MetaMask and Ledger Live. Further accounts increment the last level.
+const DefaultBaseDerivationPath = "m/44'/60'/0'/0/0"
+
+// hardenedKeyStart is the offset of hardened derivation path components.
+const hardenedKeyStart = 0x80000000
+
+// bip44Levels names the levels of a BIP-44 derivation path.
+var bip44Levels = []string{"purpose", "coin type", "account", "change", "address index"}
+
 type Wallet struct {
 	wallet *native.Wallet
 }
 
-func NewMnemonic(bits int) (string, error) {
+func NewMnemonic(bits int) (_ string, err error) {
+	defer recoverError(&err)
 	return native.NewMnemonic(bits)
 }
 
-func NewFromMnemonic(mnemonic string) (*Wallet, error) {
+func NewFromMnemonic(mnemonic string) (_ *Wallet, err error) {
+	defer recoverError(&err)
 	wallet, err := native.NewFromMnemonic(mnemonic)
 	if err != nil {
 		return nil, err
@@ -20,7 +39,8 @@ func NewFromMnemonic(mnemonic string) (*Wallet, error) {
 	return &Wallet{wallet}, nil
 }
 
-func (w *Wallet) Derive(path string, pin bool) (*Account, error) {
+func (w *Wallet) Derive(path string, pin bool) (_ *Account, err error) {
+	defer recoverError(&err)
 	parsed := native.MustParseDerivationPath(path)
 	account, err := w.wallet.Derive(parsed, pin)
 	if err != nil {
@@ -29,14 +49,147 @@ func (w *Wallet) Derive(path string, pin bool) (*Account, error) {
 	return &Account{account}, nil
 }
 
-func (w *Wallet) AddressHex(account *Account) (string, error) {
+func (w *Wallet) AddressHex(account *Account) (_ string, err error) {
+	defer recoverError(&err)
 	return w.wallet.AddressHex(account.account)
 }
 
-func (w *Wallet) PrivateKeyHex(account *Account) (string, error) {
+func (w *Wallet) PrivateKeyHex(account *Account) (_ string, err error) {
+	defer recoverError(&err)
 	return w.wallet.PrivateKeyHex(account.account)
 }
 
-func (w *Wallet) PublicKeyHex(account *Account) (string, error) {
+func (w *Wallet) PublicKeyHex(account *Account) (_ string, err error) {
+	defer recoverError(&err)
 	return w.wallet.PublicKeyHex(account.account)
 }
+
+// DerivationPath is a parsed BIP-44 derivation path.
+type DerivationPath struct {
+	path accounts.DerivationPath
+}
+
+// ParseDerivationPath parses a BIP-44 derivation path like m/44'/60'/0'/0/0. The
+// purpose, coin type and account levels must be hardened (marked with an
+// apostrophe), the optional change and address index levels must not be.
+// Components must fit into 31 bits.
+func ParseDerivationPath(path string) (*DerivationPath, error) {
+	parsed, err := parseBIP44Path(path)
+	if err != nil {
+		return nil, err
+	}
+	return &DerivationPath{parsed}, nil
+}
+
+// String returns the path in its canonical m/44'/60'/... form.
+func (p *DerivationPath) String() string {
+	return p.path.String()
+}
+
+// Size returns the number of levels of the path.
+func (p *DerivationPath) Size() int {
+	return len(p.path)
+}
+
+// Get returns the raw component at the given level, including the 2^31 offset
+// of hardened levels.
+func (p *DerivationPath) Get(index int) (int64, error) {
+	if index < 0 || index >= len(p.path) {
+		return 0, errors.New("index out of bounds")
+	}
+	return int64(p.path[index]), nil
+}
+
+func parseBIP44Path(path string) (accounts.DerivationPath, error) {
+	components := strings.Split(strings.TrimSpace(path), "/")
+	if components[0] != "m" {
+		return nil, fmt.Errorf("derivation path %q must start with m/", path)
+	}
+	levels := components[1:]
+	if len(levels) < 3 || len(levels) > len(bip44Levels) {
+		return nil, fmt.Errorf("derivation path %q must have 3 to %d levels", path, len(bip44Levels))
+	}
+	parsed := make(accounts.DerivationPath, len(levels))
+	for i, level := range levels {
+		digits := strings.TrimSuffix(level, "'")
+		hardened := digits != level
+		value, err := strconv.ParseUint(digits, 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("derivation path %q: invalid %s %q, must be an integer below 2^31", path, bip44Levels[i], level)
+		}
+		switch {
+		case i < 3 && !hardened:
+			return nil, fmt.Errorf("derivation path %q: %s must be hardened, i.e. %s'", path, bip44Levels[i], level)
+		case i >= 3 && hardened:
+			return nil, fmt.Errorf("derivation path %q: %s must not be hardened", path, bip44Levels[i])
+		case i == 0 && value != 44:
+			return nil, fmt.Errorf("derivation path %q: purpose must be 44'", path)
+		}
+		parsed[i] = uint32(value)
+		if hardened {
+			parsed[i] += hardenedKeyStart
+		}
+	}
+	return parsed, nil
+}
+
+// newHDWallet creates a wallet for the BIP-39 mnemonic and password, deriving
+// keys with leading zeros the standard way, like MetaMask and Ledger.
+func newHDWallet(phrase, password string) (*native.Wallet, error) {
+	wallet, err := native.NewFromMnemonic(strings.Join(strings.Fields(phrase), " "), password)
+	if err != nil {
+		return nil, err
+	}
+	wallet.SetFixIssue172(true)
+	return wallet, nil
+}
+
+// DeriveKeyFromMnemonic derives the private key at the given BIP-44 path from
+// a BIP-39 mnemonic and optional password, e.g. DefaultBaseDerivationPath for the
+// first account.
+func DeriveKeyFromMnemonic(phrase, password, path string) (_ *PrivateKey, err error) {
+	defer recoverError(&err)
+	parsed, err := parseBIP44Path(path)
+	if err != nil {
+		return nil, err
+	}
+	wallet, err := newHDWallet(phrase, password)
+	if err != nil {
+		return nil, err
+	}
+	account, err := wallet.Derive(parsed, false)
+	if err != nil {
+		return nil, err
+	}
+	key, err := wallet.PrivateKey(account)
+	if err != nil {
+		return nil, err
+	}
+	return &PrivateKey{key}, nil
+}
+
+// DeriveAddresses derives count consecutive account addresses from a BIP-39
+// mnemonic without password, starting at address index start of the
+// DefaultBaseDerivationPath, e.g. to present an account picker.
+func DeriveAddresses(phrase string, start, count int) (_ *Addresses, err error) {
+	defer recoverError(&err)
+	if start < 0 || count < 0 || int64(start)+int64(count) > 1<<31 {
+		return nil, fmt.Errorf("invalid address index range [%d, %d)", start, int64(start)+int64(count))
+	}
+	wallet, err := newHDWallet(phrase, "")
+	if err != nil {
+		return nil, err
+	}
+	base, _ := parseBIP44Path(DefaultBaseDerivationPath)
+	addresses := make([]common.Address, count)
+	for i := range addresses {
+		path := append(accounts.DerivationPath{}, base...)
+		path[len(path)-1] = uint32(start + i)
+		account, err := wallet.Derive(path, false)
+		if err != nil {
+			return nil, err
+		}
+		addresses[i] = account.Address
+	}
+	return &Addresses{addresses}, nil
+}
@@ -1,6 +1,8 @@
 package web3go
 
 import (
+	"encoding/hex"
+	"strings"
 	"testing"
 )
 
@@ -53,3 +55,87 @@ func TestWallet(t *testing.T) {
 		t.Error("wrong address")
 	}
 }
+
+// The well known development mnemonic of Hardhat and Anvil, whose accounts
+// MetaMask and Ledger derive identically.
+const testMnemonic = "test test test test test test test test test test test junk"
+
+func TestDeriveFromMnemonic(t *testing.T) {
+	want := []string{
+		"0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266",
+		"0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+		"0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC",
+		"0x90F79bf6EB2c4f870365E785982E1f101E93b906",
+		"0x15d34AAf54267DB7D7c367839AAf71A00a2C6A65",
+	}
+	addresses, err := DeriveAddresses(testMnemonic, 0, len(want))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addresses.Size() != len(want) {
+		t.Fatalf("address count mismatch: have %d, want %d", addresses.Size(), len(want))
+	}
+	for i := range want {
+		address, _ := addresses.Get(i)
+		if address.GetHex() != want[i] {
+			t.Errorf("address %d mismatch: have %s, want %s", i, address.GetHex(), want[i])
+		}
+	}
+	if tail, _ := DeriveAddresses(testMnemonic, 3, 2); tail.Size() != 2 {
+		t.Error("offset derivation size mismatch")
+	} else if address, _ := tail.Get(1); address.GetHex() != want[4] {
+		t.Errorf("offset derivation mismatch: have %s, want %s", address.GetHex(), want[4])
+	}
+	key, err := DeriveKeyFromMnemonic(testMnemonic, "", "m/44'/60'/0'/0/0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have := hex.EncodeToString(FromECDSA(key)); have != "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80" {
+		t.Errorf("private key mismatch: have %s", have)
+	}
+	if key, _ := DeriveKeyFromMnemonic(testMnemonic, "secret", DefaultBaseDerivationPath); PubkeyToAddress(key.Public()).GetHex() == want[0] {
+		t.Error("password did not change the derived key")
+	}
+	if _, err := DeriveAddresses("test test test", 0, 1); err == nil {
+		t.Error("expected error for invalid mnemonic")
+	}
+	if _, err := DeriveAddresses(testMnemonic, -1, 1); err == nil {
+		t.Error("expected error for negative start")
+	}
+}
+
+func TestParseDerivationPath(t *testing.T) {
+	path, err := ParseDerivationPath("m/44'/60'/0'/0/7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path.String() != "m/44'/60'/0'/0/7" || path.Size() != 5 {
+		t.Errorf("path mismatch: have %s (%d levels)", path, path.Size())
+	}
+	if coin, _ := path.Get(1); coin != 0x80000000+60 {
+		t.Errorf("coin type mismatch: have %#x", coin)
+	}
+	if _, err := ParseDerivationPath("m/44'/60'/0'/0"); err != nil {
+		t.Errorf("legacy Ledger path rejected: %v", err)
+	}
+	for path, want := range map[string]string{
+		"44'/60'/0'/0/0":            "must start with m/",
+		"m/44'/60'":                 "must have 3 to 5 levels",
+		"m/44'/60'/0'/0/0/0":        "must have 3 to 5 levels",
+		"m/44/60'/0'/0/0":           "purpose must be hardened",
+		"m/44'/60/0'/0/0":           "coin type must be hardened",
+		"m/44'/60'/0/0/0":           "account must be hardened",
+		"m/44'/60'/0'/0'/0":         "change must not be hardened",
+		"m/49'/60'/0'/0/0":          "purpose must be 44'",
+		"m/44'/60'/0'/0/2147483648": "invalid address index",
+		"m/44'/60'/x'/0/0":          "invalid account",
+		"m/44'/60'/-1'/0/0":         "invalid account",
+	} {
+		if _, err := ParseDerivationPath(path); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: error mismatch: have %v, want %q", path, err, want)
+		}
+	}
+	if _, err := DeriveKeyFromMnemonic(testMnemonic, "", "m/44/60/0/0/0"); err == nil {
+		t.Error("expected error for unhardened path")
+	}
+}
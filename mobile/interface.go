@@ -32,6 +32,9 @@ import (
 // platforms, we're using explicit getters and setters for the conversions. There
 // is of course no point in enumerating everything, just enough to support the
 // contract bindins requiring client side generated code.
+//
+// The getters return the zero value if the interface holds a different type
+// than the one requested.
 type Interface struct {
 	object interface{}
 }
@@ -161,70 +164,116 @@ func (i *Interface) SetDefaultBigInt() { i.object = new(*big.Int) }
 func (i *Interface) SetDefaultBigInts() { i.object = new([]*big.Int) }
 
 // GetBool ...
-func (i *Interface) GetBool() bool { return *i.object.(*bool) }
+func (i *Interface) GetBool() bool {
+	defer recoverZero()
+	return *i.object.(*bool)
+}
 
 //func (i *Interface) GetBools() []bool         { return *i.object.(*[]bool) }
 
 // GetString ...
-func (i *Interface) GetString() string { return *i.object.(*string) }
+func (i *Interface) GetString() string {
+	defer recoverZero()
+	return *i.object.(*string)
+}
 
 // GetStrings ...
-func (i *Interface) GetStrings() *Strings { return &Strings{*i.object.(*[]string)} }
+func (i *Interface) GetStrings() *Strings {
+	defer recoverZero()
+	return &Strings{*i.object.(*[]string)}
+}
 
 // GetBinary ...
-func (i *Interface) GetBinary() []byte { return *i.object.(*[]byte) }
+func (i *Interface) GetBinary() []byte {
+	defer recoverZero()
+	return *i.object.(*[]byte)
+}
 
 //func (i *Interface) GetBinaries() [][]byte    { return *i.object.(*[][]byte) }
 
 // GetAddress ...
-func (i *Interface) GetAddress() *Address { return &Address{*i.object.(*common.Address)} }
+func (i *Interface) GetAddress() *Address {
+	defer recoverZero()
+	return &Address{*i.object.(*common.Address)}
+}
 
 // GetAddresses ...
-func (i *Interface) GetAddresses() *Addresses { return &Addresses{*i.object.(*[]common.Address)} }
+func (i *Interface) GetAddresses() *Addresses {
+	defer recoverZero()
+	return &Addresses{*i.object.(*[]common.Address)}
+}
 
 // GetHash ...
-func (i *Interface) GetHash() *Hash { return &Hash{*i.object.(*common.Hash)} }
+func (i *Interface) GetHash() *Hash {
+	defer recoverZero()
+	return &Hash{*i.object.(*common.Hash)}
+}
 
 // GetHashes ...
-func (i *Interface) GetHashes() *Hashes { return &Hashes{*i.object.(*[]common.Hash)} }
+func (i *Interface) GetHashes() *Hashes {
+	defer recoverZero()
+	return &Hashes{*i.object.(*[]common.Hash)}
+}
 
 // GetInt8 ...
-func (i *Interface) GetInt8() int8 { return *i.object.(*int8) }
+func (i *Interface) GetInt8() int8 {
+	defer recoverZero()
+	return *i.object.(*int8)
+}
 
 // GetInt16 ...
-func (i *Interface) GetInt16() int16 { return *i.object.(*int16) }
+func (i *Interface) GetInt16() int16 {
+	defer recoverZero()
+	return *i.object.(*int16)
+}
 
 // GetInt32 ...
-func (i *Interface) GetInt32() int32 { return *i.object.(*int32) }
+func (i *Interface) GetInt32() int32 {
+	defer recoverZero()
+	return *i.object.(*int32)
+}
 
 // GetInt64 ...
-func (i *Interface) GetInt64() int64 { return *i.object.(*int64) }
+func (i *Interface) GetInt64() int64 {
+	defer recoverZero()
+	return *i.object.(*int64)
+}
 
 // GetUint8 ...
 func (i *Interface) GetUint8() *BigInt {
+	defer recoverZero()
 	return &BigInt{new(big.Int).SetUint64(uint64(*i.object.(*uint8)))}
 }
 
 // GetUint16 ...
 func (i *Interface) GetUint16() *BigInt {
+	defer recoverZero()
 	return &BigInt{new(big.Int).SetUint64(uint64(*i.object.(*uint16)))}
 }
 
 // GetUint32 ...
 func (i *Interface) GetUint32() *BigInt {
+	defer recoverZero()
 	return &BigInt{new(big.Int).SetUint64(uint64(*i.object.(*uint32)))}
 }
 
 // GetUint64 ...
 func (i *Interface) GetUint64() *BigInt {
+	defer recoverZero()
 	return &BigInt{new(big.Int).SetUint64(*i.object.(*uint64))}
 }
 
 // GetBigInt ...
-func (i *Interface) GetBigInt() *BigInt { return &BigInt{*i.object.(**big.Int)} }
+func (i *Interface) GetBigInt() *BigInt {
+	defer recoverZero()
+	return &BigInt{*i.object.(**big.Int)}
+}
 
 // GetBigInts ...
-func (i *Interface) GetBigInts() *BigInts { return &BigInts{*i.object.(*[]*big.Int)} }
+func (i *Interface) GetBigInts() *BigInts {
+	defer recoverZero()
+	return &BigInts{*i.object.(*[]*big.Int)}
+}
 
 // Interfaces is a slices of wrapped generic objects.
 type Interfaces struct {
@@ -0,0 +1,409 @@
+// Copyright 2019 The bcl-chain Authors. All rights reserved.
+
+// Contains a resumable, reorg-aware log processor built on top of the client.
+
+package web3go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// defaultLogCursorChunk is the maximum number of blocks filtered in one batch.
+	defaultLogCursorChunk = 1000
+
+	// logCursorHistory is the number of acknowledged checkpoints retained in
+	// memory to find the common ancestor after a reorg.
+	logCursorHistory = 128
+
+	// logCursorMaxReorg is the maximum number of blocks walked back along the
+	// parent hashes of the abandoned chain to find the common ancestor when none
+	// of the retained checkpoints is canonical any more (e.g. after a restart).
+	logCursorMaxReorg = 1024
+
+	// logCursorPollInterval is the delay between head checks when no new head
+	// subscription is available.
+	logCursorPollInterval = 15 * time.Second
+)
+
+// LogCheckpoint is a position of a LogCursor: the last block whose logs were
+// acknowledged by the handler.
+type LogCheckpoint struct {
+	number int64
+	hash   common.Hash
+}
+
+// NewLogCheckpoint creates a checkpoint at the given block.
+func NewLogCheckpoint(number int64, hash *Hash) *LogCheckpoint {
+	return &LogCheckpoint{number: number, hash: hash.hash}
+}
+
+// GetNumber ...
+func (c *LogCheckpoint) GetNumber() int64 { return c.number }
+
+// GetHash ...
+func (c *LogCheckpoint) GetHash() *Hash { return &Hash{c.hash} }
+
+// LogCheckpointStore persists the position of a LogCursor across restarts. Load
+// returns nil if no checkpoint was saved yet.
+type LogCheckpointStore interface {
+	Save(blockNumber int64, blockHash *Hash) error
+	Load() (*LogCheckpoint, error)
+}
+
+// LogCursorHandler is a client-side callback receiving the logs of a LogCursor.
+//
+// OnLogs is called with the logs of consecutive block ranges ending at atBlock.
+// Returning nil acknowledges the batch and advances the checkpoint; returning an
+// error redelivers the same range later. OnRollback reports that the logs of all
+// blocks after toBlock were reorged out and will be delivered again from the new
+// chain.
+//
+// Delivery is at-least-once: if the app stops after a batch was handled but
+// before its checkpoint was saved, the batch is delivered again on restart.
+type LogCursorHandler interface {
+	OnLogs(batch *Logs, atBlock int64) error
+	OnRollback(toBlock int64)
+	OnError(failure string)
+}
+
+// logCursorBackend is the part of the client API used by a LogCursor.
+type logCursorBackend interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error)
+	FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error)
+	SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error)
+}
+
+// LogCursor processes the logs matching a filter query, catching up on past
+// blocks in chunks, then tailing the chain head. Its position is stored in a
+// LogCheckpointStore only after the handler acknowledged a batch, and it rolls
+// back when a reorg invalidates already processed blocks.
+//
+// The FromBlock of the query is used when no checkpoint exists yet, defaulting to
+// the current head. If ToBlock is set, the cursor stops advancing past it.
+type LogCursor struct {
+	backend logCursorBackend
+	query   ethereum.FilterQuery
+	store   LogCheckpointStore
+	handler LogCursorHandler
+	chunk   int64
+
+	last    *LogCheckpoint  // Last acknowledged position, nil if none yet
+	next    int64           // Next block to process
+	history []LogCheckpoint // Recently acknowledged positions, oldest first
+
+	lock     sync.Mutex
+	cancel   context.CancelFunc
+	done     chan struct{}
+	handling bool // Whether a handler callback is running on the loop
+}
+
+// NewLogCursor creates a log cursor for the given filter query.
+func NewLogCursor(client *EthereumClient, query *FilterQuery, store LogCheckpointStore, handler LogCursorHandler) *LogCursor {
+	return newLogCursor(client.client, query.query, store, handler)
+}
+
+func newLogCursor(backend logCursorBackend, query ethereum.FilterQuery, store LogCheckpointStore, handler LogCursorHandler) *LogCursor {
+	return &LogCursor{
+		backend: backend,
+		query:   query,
+		store:   store,
+		handler: handler,
+		chunk:   defaultLogCursorChunk,
+	}
+}
+
+// SetChunkSize sets the maximum number of blocks filtered in one batch.
+func (c *LogCursor) SetChunkSize(blocks int64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if blocks > 0 {
+		c.chunk = blocks
+	}
+}
+
+// Start loads the stored checkpoint and starts processing logs in the background.
+func (c *LogCursor) Start() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.cancel != nil {
+		return errors.New("log cursor already running")
+	}
+	if c.done != nil {
+		select {
+		case <-c.done:
+		default:
+			return errors.New("log cursor still stopping")
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := c.init(ctx); err != nil {
+		cancel()
+		return err
+	}
+	heads := make(chan *types.Header, 1)
+	sub, err := c.backend.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		log.Debug("Log cursor falling back to polling", "err", err)
+		sub = nil
+	}
+	c.cancel, c.done = cancel, make(chan struct{})
+	go c.loop(ctx, heads, sub)
+	return nil
+}
+
+// Stop terminates log processing and waits for the running batch to finish.
+// Called while a handler callback runs, e.g. from OnLogs, it returns right away
+// and processing ends once the callback returned.
+func (c *LogCursor) Stop() {
+	c.lock.Lock()
+	if c.cancel == nil {
+		c.lock.Unlock()
+		return
+	}
+	c.cancel()
+	done, wait := c.done, !c.handling
+	c.cancel = nil
+	c.lock.Unlock()
+
+	if wait {
+		<-done
+	}
+}
+
+// handle runs a handler callback, marking it as running for Stop.
+func (c *LogCursor) handle(callback func()) {
+	c.lock.Lock()
+	c.handling = true
+	c.lock.Unlock()
+
+	defer func() {
+		c.lock.Lock()
+		c.handling = false
+		c.lock.Unlock()
+	}()
+	callback()
+}
+
+// init positions the cursor at the stored checkpoint, or at the start of the
+// query if there is none.
+func (c *LogCursor) init(ctx context.Context) error {
+	checkpoint, err := c.store.Load()
+	if err != nil {
+		return err
+	}
+	c.history = c.history[:0]
+	if checkpoint != nil {
+		c.last, c.next = checkpoint, checkpoint.number+1
+		c.history = append(c.history, *checkpoint)
+		return nil
+	}
+	c.last = nil
+	if c.query.FromBlock != nil {
+		c.next = c.query.FromBlock.Int64()
+		return nil
+	}
+	head, err := c.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return err
+	}
+	c.next = head.Number.Int64()
+	return nil
+}
+
+// loop processes batches until caught up every time a new head arrives, or the
+// poll interval elapses.
+func (c *LogCursor) loop(ctx context.Context, heads chan *types.Header, sub ethereum.Subscription) {
+	defer close(c.done)
+
+	var subErr <-chan error
+	if sub != nil {
+		defer sub.Unsubscribe()
+		subErr = sub.Err()
+	}
+	for {
+		for {
+			caughtUp, err := c.step(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				c.handle(func() { c.handler.OnError(err.Error()) })
+				break
+			}
+			if caughtUp {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-heads:
+		case err := <-subErr:
+			log.Debug("Log cursor head subscription failed", "err", err)
+			subErr = nil
+		case <-time.After(logCursorPollInterval):
+		}
+	}
+}
+
+// step checks the last acknowledged block for a reorg and then delivers the next
+// batch of logs. It reports whether the cursor caught up with the chain.
+func (c *LogCursor) step(ctx context.Context) (bool, error) {
+	head, err := c.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	if c.last != nil {
+		header, err := c.backend.HeaderByNumber(ctx, big.NewInt(c.last.number))
+		if err != nil && err != ethereum.NotFound {
+			return false, err
+		}
+		if header == nil || header.Hash() != c.last.hash {
+			return false, c.rollback(ctx)
+		}
+	}
+	to := head.Number.Int64()
+	if c.query.ToBlock != nil && c.query.ToBlock.Int64() < to {
+		to = c.query.ToBlock.Int64()
+	}
+	if c.next > to {
+		return true, nil
+	}
+	c.lock.Lock()
+	chunk := c.chunk
+	c.lock.Unlock()
+
+	if to-c.next+1 > chunk {
+		to = c.next + chunk - 1
+	}
+	end, err := c.backend.HeaderByNumber(ctx, big.NewInt(to))
+	if err != nil {
+		return false, err
+	}
+	query := c.query
+	query.FromBlock, query.ToBlock = big.NewInt(c.next), big.NewInt(to)
+	logs, err := c.backend.FilterLogs(ctx, query)
+	if err != nil {
+		return false, err
+	}
+	// Make sure the logs were served from the same chain the batch ends on,
+	// otherwise retry the range once the node settled on a head
+	recheck, err := c.backend.HeaderByNumber(ctx, big.NewInt(to))
+	if err != nil {
+		return false, err
+	}
+	if recheck.Hash() != end.Hash() {
+		return false, nil
+	}
+	batch := make([]*types.Log, len(logs))
+	for i := range logs {
+		if logs[i].BlockNumber == uint64(to) && logs[i].BlockHash != end.Hash() {
+			return false, nil
+		}
+		batch[i] = &logs[i]
+	}
+	c.handle(func() { err = c.handler.OnLogs(&Logs{batch}, to) })
+	if err != nil {
+		return false, err
+	}
+	if err := c.store.Save(to, &Hash{end.Hash()}); err != nil {
+		return false, err
+	}
+	c.advance(LogCheckpoint{number: to, hash: end.Hash()})
+	return false, nil
+}
+
+// advance records an acknowledged checkpoint.
+func (c *LogCursor) advance(checkpoint LogCheckpoint) {
+	c.last, c.next = &checkpoint, checkpoint.number+1
+
+	c.history = append(c.history, checkpoint)
+	if len(c.history) > logCursorHistory {
+		c.history = c.history[len(c.history)-logCursorHistory:]
+	}
+}
+
+// rollback rewinds the cursor to the newest retained checkpoint still on the
+// canonical chain, notifying the handler before persisting the new position. If
+// none is, the common ancestor is searched along the parent hashes of the last
+// acknowledged block.
+func (c *LogCursor) rollback(ctx context.Context) error {
+	var ancestor *LogCheckpoint
+	for i := len(c.history) - 1; i >= 0; i-- {
+		header, err := c.backend.HeaderByNumber(ctx, big.NewInt(c.history[i].number))
+		if err == ethereum.NotFound {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if header.Hash() == c.history[i].hash {
+			checkpoint := c.history[i]
+			ancestor, c.history = &checkpoint, c.history[:i]
+			break
+		}
+	}
+	if ancestor == nil {
+		checkpoint, err := c.commonAncestor(ctx)
+		if err != nil {
+			return err
+		}
+		ancestor, c.history = checkpoint, c.history[:0]
+	}
+	log.Debug("Log cursor rolling back", "from", c.last.number, "to", ancestor.number)
+
+	c.handle(func() { c.handler.OnRollback(ancestor.number) })
+	if err := c.store.Save(ancestor.number, &Hash{ancestor.hash}); err != nil {
+		return err
+	}
+	c.advance(*ancestor)
+	return nil
+}
+
+// commonAncestor walks back from the last acknowledged block along the parent
+// hashes of the abandoned chain until it reaches a canonical block. It fails if
+// the abandoned blocks are unavailable or the reorg is deeper than
+// logCursorMaxReorg blocks, as logs of blocks past the ancestor would be missed
+// otherwise.
+func (c *LogCursor) commonAncestor(ctx context.Context) (*LogCheckpoint, error) {
+	checkpoint := *c.last
+	for depth := 0; depth <= logCursorMaxReorg; depth++ {
+		header, err := c.backend.HeaderByNumber(ctx, big.NewInt(checkpoint.number))
+		if err != nil && err != ethereum.NotFound {
+			return nil, err
+		}
+		if header != nil && header.Hash() == checkpoint.hash {
+			return &checkpoint, nil
+		}
+		// Blocks before the start of the query were never delivered
+		if header != nil && c.query.FromBlock != nil && checkpoint.number < c.query.FromBlock.Int64() {
+			return &LogCheckpoint{number: checkpoint.number, hash: header.Hash()}, nil
+		}
+		if checkpoint.number == 0 {
+			return nil, errors.New("log cursor: genesis block reorged")
+		}
+		abandoned, err := c.backend.HeaderByHash(ctx, checkpoint.hash)
+		if err == ethereum.NotFound {
+			return nil, fmt.Errorf("log cursor: reorged block %d (%x) unavailable to find the common ancestor", checkpoint.number, checkpoint.hash)
+		}
+		if err != nil {
+			return nil, err
+		}
+		checkpoint = LogCheckpoint{number: checkpoint.number - 1, hash: abandoned.ParentHash}
+	}
+	return nil, fmt.Errorf("log cursor: reorg below block %d deeper than %d blocks", c.last.number, logCursorMaxReorg)
+}
@@ -0,0 +1,292 @@
+package web3go
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// testChain is a fake backend serving a chain with one log per block.
+type testChain struct {
+	headers []*types.Header
+	known   map[common.Hash]*types.Header // All headers ever served, reorged ones too
+	forks   byte                          // Number of reorgs, salting each fork
+}
+
+func newTestChain(length int) *testChain {
+	chain := &testChain{known: make(map[common.Hash]*types.Header)}
+	chain.extend(length, 0)
+	return chain
+}
+
+// extend appends blocks to the chain, salting the headers to create forks.
+func (c *testChain) extend(blocks int, salt byte) {
+	for i := 0; i < blocks; i++ {
+		header := &types.Header{
+			Number: big.NewInt(int64(len(c.headers))),
+			Extra:  []byte{salt},
+		}
+		if len(c.headers) > 0 {
+			header.ParentHash = c.headers[len(c.headers)-1].Hash()
+		}
+		c.headers = append(c.headers, header)
+		c.known[header.Hash()] = header
+	}
+}
+
+// reorg replaces all blocks from number onwards with a longer fork.
+func (c *testChain) reorg(number int, blocks int) {
+	c.headers = c.headers[:number]
+	c.forks++
+	c.extend(blocks, c.forks)
+}
+
+func (c *testChain) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	if number == nil {
+		return c.headers[len(c.headers)-1], nil
+	}
+	if number.Int64() >= int64(len(c.headers)) {
+		return nil, ethereum.NotFound
+	}
+	return c.headers[number.Int64()], nil
+}
+
+func (c *testChain) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	if header, ok := c.known[hash]; ok {
+		return header, nil
+	}
+	return nil, ethereum.NotFound
+}
+
+func (c *testChain) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	var logs []types.Log
+	for n := query.FromBlock.Int64(); n <= query.ToBlock.Int64() && n < int64(len(c.headers)); n++ {
+		logs = append(logs, types.Log{
+			BlockNumber: uint64(n),
+			BlockHash:   c.headers[n].Hash(),
+		})
+	}
+	return logs, nil
+}
+
+func (c *testChain) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	return nil, errors.New("not supported")
+}
+
+type testCheckpointStore struct {
+	checkpoint *LogCheckpoint
+}
+
+func (s *testCheckpointStore) Save(blockNumber int64, blockHash *Hash) error {
+	s.checkpoint = NewLogCheckpoint(blockNumber, blockHash)
+	return nil
+}
+
+func (s *testCheckpointStore) Load() (*LogCheckpoint, error) {
+	return s.checkpoint, nil
+}
+
+type testLogCursorHandler struct {
+	delivered map[common.Hash]int // Number of deliveries per block hash
+	rollbacks []int64
+	failAt    int64 // Batch end to reject, simulating a crash before the ack
+}
+
+func newTestLogCursorHandler() *testLogCursorHandler {
+	return &testLogCursorHandler{delivered: make(map[common.Hash]int), failAt: -1}
+}
+
+func (h *testLogCursorHandler) OnLogs(batch *Logs, atBlock int64) error {
+	for _, log := range batch.logs {
+		h.delivered[log.BlockHash]++
+	}
+	if atBlock == h.failAt {
+		return errors.New("crashed")
+	}
+	return nil
+}
+
+func (h *testLogCursorHandler) OnRollback(toBlock int64) { h.rollbacks = append(h.rollbacks, toBlock) }
+func (h *testLogCursorHandler) OnError(failure string)   {}
+
+// drain steps the cursor until it caught up with the chain.
+func drain(t *testing.T, cursor *LogCursor) error {
+	for i := 0; i < 100; i++ {
+		caughtUp, err := cursor.step(context.Background())
+		if err != nil || caughtUp {
+			return err
+		}
+	}
+	t.Fatal("log cursor did not catch up")
+	return nil
+}
+
+func TestLogCursorRestart(t *testing.T) {
+	chain := newTestChain(50)
+	store := new(testCheckpointStore)
+	handler := newTestLogCursorHandler()
+	handler.failAt = 29
+
+	query := ethereum.FilterQuery{FromBlock: big.NewInt(0)}
+	cursor := newLogCursor(chain, query, store, handler)
+	cursor.SetChunkSize(10)
+	if err := cursor.init(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := drain(t, cursor); err == nil {
+		t.Fatal("expected the handler failure to surface")
+	}
+	if store.checkpoint.GetNumber() != 19 {
+		t.Fatalf("checkpoint mismatch: have %d, want 19", store.checkpoint.GetNumber())
+	}
+	// Restart with a fresh cursor on the same store
+	handler.failAt = -1
+	cursor = newLogCursor(chain, query, store, handler)
+	cursor.SetChunkSize(10)
+	if err := cursor.init(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := drain(t, cursor); err != nil {
+		t.Fatal(err)
+	}
+	for i, header := range chain.headers {
+		want := 1
+		if i >= 20 && i < 30 {
+			want = 2 // unacknowledged batch is redelivered
+		}
+		if have := handler.delivered[header.Hash()]; have != want {
+			t.Errorf("block %d: delivery count mismatch: have %d, want %d", i, have, want)
+		}
+	}
+}
+
+func TestLogCursorReorg(t *testing.T) {
+	chain := newTestChain(50)
+	store := new(testCheckpointStore)
+	handler := newTestLogCursorHandler()
+
+	cursor := newLogCursor(chain, ethereum.FilterQuery{FromBlock: big.NewInt(0)}, store, handler)
+	cursor.SetChunkSize(10)
+	if err := cursor.init(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := drain(t, cursor); err != nil {
+		t.Fatal(err)
+	}
+	// Replace the last few blocks and make sure the cursor rolls back
+	chain.reorg(45, 8)
+	if err := drain(t, cursor); err != nil {
+		t.Fatal(err)
+	}
+	if len(handler.rollbacks) != 1 || handler.rollbacks[0] != 39 {
+		t.Fatalf("rollback mismatch: have %v, want [39]", handler.rollbacks)
+	}
+	for i, header := range chain.headers {
+		if handler.delivered[header.Hash()] == 0 {
+			t.Errorf("block %d: logs never delivered", i)
+		}
+	}
+	if store.checkpoint.GetNumber() != 52 || store.checkpoint.hash != chain.headers[52].Hash() {
+		t.Errorf("checkpoint mismatch: have %d, want 52", store.checkpoint.GetNumber())
+	}
+}
+
+func TestLogCursorDeepReorg(t *testing.T) {
+	chain := newTestChain(200)
+	store := new(testCheckpointStore)
+	handler := newTestLogCursorHandler()
+	query := ethereum.FilterQuery{FromBlock: big.NewInt(0)}
+
+	cursor := newLogCursor(chain, query, store, handler)
+	cursor.SetChunkSize(50)
+	if err := cursor.init(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := drain(t, cursor); err != nil {
+		t.Fatal(err)
+	}
+	// Restart, retaining only the stored checkpoint, and reorg 100 blocks deep
+	cursor = newLogCursor(chain, query, store, handler)
+	if err := cursor.init(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	chain.reorg(100, 120)
+	if err := drain(t, cursor); err != nil {
+		t.Fatal(err)
+	}
+	if len(handler.rollbacks) != 1 || handler.rollbacks[0] != 99 {
+		t.Fatalf("rollback mismatch: have %v, want [99]", handler.rollbacks)
+	}
+	for i, header := range chain.headers {
+		if handler.delivered[header.Hash()] != 1 {
+			t.Errorf("block %d: delivery count mismatch: have %d, want 1", i, handler.delivered[header.Hash()])
+		}
+	}
+	// Reorged blocks the node no longer knows must fail instead of skipping logs
+	for hash, header := range chain.known {
+		if header.Number.Int64() >= 100 {
+			delete(chain.known, hash)
+		}
+	}
+	chain.reorg(150, 80)
+	cursor = newLogCursor(chain, query, store, handler)
+	if err := cursor.init(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := drain(t, cursor); err == nil {
+		t.Fatal("expected error for unavailable reorged blocks")
+	}
+	if store.checkpoint.GetNumber() != 219 {
+		t.Errorf("checkpoint moved: have %d, want 219", store.checkpoint.GetNumber())
+	}
+}
+
+// testStoppingHandler stops its cursor from within OnLogs.
+type testStoppingHandler struct {
+	cursor  *LogCursor
+	stopped chan struct{}
+}
+
+func (h *testStoppingHandler) OnLogs(batch *Logs, atBlock int64) error {
+	h.cursor.SetChunkSize(5)
+	h.cursor.Stop()
+	select {
+	case <-h.stopped:
+	default:
+		close(h.stopped)
+	}
+	return nil
+}
+
+func (h *testStoppingHandler) OnRollback(toBlock int64) {}
+func (h *testStoppingHandler) OnError(failure string)   {}
+
+func TestLogCursorStopFromHandler(t *testing.T) {
+	handler := &testStoppingHandler{stopped: make(chan struct{})}
+	handler.cursor = newLogCursor(newTestChain(50), ethereum.FilterQuery{FromBlock: big.NewInt(0)}, new(testCheckpointStore), handler)
+	if err := handler.cursor.Start(); err != nil {
+		t.Fatal(err)
+	}
+	handler.cursor.SetChunkSize(20)
+	select {
+	case <-handler.stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop deadlocked in handler")
+	}
+	handler.cursor.Stop()
+	select {
+	case <-handler.cursor.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("log cursor still running")
+	}
+	if err := handler.cursor.Start(); err != nil {
+		t.Errorf("restart failed: %v", err)
+	}
+	handler.cursor.Stop()
+}
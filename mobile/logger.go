@@ -14,15 +14,153 @@
 // You should have received a copy of the GNU Lesser General Public License
 // along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
 
+// Contains the wrappers for routing the go-ethereum logger to mobile platforms.
+
 package web3go
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
+	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/log"
 )
 
+// Log levels accepted by SetLogLevel and reported to a LogHandler. Higher values
+// are more verbose; a level enables itself and every level below it.
+const (
+	LogLevelCrit  = int(log.LvlCrit)
+	LogLevelError = int(log.LvlError)
+	LogLevelWarn  = int(log.LvlWarn)
+	LogLevelInfo  = int(log.LvlInfo)
+	LogLevelDebug = int(log.LvlDebug)
+	LogLevelTrace = int(log.LvlTrace)
+)
+
+// logRateLimit is the maximum number of records forwarded to a LogHandler per
+// second. Anything above is dropped and summarised once the next window opens,
+// so a log storm inside the node cannot flood the mobile bridge.
+const logRateLimit = 200
+
+// LogHandler is a client-side callback receiving every log record emitted by the
+// library and the embedded node. The context is a JSON object of the key/value
+// pairs attached to the record.
+type LogHandler interface {
+	OnLog(level int, timestampMillis int64, message string, contextJSON string)
+}
+
+var (
+	logLock    sync.Mutex
+	logLevel   = log.LvlInfo
+	logHandler LogHandler
+)
+
 // SetVerbosity sets the global verbosity level (between 0 and 6 - see logger/verbosity.go).
 func SetVerbosity(level int) {
-	log.Root().SetHandler(log.LvlFilterHandler(log.Lvl(level), log.StreamHandler(os.Stderr, log.TerminalFormat(false))))
+	SetLogLevel(level)
+}
+
+// SetLogLevel sets the maximum level of the records to emit, see the LogLevel
+// constants. Values above LogLevelTrace are treated as trace.
+func SetLogLevel(level int) {
+	logLock.Lock()
+	defer logLock.Unlock()
+
+	logLevel = log.Lvl(level)
+	installLogHandler()
+}
+
+// SetLogHandler routes all log records through the given handler instead of
+// stderr. Passing nil restores the default stderr output.
+func SetLogHandler(handler LogHandler) {
+	logLock.Lock()
+	defer logLock.Unlock()
+
+	logHandler = handler
+	installLogHandler()
+}
+
+// installLogHandler replaces the root handler of the go-ethereum logger based on
+// the current level and handler. The caller must hold logLock.
+func installLogHandler() {
+	if logHandler == nil {
+		log.Root().SetHandler(log.LvlFilterHandler(logLevel, log.StreamHandler(os.Stderr, log.TerminalFormat(false))))
+		return
+	}
+	log.Root().SetHandler(log.LvlFilterHandler(logLevel, newMobileLogHandler(logHandler, logRateLimit)))
+}
+
+// mobileLogHandler converts go-ethereum log records into LogHandler callbacks,
+// dropping records above the configured rate.
+type mobileLogHandler struct {
+	handler LogHandler
+	limit   int
+
+	lock    sync.Mutex
+	window  time.Time // Start of the current one second rate window
+	count   int       // Number of records forwarded in the current window
+	dropped int       // Number of records dropped in the current window
+}
+
+func newMobileLogHandler(handler LogHandler, limit int) *mobileLogHandler {
+	return &mobileLogHandler{
+		handler: handler,
+		limit:   limit,
+	}
+}
+
+// Log implements log.Handler.
+func (h *mobileLogHandler) Log(r *log.Record) error {
+	h.lock.Lock()
+	if r.Time.Sub(h.window) >= time.Second || r.Time.Before(h.window) {
+		if h.dropped > 0 {
+			h.handler.OnLog(LogLevelWarn, r.Time.UnixNano()/int64(time.Millisecond), "Dropped log messages", fmt.Sprintf(`{"count":%d}`, h.dropped))
+		}
+		h.window, h.count, h.dropped = r.Time, 0, 0
+	}
+	if h.count >= h.limit {
+		h.dropped++
+		h.lock.Unlock()
+		return nil
+	}
+	h.count++
+	h.lock.Unlock()
+
+	h.handler.OnLog(int(r.Lvl), r.Time.UnixNano()/int64(time.Millisecond), r.Msg, encodeLogContext(r.Ctx))
+	return nil
+}
+
+// encodeLogContext flattens the key/value pairs of a log record into a JSON
+// object. Values that cannot be marshalled are rendered with fmt.
+func encodeLogContext(ctx []interface{}) string {
+	if len(ctx) == 0 {
+		return "{}"
+	}
+	fields := make(map[string]interface{}, len(ctx)/2)
+	for i := 0; i < len(ctx); i += 2 {
+		key := fmt.Sprint(ctx[i])
+		if i+1 >= len(ctx) {
+			fields[key] = nil
+			break
+		}
+		switch value := ctx[i+1].(type) {
+		case error:
+			fields[key] = value.Error()
+		case fmt.Stringer:
+			fields[key] = value.String()
+		default:
+			if _, err := json.Marshal(value); err != nil {
+				fields[key] = fmt.Sprintf("%+v", value)
+			} else {
+				fields[key] = value
+			}
+		}
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return "{}"
+	}
+	return string(data)
 }
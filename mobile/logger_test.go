@@ -0,0 +1,75 @@
+package web3go
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+type logRecord struct {
+	level   int
+	message string
+	context string
+}
+
+type recordingLogHandler struct {
+	lock    sync.Mutex
+	records []logRecord
+}
+
+func (h *recordingLogHandler) OnLog(level int, timestampMillis int64, message string, contextJSON string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.records = append(h.records, logRecord{level, message, contextJSON})
+}
+
+func TestLogHandlerLevelFiltering(t *testing.T) {
+	handler := new(recordingLogHandler)
+	SetLogHandler(handler)
+	SetLogLevel(LogLevelWarn)
+	defer func() {
+		SetLogHandler(nil)
+		SetLogLevel(LogLevelInfo)
+	}()
+
+	log.Debug("debug message")
+	log.Info("info message")
+	log.Warn("warn message", "block", 42)
+	log.Error("error message", "reason", "boom")
+
+	if len(handler.records) != 2 {
+		t.Fatalf("record count mismatch: have %d, want 2", len(handler.records))
+	}
+	if rec := handler.records[0]; rec.level != LogLevelWarn || rec.message != "warn message" {
+		t.Errorf("unexpected first record: %+v", rec)
+	}
+	if rec := handler.records[1]; rec.level != LogLevelError || rec.message != "error message" {
+		t.Errorf("unexpected second record: %+v", rec)
+	}
+	var ctx map[string]interface{}
+	if err := json.Unmarshal([]byte(handler.records[0].context), &ctx); err != nil {
+		t.Fatalf("invalid context JSON %q: %v", handler.records[0].context, err)
+	}
+	if ctx["block"] != float64(42) {
+		t.Errorf("context mismatch: have %v, want block=42", ctx)
+	}
+}
+
+func TestLogHandlerRateLimit(t *testing.T) {
+	handler := new(recordingLogHandler)
+	SetLogHandler(handler)
+	SetLogLevel(LogLevelTrace)
+	defer func() {
+		SetLogHandler(nil)
+		SetLogLevel(LogLevelInfo)
+	}()
+
+	for i := 0; i < 2*logRateLimit; i++ {
+		log.Info("storm", "i", i)
+	}
+	if len(handler.records) > logRateLimit {
+		t.Errorf("rate limit exceeded: have %d records, want at most %d", len(handler.records), logRateLimit)
+	}
+}
@@ -52,3 +52,50 @@ func (s *Strings) Set(index int, str string) error {
 func (s *Strings) String() string {
 	return fmt.Sprintf("%v", s.strs)
 }
+
+// Floats represents a slice of floats.
+type Floats struct{ floats []float64 }
+
+// NewFloats creates a slice of zero floats.
+func NewFloats(size int) *Floats {
+	return &Floats{
+		floats: make([]float64, size),
+	}
+}
+
+// NewFloatsEmpty creates an empty slice of floats.
+func NewFloatsEmpty() *Floats {
+	return NewFloats(0)
+}
+
+// Size returns the number of floats in the slice.
+func (f *Floats) Size() int {
+	return len(f.floats)
+}
+
+// Get returns the float at the given index from the slice.
+func (f *Floats) Get(index int) (float float64, _ error) {
+	if index < 0 || index >= len(f.floats) {
+		return 0, errors.New("index out of bounds")
+	}
+	return f.floats[index], nil
+}
+
+// Set sets the float at the given index in the slice.
+func (f *Floats) Set(index int, float float64) error {
+	if index < 0 || index >= len(f.floats) {
+		return errors.New("index out of bounds")
+	}
+	f.floats[index] = float
+	return nil
+}
+
+// Append adds a new float to the end of the slice.
+func (f *Floats) Append(float float64) {
+	f.floats = append(f.floats, float)
+}
+
+// String implements the Stringer interface.
+func (f *Floats) String() string {
+	return fmt.Sprintf("%v", f.floats)
+}
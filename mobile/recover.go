@@ -0,0 +1,59 @@
+// Copyright 2019 The bcl-chain Authors. All rights reserved.
+
+// Contains the panic recovery layer guarding the gomobile boundary.
+//
+// gomobile turns a Go panic into a process abort, so every exported method that
+// may panic defers one of the helpers below. Methods with an error return get the
+// panic converted into that error, methods without one return their documented
+// zero value. Both paths bump the counter reported by GetInternalErrorCount.
+
+package web3go
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// maxPanicStack is the number of stack trace bytes retained in recovered errors.
+const maxPanicStack = 2048
+
+// internalErrorCount is the number of panics recovered since startup.
+var internalErrorCount int64
+
+// GetInternalErrorCount returns the number of internal panics recovered at the
+// mobile boundary since the library was loaded.
+func GetInternalErrorCount() int64 {
+	return atomic.LoadInt64(&internalErrorCount)
+}
+
+// recoverError converts a panic into an error stored in err. It must be deferred
+// directly by the method being guarded.
+func recoverError(err *error) {
+	if r := recover(); r != nil {
+		*err = internalError(r)
+	}
+}
+
+// recoverZero swallows a panic, leaving the guarded method's results at their
+// zero values. It must be deferred directly by the method being guarded.
+func recoverZero() {
+	if r := recover(); r != nil {
+		internalError(r)
+	}
+}
+
+// internalError records a recovered panic and wraps it into an error carrying
+// the panic message and a truncated stack trace.
+func internalError(r interface{}) error {
+	atomic.AddInt64(&internalErrorCount, 1)
+
+	stack := debug.Stack()
+	if len(stack) > maxPanicStack {
+		stack = stack[:maxPanicStack]
+	}
+	log.Error("Recovered from internal panic", "err", r, "stack", string(stack))
+	return fmt.Errorf("internal error: %v\n%s", r, stack)
+}
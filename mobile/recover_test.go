@@ -0,0 +1,39 @@
+package web3go
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRecoverErrorReturn(t *testing.T) {
+	before := GetInternalErrorCount()
+
+	// A transaction wrapper without a backing transaction panics on recovery
+	tx := new(Transaction)
+	if _, err := tx.GetFrom(NewBigInt(1)); err == nil {
+		t.Fatal("expected error from recovered panic")
+	} else if !strings.HasPrefix(err.Error(), "internal error: ") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if have := GetInternalErrorCount(); have != before+1 {
+		t.Errorf("internal error count mismatch: have %d, want %d", have, before+1)
+	}
+}
+
+func TestRecoverZeroValue(t *testing.T) {
+	before := GetInternalErrorCount()
+
+	// A block wrapper without a backing block has no number
+	block := new(Block)
+	if number := block.GetNumberBig(); number != nil {
+		t.Errorf("number mismatch: have %v, want nil", number)
+	}
+	iface := NewInterface()
+	iface.SetString("not a bool")
+	if iface.GetBool() {
+		t.Error("expected false from mismatched interface type")
+	}
+	if have := GetInternalErrorCount(); have != before+2 {
+		t.Errorf("internal error count mismatch: have %d, want %d", have, before+2)
+	}
+}
@@ -0,0 +1,129 @@
+package web3go
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// typedDataPrimitive matches the EIP-712 atomic and dynamic types.
+var typedDataPrimitive = regexp.MustCompile(`^(address|bool|string|bytes([1-9]|[12][0-9]|3[0-2])?|u?int(8|16|24|32|40|48|56|64|72|80|88|96|104|112|120|128|136|144|152|160|168|176|184|192|200|208|216|224|232|240|248|256)?)$`)
+
+// typedDataArray matches the fixed or dynamic array suffixes of a type.
+var typedDataArray = regexp.MustCompile(`(\[[0-9]*\])+$`)
+
+// TypedData represents EIP-712 structured data, as signed by eth_signTypedData_v4.
+type TypedData struct {
+	data apitypes.TypedData
+}
+
+// NewTypedDataFromJSON parses the JSON encoding of a typed data object with the
+// types, primaryType, domain and message sections, validating the type
+// definitions.
+func NewTypedDataFromJSON(data string) (_ *TypedData, err error) {
+	defer recoverError(&err)
+	td := new(TypedData)
+	if err = json.Unmarshal([]byte(data), &td.data); err != nil {
+		return nil, err
+	}
+	if err = validateTypedData(&td.data); err != nil {
+		return nil, err
+	}
+	return td, nil
+}
+
+// validateTypedData checks that all sections are present and every type used is
+// either a primitive or defined in the types section.
+func validateTypedData(td *apitypes.TypedData) error {
+	if len(td.Types) == 0 {
+		return errors.New("typed data: missing types")
+	}
+	if _, ok := td.Types["EIP712Domain"]; !ok {
+		return errors.New("typed data: missing EIP712Domain type")
+	}
+	if td.PrimaryType == "" {
+		return errors.New("typed data: missing primaryType")
+	}
+	if _, ok := td.Types[td.PrimaryType]; !ok {
+		return fmt.Errorf("typed data: primary type %q is undefined", td.PrimaryType)
+	}
+	if td.Domain.ChainId == nil && td.Domain.Name == "" && td.Domain.Version == "" && td.Domain.VerifyingContract == "" && td.Domain.Salt == "" {
+		return errors.New("typed data: missing domain")
+	}
+	if td.Message == nil {
+		return errors.New("typed data: missing message")
+	}
+	for name, fields := range td.Types {
+		if name == "" {
+			return errors.New("typed data: empty type name")
+		}
+		for i, field := range fields {
+			if field.Name == "" {
+				return fmt.Errorf("typed data: type %q field %d: empty name", name, i)
+			}
+			if field.Type == "" {
+				return fmt.Errorf("typed data: type %q field %q: empty type", name, field.Name)
+			}
+			base := typedDataArray.ReplaceAllString(field.Type, "")
+			if typedDataPrimitive.MatchString(base) {
+				continue
+			}
+			if base == name {
+				return fmt.Errorf("typed data: type %q cannot reference itself", name)
+			}
+			if _, ok := td.Types[base]; !ok {
+				return fmt.Errorf("typed data: type %q field %q: type %q is undefined", name, field.Name, field.Type)
+			}
+		}
+	}
+	return nil
+}
+
+// HashTypedData returns the EIP-712 signing hash of the data, i.e. the keccak256
+// of "\x19\x01" + domainSeparator + hashStruct(message).
+func (td *TypedData) HashTypedData() (_ *Hash, err error) {
+	defer recoverError(&err)
+	hash, _, err := apitypes.TypedDataAndHash(td.data)
+	if err != nil {
+		return nil, err
+	}
+	return &Hash{hash: common.BytesToHash(hash)}, nil
+}
+
+// SignTypedData signs the data the way eth_signTypedData_v4 does, returning the
+// 65 byte [R || S || V] signature with V being 27 or 28.
+func (td *TypedData) SignTypedData(privKeyHex string) (_ []byte, err error) {
+	defer recoverError(&err)
+	hash, err := td.HashTypedData()
+	if err != nil {
+		return nil, err
+	}
+	key, err := parsePrivateKeyHex(privKeyHex)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroKey(key)
+
+	sig, err := crypto.Sign(hash.hash[:], key)
+	if err != nil {
+		return nil, err
+	}
+	sig[crypto.RecoveryIDOffset] += 27
+	return sig, nil
+}
+
+// RecoverTypedDataSigner returns the address that signed the data. The recovery
+// ID of the signature may be given either as 27/28 or as 0/1.
+func (td *TypedData) RecoverTypedDataSigner(sig []byte) (_ *Address, err error) {
+	defer recoverError(&err)
+	hash, err := td.HashTypedData()
+	if err != nil {
+		return nil, err
+	}
+	return recoverSigner(hash.hash[:], sig)
+}
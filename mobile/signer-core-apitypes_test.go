@@ -0,0 +1,124 @@
+package web3go
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Example from the EIP-712 specification, signed by keccak256("cow").
+const testTypedDataJSON = `{
+	"types": {
+		"EIP712Domain": [
+			{"name": "name", "type": "string"},
+			{"name": "version", "type": "string"},
+			{"name": "chainId", "type": "uint256"},
+			{"name": "verifyingContract", "type": "address"}
+		],
+		"Person": [
+			{"name": "name", "type": "string"},
+			{"name": "wallet", "type": "address"}
+		],
+		"Mail": [
+			{"name": "from", "type": "Person"},
+			{"name": "to", "type": "Person"},
+			{"name": "contents", "type": "string"}
+		]
+	},
+	"primaryType": "Mail",
+	"domain": {
+		"name": "Ether Mail",
+		"version": "1",
+		"chainId": 1,
+		"verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"
+	},
+	"message": {
+		"from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+		"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+		"contents": "Hello, Bob!"
+	}
+}`
+
+var (
+	testTypedDataHash = "0xbe609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2"
+	testTypedDataSig  = "0x4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b915621c"
+)
+
+func TestTypedDataSpecExample(t *testing.T) {
+	td, err := NewTypedDataFromJSON(testTypedDataJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := td.HashTypedData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash.GetHex() != testTypedDataHash {
+		t.Errorf("hash mismatch: have %s, want %s", hash.GetHex(), testTypedDataHash)
+	}
+	key := hexutil.Encode(crypto.Keccak256([]byte("cow")))
+	sig, err := td.SignTypedData(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hexutil.Encode(sig) != testTypedDataSig {
+		t.Errorf("signature mismatch: have %x, want %s", sig, testTypedDataSig)
+	}
+	signer, err := td.RecoverTypedDataSigner(sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := common.HexToAddress("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"); signer.address != want {
+		t.Errorf("signer mismatch: have %x, want %x", signer.address, want)
+	}
+}
+
+func TestTypedDataDynamicTypes(t *testing.T) {
+	data := strings.NewReplacer(
+		`{"name": "to", "type": "Person"}`, `{"name": "to", "type": "Person[]"}, {"name": "attachment", "type": "bytes"}`,
+		`{"name": "wallet", "type": "address"}`, `{"name": "wallets", "type": "address[]"}`,
+		`"wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"}`, `"wallets": ["0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"]}`,
+		`"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"}`, `"to": [{"name": "Bob", "wallets": ["0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB", "0xB0BdaBea57B0BDABeA57b0bdABEA57b0BDabEa57"]}], "attachment": "0x0123"`,
+	).Replace(testTypedDataJSON)
+
+	td, err := NewTypedDataFromJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := td.HashTypedData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash.GetHex() == testTypedDataHash {
+		t.Error("array and bytes fields did not affect the hash")
+	}
+}
+
+func TestTypedDataMalformed(t *testing.T) {
+	tests := []struct {
+		old, new string
+		err      string
+	}{
+		{`"primaryType": "Mail"`, `"primaryType": "Letter"`, `primary type "Letter" is undefined`},
+		{`{"name": "to", "type": "Person"}`, `{"name": "to", "type": "Human"}`, `type "Human" is undefined`},
+		{`{"name": "contents", "type": "string"}`, `{"name": "", "type": "string"}`, `empty name`},
+		{`"EIP712Domain"`, `"Domain"`, `missing EIP712Domain type`},
+	}
+	for _, tt := range tests {
+		_, err := NewTypedDataFromJSON(strings.Replace(testTypedDataJSON, tt.old, tt.new, 1))
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("replacing %s: error mismatch: have %v, want %q", tt.old, err, tt.err)
+		}
+	}
+	// Well formed types with a mismatching message must fail on hashing
+	td, err := NewTypedDataFromJSON(strings.Replace(testTypedDataJSON, `"contents": "Hello, Bob!"`, `"contents": {"nested": true}`, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := td.HashTypedData(); err == nil {
+		t.Error("expected error for mismatching message")
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2019 The bcl-chain Authors. All rights reserved.
+
+// Contains support for signing transactions with keys held outside the library.
+
+package web3go
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// HashSigner is implemented by the client app to sign with keys the library never
+// sees, e.g. in a hardware wallet, the platform keychain or a remote MPC service.
+//
+// SignHash must return the 65 byte [R || S || V] secp256k1 signature of the 32
+// byte hash as is, without hashing or prefixing it again. V may be 0/1 or 27/28.
+type HashSigner interface {
+	GetAddress() *Address
+	SignHash(hash []byte) ([]byte, error)
+}
+
+// SignTransactionWithSigner signs the transaction through an external signer.
+// The signing hash is computed with the signer matching the transaction type and
+// chain ID, which may only be nil for legacy transactions without replay
+// protection. The external signer is called exactly once, and its signature is
+// only attached if it recovers to the address the signer reports.
+func SignTransactionWithSigner(tx *Transaction, signer HashSigner, chainID *BigInt) (_ *Transaction, err error) {
+	defer recoverError(&err)
+	if isSigned(tx.tx) {
+		return nil, errors.New("transaction already signed")
+	}
+	txSigner, err := transactionSigner(tx.tx, chainID)
+	if err != nil {
+		return nil, err
+	}
+	hash := txSigner.Hash(tx.tx)
+	sig, err := signer.SignHash(common.CopyBytes(hash[:]))
+	if err != nil {
+		return nil, err
+	}
+	sig, err = normalizeSignature(sig)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := crypto.SigToPub(hash[:], sig)
+	if err != nil {
+		return nil, err
+	}
+	if from, want := crypto.PubkeyToAddress(*pub), signer.GetAddress(); from != want.address {
+		return nil, fmt.Errorf("signature recovers to %s instead of signer address %s", from.Hex(), want.address.Hex())
+	}
+	signed, err := tx.tx.WithSignature(txSigner, sig)
+	if err != nil {
+		return nil, err
+	}
+	return &Transaction{tx: signed}, nil
+}
@@ -0,0 +1,79 @@
+package web3go
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// testHashSigner is a mock external signer counting its invocations.
+type testHashSigner struct {
+	keyHex  string
+	address *Address
+	legacyV bool  // Whether to return V as 27/28
+	err     error // Error to fail signing with
+	calls   int
+	hashes  [][]byte
+}
+
+func (s *testHashSigner) GetAddress() *Address { return s.address }
+
+func (s *testHashSigner) SignHash(hash []byte) ([]byte, error) {
+	s.calls++
+	s.hashes = append(s.hashes, hash)
+	if s.err != nil {
+		return nil, s.err
+	}
+	key, _ := crypto.HexToECDSA(s.keyHex)
+	sig, err := crypto.Sign(hash, key)
+	if err == nil && s.legacyV {
+		sig[64] += 27
+	}
+	return sig, err
+}
+
+func TestSignTransactionWithSigner(t *testing.T) {
+	chainID := NewBigInt(1)
+	txs := []*Transaction{
+		NewTransaction(0, NewSeededAddress(1), NewBigInt(1), 21000, NewBigInt(1), nil),
+		newTestDynamicFeeTx(chainID, 1, NewSeededAddress(1), NewBigInt(1), 21000, NewBigInt(1), NewBigInt(2), nil),
+	}
+	for i, tx := range txs {
+		for _, legacyV := range []bool{false, true} {
+			signer := &testHashSigner{keyHex: testKeyHex, address: testAddress, legacyV: legacyV}
+			signed, err := SignTransactionWithSigner(tx, signer, chainID)
+			if err != nil {
+				t.Fatalf("tx %d: %v", i, err)
+			}
+			if signer.calls != 1 {
+				t.Errorf("tx %d: signer called %d times, want once", i, signer.calls)
+			}
+			if hash, _ := tx.GetSigningHash(chainID); string(signer.hashes[0]) != string(hash.hash[:]) {
+				t.Errorf("tx %d: signed hash mismatch: have %x, want %x", i, signer.hashes[0], hash.hash)
+			}
+			if from, err := signed.GetFrom(chainID); err != nil || !from.Equals(testAddress) {
+				t.Errorf("tx %d: sender mismatch: have %v (%v)", i, from, err)
+			}
+		}
+	}
+	// A signature by another key must not be attached
+	other := &testHashSigner{keyHex: testKeyHex, address: NewSeededAddress(2)}
+	if _, err := SignTransactionWithSigner(txs[1], other, chainID); err == nil || !strings.Contains(err.Error(), "instead of signer address") {
+		t.Errorf("foreign signature error mismatch: %v", err)
+	}
+	failing := &testHashSigner{address: testAddress, err: errors.New("user rejected")}
+	if _, err := SignTransactionWithSigner(txs[1], failing, chainID); err == nil || err.Error() != "user rejected" {
+		t.Errorf("signer error mismatch: %v", err)
+	}
+	// Signing hashes are never computed without a chain ID for typed transactions
+	unused := &testHashSigner{keyHex: testKeyHex, address: testAddress}
+	if _, err := SignTransactionWithSigner(txs[1], unused, nil); err == nil || unused.calls != 0 {
+		t.Errorf("missing chain ID mismatch: %v, %d calls", err, unused.calls)
+	}
+	signed, _ := SignTransactionWithSigner(txs[0], unused, chainID)
+	if _, err := SignTransactionWithSigner(signed, unused, chainID); err == nil {
+		t.Error("expected error for signed transaction")
+	}
+}
@@ -0,0 +1,215 @@
+// Copyright 2019 The bcl-chain Authors. All rights reserved.
+
+// Contains the retrieval of account state through the client.
+
+package web3go
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// stateBackend is the part of the client API used to retrieve account state.
+type stateBackend interface {
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+	PendingBalanceAt(ctx context.Context, account common.Address) (*big.Int, error)
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error)
+	PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error)
+	StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error)
+	PendingStorageAt(ctx context.Context, account common.Address, key common.Hash) ([]byte, error)
+}
+
+// BalanceAt retrieves the wei balance of the account at the given block. A
+// negative number retrieves it at the latest block, or at the block of a tag
+// like SafeBlockNumber. Nodes without the state of old blocks fail with their
+// own error rather than reporting a zero balance.
+func (ec *EthereumClient) BalanceAt(ctx *Context, account *Address, blockNumber int64) (_ *BigInt, err error) {
+	defer recoverError(&err)
+	return balanceAt(ctx.context, ec.client, account.address, blockNumber)
+}
+
+// PendingBalanceAt retrieves the wei balance of the account in the pending state.
+func (ec *EthereumClient) PendingBalanceAt(ctx *Context, account *Address) (_ *BigInt, err error) {
+	defer recoverError(&err)
+	balance, err := ec.client.PendingBalanceAt(ctx.context, account.address)
+	if err != nil {
+		return nil, err
+	}
+	return &BigInt{balance}, nil
+}
+
+// BalanceAtHash retrieves the wei balance of the account at the block with the
+// given hash, which is unambiguous during reorgs. If requireCanonical is set,
+// the query fails once the block is no longer canonical. Nodes not supporting
+// EIP-1898 block hash parameters fail with an error IsBlockHashNotSupported
+// reports.
+func (ec *EthereumClient) BalanceAtHash(ctx *Context, account *Address, blockHash *Hash, requireCanonical bool) (_ *BigInt, err error) {
+	defer recoverError(&err)
+	return balanceAtHash(ctx.context, ec.client.Client(), account.address, blockHash.hash, requireCanonical)
+}
+
+func balanceAt(ctx context.Context, backend stateBackend, account common.Address, blockNumber int64) (*BigInt, error) {
+	balance, err := backend.BalanceAt(ctx, account, blockNumberArg(blockNumber))
+	if err != nil {
+		return nil, err
+	}
+	return &BigInt{balance}, nil
+}
+
+func balanceAtHash(ctx context.Context, backend callBackend, account common.Address, blockHash common.Hash, requireCanonical bool) (*BigInt, error) {
+	var balance hexutil.Big
+	if err := backend.CallContext(ctx, &balance, "eth_getBalance", account, blockHashParam(blockHash, requireCanonical)); err != nil {
+		return nil, blockHashError("eth_getBalance", 1, err, func() error {
+			return backend.CallContext(ctx, &balance, "eth_getBalance", account, "latest")
+		})
+	}
+	return &BigInt{(*big.Int)(&balance)}, nil
+}
+
+// NonceAt retrieves the nonce of the account at the given block, i.e. the
+// number of transactions it sent. A negative number retrieves it at the latest
+// block, or at the block of a tag like SafeBlockNumber. Nonces beyond the int64
+// range fail with the error "nonce N overflows int64".
+func (ec *EthereumClient) NonceAt(ctx *Context, account *Address, blockNumber int64) (_ int64, err error) {
+	defer recoverError(&err)
+	return nonceAt(ctx.context, ec.client, account.address, blockNumber)
+}
+
+// PendingNonceAt retrieves the nonce of the account in the pending state, which
+// counts the transactions queued in the pool of the node too. This is the nonce
+// of the next transaction to send.
+func (ec *EthereumClient) PendingNonceAt(ctx *Context, account *Address) (_ int64, err error) {
+	defer recoverError(&err)
+	return pendingNonceAt(ctx.context, ec.client, account.address)
+}
+
+func nonceAt(ctx context.Context, backend stateBackend, account common.Address, blockNumber int64) (int64, error) {
+	nonce, err := backend.NonceAt(ctx, account, blockNumberArg(blockNumber))
+	if err != nil {
+		return 0, err
+	}
+	return uint64ToInt64(nonce, "nonce")
+}
+
+func pendingNonceAt(ctx context.Context, backend stateBackend, account common.Address) (int64, error) {
+	nonce, err := backend.PendingNonceAt(ctx, account)
+	if err != nil {
+		return 0, err
+	}
+	return uint64ToInt64(nonce, "nonce")
+}
+
+// CodeAt retrieves the contract code of the account at the given block, which is
+// empty but never nil for externally owned accounts. A negative number retrieves
+// it at the latest block, or at the block of a tag like SafeBlockNumber.
+func (ec *EthereumClient) CodeAt(ctx *Context, account *Address, blockNumber int64) (_ []byte, err error) {
+	defer recoverError(&err)
+	return codeAt(ctx.context, ec.client, account.address, blockNumber)
+}
+
+// PendingCodeAt retrieves the contract code of the account in the pending state,
+// which is empty but never nil for externally owned accounts.
+func (ec *EthereumClient) PendingCodeAt(ctx *Context, account *Address) (_ []byte, err error) {
+	defer recoverError(&err)
+	return pendingCodeAt(ctx.context, ec.client, account.address)
+}
+
+// IsContract reports whether the account has contract code at the latest block.
+// Note that contracts under construction and destructed ones have no code.
+func (ec *EthereumClient) IsContract(ctx *Context, account *Address) (_ bool, err error) {
+	defer recoverError(&err)
+	return isContract(ctx.context, ec.client, account.address)
+}
+
+func codeAt(ctx context.Context, backend stateBackend, account common.Address, blockNumber int64) ([]byte, error) {
+	code, err := backend.CodeAt(ctx, account, blockNumberArg(blockNumber))
+	if err != nil {
+		return nil, err
+	}
+	if code == nil {
+		code = []byte{}
+	}
+	return code, nil
+}
+
+func pendingCodeAt(ctx context.Context, backend stateBackend, account common.Address) ([]byte, error) {
+	code, err := backend.PendingCodeAt(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+	if code == nil {
+		code = []byte{}
+	}
+	return code, nil
+}
+
+func isContract(ctx context.Context, backend stateBackend, account common.Address) (bool, error) {
+	code, err := codeAt(ctx, backend, account, LatestBlockNumber)
+	if err != nil {
+		return false, err
+	}
+	return len(code) > 0, nil
+}
+
+// StorageAt retrieves the 32 byte value of the storage slot of the account at
+// the given block, e.g. the implementation slot of a proxy. A negative number
+// retrieves it at the latest block, or at the block of a tag like
+// SafeBlockNumber. Short values served by some nodes are left-padded.
+func (ec *EthereumClient) StorageAt(ctx *Context, account *Address, slot *Hash, blockNumber int64) (_ []byte, err error) {
+	defer recoverError(&err)
+	return storageAt(ctx.context, ec.client, account.address, slot.hash, blockNumber)
+}
+
+// PendingStorageAt retrieves the 32 byte value of the storage slot of the
+// account in the pending state.
+func (ec *EthereumClient) PendingStorageAt(ctx *Context, account *Address, slot *Hash) (_ []byte, err error) {
+	defer recoverError(&err)
+	return pendingStorageAt(ctx.context, ec.client, account.address, slot.hash)
+}
+
+func storageAt(ctx context.Context, backend stateBackend, account common.Address, slot common.Hash, blockNumber int64) ([]byte, error) {
+	value, err := backend.StorageAt(ctx, account, slot, blockNumberArg(blockNumber))
+	if err != nil {
+		return nil, err
+	}
+	return storageWord(value)
+}
+
+func pendingStorageAt(ctx context.Context, backend stateBackend, account common.Address, slot common.Hash) ([]byte, error) {
+	value, err := backend.PendingStorageAt(ctx, account, slot)
+	if err != nil {
+		return nil, err
+	}
+	return storageWord(value)
+}
+
+// StorageAtHash retrieves the 32 byte value of the storage slot of the account
+// at the block with the given hash, see StorageAt and BalanceAtHash.
+func (ec *EthereumClient) StorageAtHash(ctx *Context, account *Address, slot *Hash, blockHash *Hash, requireCanonical bool) (_ []byte, err error) {
+	defer recoverError(&err)
+	return storageAtHash(ctx.context, ec.client.Client(), account.address, slot.hash, blockHash.hash, requireCanonical)
+}
+
+func storageAtHash(ctx context.Context, backend callBackend, account common.Address, slot common.Hash, blockHash common.Hash, requireCanonical bool) ([]byte, error) {
+	var value hexutil.Bytes
+	if err := backend.CallContext(ctx, &value, "eth_getStorageAt", account, slot, blockHashParam(blockHash, requireCanonical)); err != nil {
+		return nil, blockHashError("eth_getStorageAt", 2, err, func() error {
+			return backend.CallContext(ctx, &value, "eth_getStorageAt", account, slot, "latest")
+		})
+	}
+	return storageWord(value)
+}
+
+// storageWord left-pads a storage value to 32 bytes.
+func storageWord(value []byte) ([]byte, error) {
+	if len(value) > common.HashLength {
+		return nil, fmt.Errorf("storage value of %d bytes exceeds %d", len(value), common.HashLength)
+	}
+	return common.LeftPadBytes(value, common.HashLength), nil
+}
@@ -0,0 +1,282 @@
+package web3go
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// testStateService mocks the state access methods of the eth namespace for a
+// chain whose state is pruned below block prunedBelow.
+type testStateService struct {
+	balances    []map[common.Address]*big.Int // Balances by block number
+	pending     map[common.Address]*big.Int
+	nonces      map[common.Address]uint64 // Nonces at the latest block
+	codes       map[common.Address][]byte // Codes deployed in block 2
+	storage     map[common.Hash][]byte    // Raw slot values of any account
+	hashes      map[common.Hash]int       // Block numbers by hash
+	prunedBelow int
+
+	blocks []rpc.BlockNumberOrHash // Requested blocks
+}
+
+// block resolves the requested block to its number, -1 selecting pending.
+func (s *testStateService) block(block rpc.BlockNumberOrHash) (int, error) {
+	s.blocks = append(s.blocks, block)
+	if hash, ok := block.Hash(); ok {
+		number, ok := s.hashes[hash]
+		if !ok {
+			return 0, errors.New("header for hash not found")
+		}
+		return number, nil
+	}
+	number, _ := block.Number()
+	switch {
+	case number == rpc.PendingBlockNumber:
+		return -1, nil
+	case number < 0:
+		return len(s.balances) - 1, nil
+	case int(number) >= len(s.balances):
+		return 0, errors.New("header not found")
+	case int(number) < s.prunedBelow:
+		return 0, errors.New("missing trie node 0123 (path ) state 0x0123 is not available, not found")
+	}
+	return int(number), nil
+}
+
+func (s *testStateService) GetBalance(account common.Address, block rpc.BlockNumberOrHash) (*hexutil.Big, error) {
+	number, err := s.block(block)
+	if err != nil {
+		return nil, err
+	}
+	balances := s.pending
+	if number >= 0 {
+		balances = s.balances[number]
+	}
+	balance := new(big.Int)
+	if balances[account] != nil {
+		balance.Set(balances[account])
+	}
+	return (*hexutil.Big)(balance), nil
+}
+
+func (s *testStateService) GetTransactionCount(account common.Address, block rpc.BlockNumberOrHash) (*hexutil.Uint64, error) {
+	number, err := s.block(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := s.nonces[account]
+	if number < 0 && nonce < math.MaxUint64 {
+		nonce++ // One more transaction queued in the pool
+	}
+	return (*hexutil.Uint64)(&nonce), nil
+}
+
+func (s *testStateService) GetCode(account common.Address, block rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	number, err := s.block(block)
+	if err != nil {
+		return nil, err
+	}
+	if number >= 0 && number < 2 {
+		return hexutil.Bytes{}, nil
+	}
+	return s.codes[account], nil
+}
+
+func (s *testStateService) GetStorageAt(account common.Address, slot common.Hash, block rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	if _, err := s.block(block); err != nil {
+		return nil, err
+	}
+	return s.storage[slot], nil
+}
+
+// newTestStateService creates a chain of three blocks with the state of the
+// first one pruned, where the balance of the account grows in every block.
+func newTestStateService(account common.Address) *testStateService {
+	large, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	return &testStateService{
+		balances: []map[common.Address]*big.Int{
+			{account: big.NewInt(1)},
+			{account: big.NewInt(1000)},
+			{account: large},
+		},
+		pending:     map[common.Address]*big.Int{account: new(big.Int).Add(large, big.NewInt(1))},
+		nonces:      map[common.Address]uint64{account: 5, {0xff}: math.MaxUint64},
+		codes:       map[common.Address][]byte{account: {0x60, 0x80, 0x60, 0x40}},
+		hashes:      map[common.Hash]int{{0x01}: 1},
+		prunedBelow: 1,
+	}
+}
+
+func TestBalanceAt(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		account = NewSeededAddress(1).address
+		service = newTestStateService(account)
+		client  = newTestRPCClient(t, service)
+	)
+	for number, want := range map[int64]string{
+		1:                  "1000",
+		2:                  "123456789012345678901234567890",
+		LatestBlockNumber:  "123456789012345678901234567890",
+		PendingBlockNumber: "123456789012345678901234567891",
+	} {
+		balance, err := balanceAt(ctx, client, account, number)
+		if err != nil {
+			t.Fatalf("block %d: %v", number, err)
+		}
+		if balance.String() != want {
+			t.Errorf("block %d: balance mismatch: have %s, want %s", number, balance, want)
+		}
+	}
+	if balance, err := balanceAt(ctx, client, common.Address{1}, 2); err != nil || balance.String() != "0" {
+		t.Errorf("empty account balance mismatch: have %v (%v)", balance, err)
+	}
+	// Pruned state must fail instead of reporting an empty balance
+	if balance, err := balanceAt(ctx, client, account, 0); err == nil || !strings.Contains(err.Error(), "missing trie node") {
+		t.Errorf("pruned state error mismatch: have %v (%v)", balance, err)
+	}
+	balance, err := balanceAtHash(ctx, client.Client(), account, common.Hash{0x01}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance.String() != "1000" {
+		t.Errorf("balance by hash mismatch: have %s, want 1000", balance)
+	}
+	if last := service.blocks[len(service.blocks)-1]; last.BlockHash == nil || !last.RequireCanonical {
+		t.Errorf("block hash parameter mismatch: %+v", last)
+	}
+}
+
+func TestNonceAt(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		account = NewSeededAddress(1).address
+		service = newTestStateService(account)
+		client  = newTestRPCClient(t, service)
+	)
+	nonce, err := nonceAt(ctx, client, account, LatestBlockNumber)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nonce != 5 {
+		t.Errorf("nonce mismatch: have %d, want 5", nonce)
+	}
+	if last := service.blocks[len(service.blocks)-1]; last.BlockNumber == nil || *last.BlockNumber != rpc.LatestBlockNumber {
+		t.Errorf("latest block parameter mismatch: %+v", last)
+	}
+	pending, err := pendingNonceAt(ctx, client, account)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending != 6 {
+		t.Errorf("pending nonce mismatch: have %d, want 6", pending)
+	}
+	if last := service.blocks[len(service.blocks)-1]; last.BlockNumber == nil || *last.BlockNumber != rpc.PendingBlockNumber {
+		t.Errorf("pending tag not sent: %+v", last)
+	}
+	if _, err := nonceAt(ctx, client, common.Address{0xff}, LatestBlockNumber); err == nil || err.Error() != "nonce 18446744073709551615 overflows int64" {
+		t.Errorf("overflow error mismatch: %v", err)
+	}
+	if _, err := nonceAt(ctx, client, account, 0); err == nil || !strings.Contains(err.Error(), "missing trie node") {
+		t.Errorf("pruned state error mismatch: %v", err)
+	}
+}
+
+func TestCodeAt(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		account = NewSeededAddress(1).address
+		service = newTestStateService(account)
+		client  = newTestRPCClient(t, service)
+	)
+	code, err := pendingCodeAt(ctx, client, account)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(code, []byte{0x60, 0x80, 0x60, 0x40}) {
+		t.Errorf("pending code mismatch: have %x", code)
+	}
+	// Accounts without code must yield an empty, non-nil slice
+	for _, query := range []func() ([]byte, error){
+		func() ([]byte, error) { return codeAt(ctx, client, account, 1) },
+		func() ([]byte, error) { return pendingCodeAt(ctx, client, common.Address{1}) },
+	} {
+		code, err := query()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code == nil || len(code) != 0 {
+			t.Errorf("empty code mismatch: have %#v", code)
+		}
+	}
+	if contract, err := isContract(ctx, client, account); err != nil || !contract {
+		t.Errorf("contract detection mismatch: have %v (%v)", contract, err)
+	}
+	if contract, err := isContract(ctx, client, common.Address{1}); err != nil || contract {
+		t.Errorf("account detected as contract: %v", err)
+	}
+	service.prunedBelow = 2
+	if _, err := codeAt(ctx, client, account, 1); err == nil || err.Error() != "missing trie node 0123 (path ) state 0x0123 is not available, not found" {
+		t.Errorf("pruned state error not propagated verbatim: %v", err)
+	}
+}
+
+func TestStorageAt(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		account = NewSeededAddress(1).address
+		service = newTestStateService(account)
+		client  = newTestRPCClient(t, service)
+		full    = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+	)
+	service.storage = map[common.Hash][]byte{
+		{0x01}: {0x2a},
+		{0x02}: full.Bytes(),
+		{0x03}: make([]byte, 33),
+	}
+	for slot, want := range map[common.Hash]common.Hash{
+		{0x01}: common.BigToHash(big.NewInt(42)),
+		{0x02}: full,
+		{0x04}: {},
+	} {
+		value, err := storageAt(ctx, client, account, slot, LatestBlockNumber)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(value, want[:]) {
+			t.Errorf("slot %x: value mismatch: have %x, want %x", slot, value, want)
+		}
+		pending, err := pendingStorageAt(ctx, client, account, slot)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(pending, want[:]) {
+			t.Errorf("slot %x: pending value mismatch: have %x, want %x", slot, pending, want)
+		}
+	}
+	value, err := storageAtHash(ctx, client.Client(), account, common.Hash{0x01}, common.Hash{0x01}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(value, common.BigToHash(big.NewInt(42)).Bytes()) {
+		t.Errorf("value by hash mismatch: have %x", value)
+	}
+	if last := service.blocks[len(service.blocks)-1]; last.BlockHash == nil || *last.BlockHash != (common.Hash{0x01}) {
+		t.Errorf("block hash parameter mismatch: %+v", last)
+	}
+	if _, err := storageAt(ctx, client, account, common.Hash{0x03}, LatestBlockNumber); err == nil {
+		t.Error("expected error for oversized value")
+	}
+	if _, err := storageAt(ctx, client, account, common.Hash{0x01}, 0); err == nil || !strings.Contains(err.Error(), "missing trie node") {
+		t.Errorf("pruned state error mismatch: %v", err)
+	}
+}
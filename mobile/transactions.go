@@ -0,0 +1,153 @@
+// Copyright 2019 The bcl-chain Authors. All rights reserved.
+
+// Contains the retrieval of transactions and receipts through the client.
+
+package web3go
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrTransactionNotFound is returned if the node does not know the requested
+// transaction, neither in a block nor in its pending pool.
+var ErrTransactionNotFound = errors.New("transaction not found")
+
+// ErrReceiptNotFound is returned if the node has no receipt for the requested
+// transaction, because it is unknown or still pending.
+var ErrReceiptNotFound = errors.New("receipt not found")
+
+// transactionBackend is the part of the client API used to retrieve transactions.
+type transactionBackend interface {
+	TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error)
+}
+
+// blockTransactionBackend is the part of the client API used to page through the
+// transactions of a block.
+type blockTransactionBackend interface {
+	TransactionInBlock(ctx context.Context, blockHash common.Hash, index uint) (*types.Transaction, error)
+}
+
+// callBackend is the raw RPC client API, used where the typed client drops
+// information, e.g. to decode receipts the same way as NewReceiptFromJSON or to
+// tell unknown blocks from empty ones.
+type callBackend interface {
+	CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error
+}
+
+// TransactionResult is a transaction retrieved from the node, along with
+// whether it is still pending.
+type TransactionResult struct {
+	tx      *types.Transaction
+	pending bool
+}
+
+// GetTransaction ...
+func (r *TransactionResult) GetTransaction() *Transaction { return &Transaction{tx: r.tx} }
+
+// IsPending reports whether the transaction is still waiting in the pending
+// pool, not included in a block yet.
+func (r *TransactionResult) IsPending() bool { return r.pending }
+
+// TransactionByHash retrieves the transaction with the given hash, be it mined
+// or pending. An unknown transaction fails with ErrTransactionNotFound.
+func (ec *EthereumClient) TransactionByHash(ctx *Context, hash *Hash) (_ *TransactionResult, err error) {
+	defer recoverError(&err)
+	return transactionByHash(ctx.context, ec.client, hash.hash)
+}
+
+func transactionByHash(ctx context.Context, backend transactionBackend, hash common.Hash) (*TransactionResult, error) {
+	tx, pending, err := backend.TransactionByHash(ctx, hash)
+	if err == ethereum.NotFound || err == nil && tx == nil {
+		return nil, ErrTransactionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if tx.Hash() != hash {
+		return nil, fmt.Errorf("transaction hash mismatch: have %x, want %x", tx.Hash(), hash)
+	}
+	return &TransactionResult{tx: tx, pending: pending}, nil
+}
+
+// TransactionReceipt retrieves the receipt of the mined transaction with the
+// given hash, including the block hash and number, the transaction index, the
+// effective gas price and the logs. A transaction unknown to the node or still
+// pending fails with ErrReceiptNotFound.
+func (ec *EthereumClient) TransactionReceipt(ctx *Context, txHash *Hash) (_ *Receipt, err error) {
+	defer recoverError(&err)
+	return transactionReceipt(ctx.context, ec.client.Client(), txHash.hash)
+}
+
+func transactionReceipt(ctx context.Context, backend callBackend, hash common.Hash) (*Receipt, error) {
+	var raw json.RawMessage
+	if err := backend.CallContext(ctx, &raw, "eth_getTransactionReceipt", hash); err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, ErrReceiptNotFound
+	}
+	receipt, err := decodeReceiptJSON(raw)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.receipt.TxHash != hash {
+		return nil, fmt.Errorf("receipt transaction hash mismatch: have %x, want %x", receipt.receipt.TxHash, hash)
+	}
+	return receipt, nil
+}
+
+// TransactionCount retrieves the number of transactions in the block with the
+// given hash, without downloading the block. An unknown block fails with an
+// error IsNotFound reports.
+func (ec *EthereumClient) TransactionCount(ctx *Context, blockHash *Hash) (_ int, err error) {
+	defer recoverError(&err)
+	count, err := transactionCount(ctx.context, ec.client.Client(), blockHash.hash)
+	return int(count), err
+}
+
+func transactionCount(ctx context.Context, backend callBackend, blockHash common.Hash) (uint, error) {
+	var count *hexutil.Uint
+	if err := backend.CallContext(ctx, &count, "eth_getBlockTransactionCountByHash", blockHash); err != nil {
+		return 0, err
+	}
+	if count == nil {
+		return 0, ethereum.NotFound
+	}
+	return uint(*count), nil
+}
+
+// TransactionInBlock retrieves the transaction at the given index of the block
+// with the given hash, without downloading the block. An index out of the range
+// of the block's transactions fails with a descriptive error, an unknown block
+// with an error IsNotFound reports.
+func (ec *EthereumClient) TransactionInBlock(ctx *Context, blockHash *Hash, index int) (_ *Transaction, err error) {
+	defer recoverError(&err)
+	return transactionInBlock(ctx.context, ec.client, ec.client.Client(), blockHash.hash, index)
+}
+
+func transactionInBlock(ctx context.Context, backend blockTransactionBackend, caller callBackend, blockHash common.Hash, index int) (*Transaction, error) {
+	if index < 0 {
+		return nil, fmt.Errorf("invalid transaction index %d", index)
+	}
+	tx, err := backend.TransactionInBlock(ctx, blockHash, uint(index))
+	if err == ethereum.NotFound || err == nil && tx == nil {
+		// Nodes answer null for both unknown blocks and indices past the end
+		count, err := transactionCount(ctx, caller, blockHash)
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("transaction index %d out of range, block %x has %d transactions", index, blockHash, count)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Transaction{tx: tx}, nil
+}
@@ -0,0 +1,268 @@
+package web3go
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// testEthService mocks the eth namespace of a node, serving recorded responses.
+type testEthService struct {
+	txs      map[common.Hash]json.RawMessage
+	receipts map[common.Hash]json.RawMessage
+	blocks   map[common.Hash]*Block
+}
+
+func (s *testEthService) GetBlockByHash(hash common.Hash, fullTxs bool) (json.RawMessage, error) {
+	block, ok := s.blocks[hash]
+	if !ok {
+		return json.RawMessage("null"), nil
+	}
+	data, err := block.EncodeRPCJSON(fullTxs)
+	return json.RawMessage(data), err
+}
+
+func (s *testEthService) GetBlockTransactionCountByHash(hash common.Hash) *hexutil.Uint {
+	block, ok := s.blocks[hash]
+	if !ok {
+		return nil
+	}
+	count := hexutil.Uint(block.GetTransactionCount())
+	return &count
+}
+
+func (s *testEthService) GetTransactionByBlockHashAndIndex(hash common.Hash, index hexutil.Uint) (json.RawMessage, error) {
+	block, ok := s.blocks[hash]
+	if !ok {
+		return json.RawMessage("null"), nil
+	}
+	data, err := block.EncodeRPCJSON(true)
+	if err != nil {
+		return nil, err
+	}
+	var fields struct {
+		Transactions []json.RawMessage `json:"transactions"`
+	}
+	if err := json.Unmarshal([]byte(data), &fields); err != nil {
+		return nil, err
+	}
+	if int(index) >= len(fields.Transactions) {
+		return json.RawMessage("null"), nil
+	}
+	return fields.Transactions[index], nil
+}
+
+func (s *testEthService) GetTransactionByHash(hash common.Hash) json.RawMessage {
+	if tx, ok := s.txs[hash]; ok {
+		return tx
+	}
+	return json.RawMessage("null")
+}
+
+func (s *testEthService) GetTransactionReceipt(hash common.Hash) json.RawMessage {
+	if receipt, ok := s.receipts[hash]; ok {
+		return receipt
+	}
+	return json.RawMessage("null")
+}
+
+// newTestRPCClient starts an in-process RPC server serving the service as the
+// eth namespace and returns a client connected to it.
+func newTestRPCClient(t *testing.T, service interface{}) *ethclient.Client {
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", service); err != nil {
+		t.Fatal(err)
+	}
+	client := rpc.DialInProc(server)
+	t.Cleanup(func() {
+		client.Close()
+		server.Stop()
+	})
+	return ethclient.NewClient(client)
+}
+
+func TestTransactionByHash(t *testing.T) {
+	var (
+		chainID = NewBigInt(FixtureChainID)
+		service = &testEthService{txs: make(map[common.Hash]json.RawMessage)}
+		client  = newTestRPCClient(t, service)
+	)
+	mined, _ := NewFixtureTransaction(0, 1)
+	pending, _ := NewFixtureTransaction(1, 1)
+
+	minedJSON, err := mined.EncodeRPCJSON(chainID, NewSeededHash(1), 100, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pendingJSON, err := pending.EncodeRPCJSON(chainID, nil, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.txs[mined.tx.Hash()] = json.RawMessage(minedJSON)
+	service.txs[pending.tx.Hash()] = json.RawMessage(pendingJSON)
+
+	for _, want := range []struct {
+		tx      *Transaction
+		pending bool
+	}{{mined, false}, {pending, true}} {
+		result, err := transactionByHash(context.Background(), client, want.tx.tx.Hash())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.IsPending() != want.pending {
+			t.Errorf("transaction %d pending mismatch: have %v, want %v", want.tx.GetNonce(), result.IsPending(), want.pending)
+		}
+		tx := result.GetTransaction()
+		if !tx.GetHash().Equals(want.tx.GetHash()) || tx.GetType() != TxTypeDynamicFee {
+			t.Errorf("transaction %d decoded as %s of type %d", want.tx.GetNonce(), tx.GetHash().GetHex(), tx.GetType())
+		}
+	}
+	if _, err := transactionByHash(context.Background(), client, common.Hash{1}); err != ErrTransactionNotFound || !IsNotFound(err) {
+		t.Errorf("missing transaction error mismatch: %v", err)
+	}
+}
+
+// testRPCTransferReceipt is a generated receipt of a type-2 USDC transfer, in the
+// format returned by eth_getTransactionReceipt of mainnet nodes.
+const testRPCTransferReceipt = `{
+	"blockHash": "0x99031645953235a8769ab14d28a0b7ef772fe706f52da383334fa82b9817faab",
+	"blockNumber": "0x112a880",
+	"contractAddress": null,
+	"cumulativeGasUsed": "0xc6539",
+	"effectiveGasPrice": "0x4f7c8c840",
+	"from": "0x71562b71999873db5b286df957af199ec94617f7",
+	"gasUsed": "0xaff9",
+	"logs": [
+		{
+			"address": "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+			"blockHash": "0x99031645953235a8769ab14d28a0b7ef772fe706f52da383334fa82b9817faab",
+			"blockNumber": "0x112a880",
+			"data": "0x000000000000000000000000000000000000000000000000000000000ee6b280",
+			"logIndex": "0x2a",
+			"removed": false,
+			"topics": [
+				"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+				"0x00000000000000000000000071562b71999873db5b286df957af199ec94617f7",
+				"0x000000000000000000000000095e7baea6a6c7c4c2dfeb977efac326af552d87"
+			],
+			"transactionHash": "0x2f4fda957ee3493a572ecfca2b8203a427bdb319c1ba36e0406e75125b91ed9b",
+			"transactionIndex": "0x7"
+		}
+	],
+	"logsBloom": "0x00000000000000000000000000000000000000000000000000002000004000000000000000000000004000000000000000000000000000000000000000000000000000000000000008000008000000000000000000000000000000000000000000000000000000000000000001000000000000000000000000000010000000000000000000000000000000000000000000000000010000000000000000000000000000000020200000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000",
+	"status": "0x1",
+	"to": "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+	"transactionHash": "0x2f4fda957ee3493a572ecfca2b8203a427bdb319c1ba36e0406e75125b91ed9b",
+	"transactionIndex": "0x7",
+	"type": "0x2"
+}`
+
+func TestTransactionReceipt(t *testing.T) {
+	var (
+		hash    = common.HexToHash("0x2f4fda957ee3493a572ecfca2b8203a427bdb319c1ba36e0406e75125b91ed9b")
+		service = &testEthService{receipts: map[common.Hash]json.RawMessage{hash: json.RawMessage(testRPCTransferReceipt)}}
+		client  = newTestRPCClient(t, service).Client()
+	)
+	receipt, err := transactionReceipt(context.Background(), client, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt.GetBlockHash().GetHex() != "0x99031645953235a8769ab14d28a0b7ef772fe706f52da383334fa82b9817faab" {
+		t.Errorf("block hash mismatch: have %s", receipt.GetBlockHash().GetHex())
+	}
+	if number, err := receipt.GetBlockNumber(); err != nil || number != 18000000 {
+		t.Errorf("block number mismatch: have %d (%v), want 18000000", number, err)
+	}
+	if receipt.GetTransactionIndex() != 7 || receipt.GetType() != TxTypeDynamicFee || receipt.GetStatus() != 1 {
+		t.Errorf("receipt fields mismatch: index %d, type %d, status %d", receipt.GetTransactionIndex(), receipt.GetType(), receipt.GetStatus())
+	}
+	if price := receipt.GetEffectiveGasPrice(); price == nil || price.String() != "21337000000" {
+		t.Errorf("effective gas price mismatch: have %v", price)
+	}
+	if err := VerifyReceiptBloom(receipt); err != nil {
+		t.Error(err)
+	}
+	logs := receipt.GetLogs()
+	if logs.Size() != 1 {
+		t.Fatalf("log count mismatch: have %d, want 1", logs.Size())
+	}
+	log, _ := logs.Get(0)
+	if log.GetAddress().GetHex() != "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48" || log.GetIndex() != 42 || log.GetTopics().Size() != 3 {
+		t.Errorf("log mismatch: %s index %d", log.GetAddress().GetHex(), log.GetIndex())
+	}
+	// The client path must decode exactly like NewReceiptFromJSON
+	parsed, err := NewReceiptFromJSON(testRPCTransferReceipt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	have, _ := receipt.EncodeJSON()
+	want, _ := parsed.EncodeJSON()
+	if have != want {
+		t.Errorf("client receipt differs from parsed one:\nhave %s\nwant %s", have, want)
+	}
+	if _, err := transactionReceipt(context.Background(), client, common.Hash{1}); err != ErrReceiptNotFound || !IsNotFound(err) {
+		t.Errorf("missing receipt error mismatch: %v", err)
+	}
+}
+
+func TestTransactionInBlock(t *testing.T) {
+	txs := NewTransactions()
+	for _, fixture := range testTxFixtures {
+		tx, err := NewTransactionFromJSON(fixture.json)
+		if err != nil {
+			t.Fatal(err)
+		}
+		txs.Append(tx)
+	}
+	header := &Header{&types.Header{Number: big.NewInt(100), Difficulty: big.NewInt(1), GasLimit: 30000000}}
+	block, err := NewBlock(header, txs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		ctx     = context.Background()
+		hash    = block.block.Hash()
+		service = &testEthService{blocks: map[common.Hash]*Block{hash: block}}
+		client  = newTestRPCClient(t, service)
+	)
+	full, err := blockByHash(ctx, client, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count, err := transactionCount(ctx, client.Client(), hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int(count) != full.GetTransactions().Size() || count != uint(len(testTxFixtures)) {
+		t.Fatalf("transaction count mismatch: have %d, want %d", count, full.GetTransactions().Size())
+	}
+	for i := 0; i < int(count); i++ {
+		tx, err := transactionInBlock(ctx, client, client.Client(), hash, i)
+		if err != nil {
+			t.Fatalf("transaction %d: %v", i, err)
+		}
+		want, _ := full.GetTransactions().Get(i)
+		if !tx.GetHash().Equals(want.GetHash()) || tx.GetType() != testTxFixtures[i].txType {
+			t.Errorf("transaction %d mismatch: have %s of type %d, want %s", i, tx.GetHash().GetHex(), tx.GetType(), want.GetHash().GetHex())
+		}
+	}
+	for _, index := range []int{int(count), -1} {
+		if _, err := transactionInBlock(ctx, client, client.Client(), hash, index); err == nil || !strings.Contains(err.Error(), "index") {
+			t.Errorf("index %d error mismatch: %v", index, err)
+		}
+	}
+	if _, err := transactionInBlock(ctx, client, client.Client(), common.Hash{1}, 0); !IsNotFound(err) {
+		t.Errorf("unknown block error mismatch: %v", err)
+	}
+	if _, err := transactionCount(ctx, client.Client(), common.Hash{1}); !IsNotFound(err) {
+		t.Errorf("unknown block count error mismatch: %v", err)
+	}
+}
@@ -19,13 +19,26 @@
 package web3go
 
 import (
+	"bytes"
+	"container/heap"
 	"encoding/json"
 	"errors"
 	"fmt"
-
+	"math"
+	"math/big"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	ethereum "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
 	whisper "github.com/ethereum/go-ethereum/whisper/whisperv6"
 )
 
@@ -45,11 +58,31 @@ func (n *Nonce) GetHex() string {
 	return fmt.Sprintf("0x%x", n.nonce[:])
 }
 
+// GetUint64String retrieves the decimal string representation of the block
+// nonce, which may exceed the int64 range.
+func (n *Nonce) GetUint64String() string {
+	return strconv.FormatUint(n.nonce.Uint64(), 10)
+}
+
 // Bloom represents a 256 bit bloom filter.
 type Bloom struct {
 	bloom types.Bloom
 }
 
+// NewBloomFromBytes creates a bloom filter from its 256 byte representation.
+func NewBloomFromBytes(data []byte) (*Bloom, error) {
+	if len(data) != types.BloomByteLength {
+		return nil, fmt.Errorf("invalid bloom length: have %d, want %d", len(data), types.BloomByteLength)
+	}
+	return &Bloom{types.BytesToBloom(data)}, nil
+}
+
+// CreateBloomFromLogs computes the bloom filter of a set of logs, adding the
+// address and all topics of every log.
+func CreateBloomFromLogs(logs *Logs) *Bloom {
+	return &Bloom{types.BytesToBloom(types.LogsBloom(logs.logs))}
+}
+
 // GetBytes retrieves the byte representation of the bloom filter.
 func (b *Bloom) GetBytes() []byte {
 	return b.bloom[:]
@@ -60,13 +93,51 @@ func (b *Bloom) GetHex() string {
 	return fmt.Sprintf("0x%x", b.bloom[:])
 }
 
+// Test checks whether data, typically a log address or topic, may have been
+// added to the bloom filter, using the bloom9 probing of the logs bloom. A false
+// result means the data is definitely absent, while a true result only means it
+// may be present: false positives are possible and must be confirmed against the
+// actual logs.
+func (b *Bloom) Test(data []byte) bool {
+	return b.bloom.Test(data)
+}
+
+// TestAddress checks whether logs emitted by the given contract may be present.
+// See Test for the meaning of the result.
+func (b *Bloom) TestAddress(addr *Address) bool {
+	return b.bloom.Test(addr.address[:])
+}
+
+// TestTopic checks whether logs with the given topic, at any position, may be
+// present. See Test for the meaning of the result.
+func (b *Bloom) TestTopic(topic *Hash) bool {
+	return b.bloom.Test(topic.hash[:])
+}
+
+// Or returns the union of two bloom filters, e.g. to aggregate the blooms of the
+// receipts of a block into the block bloom. Neither input is modified.
+func (b *Bloom) Or(other *Bloom) *Bloom {
+	res := &Bloom{b.bloom}
+	for i := range res.bloom {
+		res.bloom[i] |= other.bloom[i]
+	}
+	return res
+}
+
 // Header represents a block header in the Ethereum blockchain.
 type Header struct {
 	header *types.Header
 }
 
+// NewEmptyHeader creates a header with all fields zero, to be filled in with the
+// setters.
+func NewEmptyHeader() *Header {
+	return &Header{&types.Header{Number: new(big.Int), Difficulty: new(big.Int)}}
+}
+
 // NewHeaderFromRLP parses a header from an RLP data dump.
-func NewHeaderFromRLP(data []byte) (*Header, error) {
+func NewHeaderFromRLP(data []byte) (_ *Header, err error) {
+	defer recoverError(&err)
 	h := &Header{
 		header: new(types.Header),
 	}
@@ -77,12 +148,14 @@ func NewHeaderFromRLP(data []byte) (*Header, error) {
 }
 
 // EncodeRLP encodes a header into an RLP data dump.
-func (h *Header) EncodeRLP() ([]byte, error) {
+func (h *Header) EncodeRLP() (_ []byte, err error) {
+	defer recoverError(&err)
 	return rlp.EncodeToBytes(h.header)
 }
 
 // NewHeaderFromJSON parses a header from a JSON data dump.
-func NewHeaderFromJSON(data string) (*Header, error) {
+func NewHeaderFromJSON(data string) (_ *Header, err error) {
+	defer recoverError(&err)
 	h := &Header{
 		header: new(types.Header),
 	}
@@ -93,7 +166,8 @@ func NewHeaderFromJSON(data string) (*Header, error) {
 }
 
 // EncodeJSON encodes a header into a JSON data dump.
-func (h *Header) EncodeJSON() (string, error) {
+func (h *Header) EncodeJSON() (_ string, err error) {
+	defer recoverError(&err)
 	data, err := json.Marshal(h.header)
 	return string(data), err
 }
@@ -119,11 +193,37 @@ func (h *Header) GetReceiptHash() *Hash { return &Hash{h.header.ReceiptHash} }
 // GetBloom ...
 func (h *Header) GetBloom() *Bloom { return &Bloom{h.header.Bloom} }
 
-// GetDifficulty ...
-func (h *Header) GetDifficulty() *BigInt { return &BigInt{h.header.Difficulty} }
+// GetDifficulty returns the difficulty of the header, or nil if it is missing.
+func (h *Header) GetDifficulty() *BigInt {
+	if h.header.Difficulty == nil {
+		return nil
+	}
+	return &BigInt{copyBig(h.header.Difficulty)}
+}
 
-// GetNumber ...
-func (h *Header) GetNumber() int64 { return h.header.Number.Int64() }
+// GetNumber returns the block number of the header. It fails if the number is
+// missing, as in pending headers served by some nodes, or does not fit into an
+// int64.
+func (h *Header) GetNumber() (int64, error) {
+	return blockNumberInt64(h.header.Number)
+}
+
+// GetNumberBig returns the block number of the header, or nil if it is missing.
+func (h *Header) GetNumberBig() *BigInt {
+	if h.header.Number == nil {
+		return nil
+	}
+	return &BigInt{new(big.Int).Set(h.header.Number)}
+}
+
+// GetBaseFee returns the EIP-1559 base fee of the block, or nil for headers
+// predating the London fork.
+func (h *Header) GetBaseFee() *BigInt {
+	if h.header.BaseFee == nil {
+		return nil
+	}
+	return &BigInt{copyBig(h.header.BaseFee)}
+}
 
 // GetGasLimit ...
 func (h *Header) GetGasLimit() int64 { return int64(h.header.GasLimit) }
@@ -134,6 +234,21 @@ func (h *Header) GetGasUsed() int64 { return int64(h.header.GasUsed) }
 // GetTime ...
 func (h *Header) GetTime() int64 { return int64(h.header.Time) }
 
+// GetGasLimitChecked returns the gas limit, failing if it overflows int64.
+func (h *Header) GetGasLimitChecked() (int64, error) {
+	return uint64ToInt64(h.header.GasLimit, "gas limit")
+}
+
+// GetGasUsedChecked returns the gas used, failing if it overflows int64.
+func (h *Header) GetGasUsedChecked() (int64, error) {
+	return uint64ToInt64(h.header.GasUsed, "gas used")
+}
+
+// GetTimeChecked returns the timestamp, failing if it overflows int64.
+func (h *Header) GetTimeChecked() (int64, error) {
+	return uint64ToInt64(h.header.Time, "timestamp")
+}
+
 // GetExtra ...
 func (h *Header) GetExtra() []byte { return h.header.Extra }
 
@@ -146,6 +261,98 @@ func (h *Header) GetNonce() *Nonce { return &Nonce{h.header.Nonce} }
 // GetHash ...
 func (h *Header) GetHash() *Hash { return &Hash{h.header.Hash()} }
 
+// Copy returns a deep copy of the header, which can be modified independently.
+func (h *Header) Copy() *Header { return &Header{types.CopyHeader(h.header)} }
+
+// SetParentHash sets the parent hash of the header. A nil hash is ignored.
+func (h *Header) SetParentHash(hash *Hash) {
+	if hash != nil {
+		h.header.ParentHash = hash.hash
+	}
+}
+
+// SetCoinbase sets the coinbase of the header. A nil address is ignored.
+func (h *Header) SetCoinbase(address *Address) {
+	if address != nil {
+		h.header.Coinbase = address.address
+	}
+}
+
+// SetRoot sets the state root of the header. A nil root is ignored.
+func (h *Header) SetRoot(root *Hash) {
+	if root != nil {
+		h.header.Root = root.hash
+	}
+}
+
+// SetNumber ...
+func (h *Header) SetNumber(number int64) { h.header.Number = big.NewInt(number) }
+
+// SetGasLimit ...
+func (h *Header) SetGasLimit(gasLimit int64) { h.header.GasLimit = uint64(gasLimit) }
+
+// SetGasUsed ...
+func (h *Header) SetGasUsed(gasUsed int64) { h.header.GasUsed = uint64(gasUsed) }
+
+// SetTime ...
+func (h *Header) SetTime(time int64) { h.header.Time = uint64(time) }
+
+// SetExtra ...
+func (h *Header) SetExtra(extra []byte) { h.header.Extra = common.CopyBytes(extra) }
+
+// SetDifficulty sets the difficulty of the header. A nil difficulty clears it.
+func (h *Header) SetDifficulty(difficulty *BigInt) {
+	if difficulty == nil {
+		h.header.Difficulty = nil
+		return
+	}
+	h.header.Difficulty = copyBig(difficulty.bigint)
+}
+
+// SetBaseFee sets the EIP-1559 base fee of the block. A nil fee turns the header
+// into a pre-London one.
+func (h *Header) SetBaseFee(baseFee *BigInt) {
+	if baseFee == nil {
+		h.header.BaseFee = nil
+		return
+	}
+	h.header.BaseFee = copyBig(baseFee.bigint)
+}
+
+// SetMixDigest sets the mix digest of the header. A nil digest is ignored.
+func (h *Header) SetMixDigest(digest *Hash) {
+	if digest != nil {
+		h.header.MixDigest = digest.hash
+	}
+}
+
+// SetNonce sets the nonce of the header. A nil nonce is ignored.
+func (h *Header) SetNonce(nonce *Nonce) {
+	if nonce != nil {
+		h.header.Nonce = nonce.nonce
+	}
+}
+
+// blockNumberInt64 converts a block number for platforms lacking big integers.
+func blockNumberInt64(number *big.Int) (int64, error) {
+	if number == nil {
+		return 0, errors.New("block number missing")
+	}
+	if !number.IsInt64() {
+		return 0, fmt.Errorf("block number %v overflows int64", number)
+	}
+	return number.Int64(), nil
+}
+
+// uint64ToInt64 converts an unsigned field to int64, failing instead of wrapping
+// negative if the value is above math.MaxInt64.
+func uint64ToInt64(value uint64, field string) (int64, error) {
+	if value > math.MaxInt64 {
+		return 0, fmt.Errorf("%s %d overflows int64", field, value)
+	}
+	return int64(value), nil
+}
+
 // Headers represents a slice of headers.
 type Headers struct{ headers []*types.Header }
 
@@ -159,16 +366,198 @@ func (h *Headers) Get(index int) (header *Header, _ error) {
 	if index < 0 || index >= len(h.headers) {
 		return nil, errors.New("index out of bounds")
 	}
-	return &Header{h.headers[index]}, nil
+	return &Header{types.CopyHeader(h.headers[index])}, nil
+}
+
+// GetFirst returns the first header of the slice.
+func (h *Headers) GetFirst() (*Header, error) { return h.Get(0) }
+
+// GetLast returns the last header of the slice.
+func (h *Headers) GetLast() (*Header, error) { return h.Get(len(h.headers) - 1) }
+
+// NewHeaders creates an empty slice of headers.
+func NewHeaders() *Headers {
+	return &Headers{headers: make([]*types.Header, 0)}
+}
+
+// copyHeaders returns a deep copy of the headers, so the headers of a block can
+// be handed out without the setters of Header modifying the block.
+func copyHeaders(headers []*types.Header) []*types.Header {
+	cpy := make([]*types.Header, len(headers))
+	for i, header := range headers {
+		cpy[i] = types.CopyHeader(header)
+	}
+	return cpy
+}
+
+// Append adds a copy of the header to the end of the slice, so later changes
+// through the setters of header do not modify the slice.
+func (h *Headers) Append(header *Header) error {
+	if header == nil || header.header == nil {
+		return errors.New("nil header")
+	}
+	h.headers = append(h.headers, types.CopyHeader(header.header))
+	return nil
+}
+
+// NewHeadersFromRLP parses a slice of headers from an RLP list. The index of the
+// first malformed header is reported on failure.
+func NewHeadersFromRLP(data []byte) (_ *Headers, err error) {
+	defer recoverError(&err)
+	var raws []rlp.RawValue
+	if err := rlp.DecodeBytes(data, &raws); err != nil {
+		return nil, err
+	}
+	h := &Headers{headers: make([]*types.Header, len(raws))}
+	for i, raw := range raws {
+		h.headers[i] = new(types.Header)
+		if err := rlp.DecodeBytes(common.CopyBytes(raw), h.headers[i]); err != nil {
+			return nil, fmt.Errorf("header %d: %v", i, err)
+		}
+	}
+	return h, nil
+}
+
+// EncodeRLP encodes a slice of headers into an RLP list.
+func (h *Headers) EncodeRLP() (_ []byte, err error) {
+	defer recoverError(&err)
+	if h.headers == nil {
+		return rlp.EncodeToBytes([]*types.Header{})
+	}
+	return rlp.EncodeToBytes(h.headers)
+}
+
+// NewHeadersFromJSON parses a slice of headers from a JSON array. The index of
+// the first malformed header is reported on failure.
+func NewHeadersFromJSON(data string) (_ *Headers, err error) {
+	defer recoverError(&err)
+	var raws []json.RawMessage
+	if err := json.Unmarshal([]byte(data), &raws); err != nil {
+		return nil, err
+	}
+	h := &Headers{headers: make([]*types.Header, len(raws))}
+	for i, raw := range raws {
+		h.headers[i] = new(types.Header)
+		if err := json.Unmarshal(raw, h.headers[i]); err != nil {
+			return nil, fmt.Errorf("header %d: %v", i, err)
+		}
+	}
+	return h, nil
+}
+
+// EncodeJSON encodes a slice of headers into a JSON array.
+func (h *Headers) EncodeJSON() (_ string, err error) {
+	defer recoverError(&err)
+	if h.headers == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(h.headers)
+	return string(data), err
 }
 
 // Block represents an entire block in the Ethereum blockchain.
 type Block struct {
 	block *types.Block
+
+	sizeOnce   sync.Once
+	headerSize int64 // Cached length of the header encoding
+	bodySize   int64 // Cached length of the body encoding
+
+	indexOnce sync.Once
+	txIndex   map[common.Hash]int // Positions of the transactions by hash
+
+	// Fields of RPC block objects not represented by the block itself
+	txHashes        []common.Hash // Transaction hashes if the bodies were omitted
+	uncleHashes     []common.Hash // Uncle hashes, the uncle headers are never served
+	totalDifficulty *big.Int      // Total difficulty up to the block, if reported
+	reportedSize    uint64        // Size of the full block if the bodies were omitted
+}
+
+// NewBlock assembles a block from the given header and body. The transaction and
+// uncle roots of the header are recomputed from the body, nil slices are treated
+// as empty.
+func NewBlock(header *Header, txs *Transactions, uncles *Headers) (_ *Block, err error) {
+	defer recoverError(&err)
+	if header == nil || header.header == nil {
+		return nil, errors.New("nil header")
+	}
+	var (
+		rawTxs    types.Transactions
+		rawUncles []*types.Header
+	)
+	if txs != nil {
+		rawTxs = txs.txs
+	}
+	if uncles != nil {
+		rawUncles = uncles.headers
+	}
+	h := types.CopyHeader(header.header)
+	h.TxHash = types.DeriveSha(rawTxs, trie.NewStackTrie(nil))
+	h.UncleHash = types.CalcUncleHash(rawUncles)
+
+	return &Block{block: types.NewBlockWithHeader(h).WithBody(rawTxs, rawUncles)}, nil
+}
+
+// NewBlockWithHeader creates a block with the given header and an empty body. The
+// header is copied, so later changes to it do not affect the block.
+func NewBlockWithHeader(header *Header) *Block {
+	return &Block{block: types.NewBlockWithHeader(header.header)}
+}
+
+// WithBody returns a copy of the block with the given transactions and uncles.
+// Like its go-ethereum counterpart it keeps the header as is, use VerifyBody to
+// check the body against the header roots, or NewBlock to recompute them.
+func (b *Block) WithBody(txs *Transactions, uncles *Headers) *Block {
+	var (
+		rawTxs    types.Transactions
+		rawUncles []*types.Header
+	)
+	if txs != nil {
+		rawTxs = txs.txs
+	}
+	if uncles != nil {
+		rawUncles = uncles.headers
+	}
+	return &Block{block: b.block.WithBody(rawTxs, rawUncles)}
+}
+
+// VerifyBody checks that the transactions and uncles of the block match the
+// roots in its header.
+func (b *Block) VerifyBody() (err error) {
+	defer recoverError(&err)
+	if err := b.VerifyTransactionsRoot(); err != nil {
+		return err
+	}
+	if hash := types.CalcUncleHash(b.block.Uncles()); hash != b.block.UncleHash() {
+		return fmt.Errorf("uncle root mismatch: have %x, want %x", hash, b.block.UncleHash())
+	}
+	return nil
+}
+
+// VerifyTransactionsRoot checks that the transactions of the block match the
+// transaction root in its header. Blocks holding only transaction hashes cannot
+// be verified and fail.
+func (b *Block) VerifyTransactionsRoot() (err error) {
+	defer recoverError(&err)
+	if len(b.txHashes) > 0 {
+		return errors.New("transaction bodies not available")
+	}
+	if hash := types.DeriveSha(b.block.Transactions(), trie.NewStackTrie(nil)); hash != b.block.TxHash() {
+		return fmt.Errorf("transaction root mismatch: have %x, want %x", hash, b.block.TxHash())
+	}
+	return nil
+}
+
+// ComputeReceiptsRoot returns the receipt trie root of the receipts, to be
+// checked against the receipt hash of their block header.
+func ComputeReceiptsRoot(receipts *Receipts) (_ *Hash, err error) {
+	defer recoverError(&err)
+	return &Hash{types.DeriveSha(receipts.receipts, trie.NewStackTrie(nil))}, nil
 }
 
 // NewBlockFromRLP parses a block from an RLP data dump.
-func NewBlockFromRLP(data []byte) (*Block, error) {
+func NewBlockFromRLP(data []byte) (_ *Block, err error) {
+	defer recoverError(&err)
 	b := &Block{
 		block: new(types.Block),
 	}
@@ -179,12 +568,14 @@ func NewBlockFromRLP(data []byte) (*Block, error) {
 }
 
 // EncodeRLP encodes a block into an RLP data dump.
-func (b *Block) EncodeRLP() ([]byte, error) {
+func (b *Block) EncodeRLP() (_ []byte, err error) {
+	defer recoverError(&err)
 	return rlp.EncodeToBytes(b.block)
 }
 
 // NewBlockFromJSON parses a block from a JSON data dump.
-func NewBlockFromJSON(data string) (*Block, error) {
+func NewBlockFromJSON(data string) (_ *Block, err error) {
+	defer recoverError(&err)
 	b := &Block{
 		block: new(types.Block),
 	}
@@ -195,11 +586,164 @@ func NewBlockFromJSON(data string) (*Block, error) {
 }
 
 // EncodeJSON encodes a block into a JSON data dump.
-func (b *Block) EncodeJSON() (string, error) {
+func (b *Block) EncodeJSON() (_ string, err error) {
+	defer recoverError(&err)
 	data, err := json.Marshal(b.block)
 	return string(data), err
 }
 
+// rpcBlock is the part of an eth_getBlockByNumber response not covered by the
+// JSON encoding of the header.
+type rpcBlock struct {
+	Hash            *common.Hash      `json:"hash"`
+	Transactions    []json.RawMessage `json:"transactions"`
+	Uncles          []common.Hash     `json:"uncles"`
+	Withdrawals     types.Withdrawals `json:"withdrawals"`
+	TotalDifficulty *hexutil.Big      `json:"totalDifficulty"`
+	Size            *hexutil.Uint64   `json:"size"`
+}
+
+// NewBlockFromRPCJSON parses a block object in the format returned by
+// eth_getBlockByNumber. If withFullTxs is set the transactions are expected as
+// full objects, otherwise as hashes, which are then only available through
+// GetTransactionHashes.
+//
+// Uncles are only served as hashes and available through GetUncleHashes, the
+// total difficulty through GetTotalDifficulty.
+//
+// Blocks decoded from hashes have no transaction bodies: GetTransactionCount
+// reports 0 and VerifyBody fails with "transaction bodies not available".
+func NewBlockFromRPCJSON(data string, withFullTxs bool) (_ *Block, err error) {
+	defer recoverError(&err)
+	header := new(types.Header)
+	if err := json.Unmarshal([]byte(data), header); err != nil {
+		return nil, err
+	}
+	var rpc rpcBlock
+	if err := json.Unmarshal([]byte(data), &rpc); err != nil {
+		return nil, err
+	}
+	if rpc.Hash != nil && *rpc.Hash != header.Hash() {
+		return nil, fmt.Errorf("block hash mismatch: have %x, want %x", header.Hash(), *rpc.Hash)
+	}
+	var (
+		txs      types.Transactions
+		txHashes []common.Hash
+	)
+	for i, raw := range rpc.Transactions {
+		if withFullTxs {
+			tx, err := NewTransactionFromRPCJSON(string(raw))
+			if err != nil {
+				return nil, fmt.Errorf("transaction %d: %v", i, err)
+			}
+			txs = append(txs, tx.tx)
+		} else {
+			var hash common.Hash
+			if err := json.Unmarshal(raw, &hash); err != nil {
+				return nil, fmt.Errorf("transaction %d: %v", i, err)
+			}
+			txHashes = append(txHashes, hash)
+		}
+	}
+	block := types.NewBlockWithHeader(header).WithBody(txs, nil)
+	if rpc.Withdrawals != nil {
+		block = block.WithWithdrawals(rpc.Withdrawals)
+	}
+	b := &Block{block: block, txHashes: txHashes, uncleHashes: rpc.Uncles}
+	if rpc.TotalDifficulty != nil {
+		b.totalDifficulty = rpc.TotalDifficulty.ToInt()
+	}
+	if rpc.Size != nil && txHashes != nil {
+		b.reportedSize = uint64(*rpc.Size)
+	}
+	return b, nil
+}
+
+// EncodeRPCJSON encodes a block into the format returned by eth_getBlockByNumber,
+// with the transactions as full objects if fullTxs is set or as hashes otherwise.
+// Transaction senders are recovered with the signing scheme detected from each
+// signature.
+func (b *Block) EncodeRPCJSON(fullTxs bool) (_ string, err error) {
+	defer recoverError(&err)
+	data, err := json.Marshal(b.block.Header())
+	if err != nil {
+		return "", err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return "", err
+	}
+	fields["size"] = hexutil.Uint64(b.GetSize())
+
+	txs := make([]interface{}, 0, len(b.block.Transactions()))
+	switch {
+	case fullTxs && len(b.txHashes) > 0:
+		return "", errors.New("transaction bodies not available")
+	case fullTxs:
+		for i, tx := range b.block.Transactions() {
+			wrapped := &Transaction{tx: tx}
+			data, err := wrapped.EncodeRPCJSON(wrapped.DeriveChainID(), b.GetHash(), b.block.Number().Int64(), i)
+			if err != nil {
+				return "", fmt.Errorf("transaction %d: %v", i, err)
+			}
+			txs = append(txs, json.RawMessage(data))
+		}
+	case len(b.txHashes) > 0:
+		for _, hash := range b.txHashes {
+			txs = append(txs, hash)
+		}
+	default:
+		for _, tx := range b.block.Transactions() {
+			txs = append(txs, tx.Hash())
+		}
+	}
+	fields["transactions"] = txs
+	fields["uncles"] = b.GetUncleHashes().hashes
+	if withdrawals := b.block.Withdrawals(); withdrawals != nil {
+		fields["withdrawals"] = withdrawals
+	}
+	if b.totalDifficulty != nil {
+		fields["totalDifficulty"] = (*hexutil.Big)(b.totalDifficulty)
+	}
+	data, err = json.Marshal(fields)
+	return string(data), err
+}
+
+// GetTransactionHashes returns the hashes of the transactions in the block, also
+// for blocks decoded from RPC responses without transaction bodies.
+func (b *Block) GetTransactionHashes() *Hashes {
+	if b.txHashes != nil {
+		return &Hashes{append([]common.Hash{}, b.txHashes...)}
+	}
+	hashes := make([]common.Hash, 0, len(b.block.Transactions()))
+	for _, tx := range b.block.Transactions() {
+		hashes = append(hashes, tx.Hash())
+	}
+	return &Hashes{hashes}
+}
+
+// GetUncleHashes returns the hashes of the uncles of the block, also for blocks
+// decoded from RPC responses, which carry no uncle headers.
+func (b *Block) GetUncleHashes() *Hashes {
+	if b.uncleHashes != nil {
+		return &Hashes{append([]common.Hash{}, b.uncleHashes...)}
+	}
+	hashes := make([]common.Hash, 0, len(b.block.Uncles()))
+	for _, uncle := range b.block.Uncles() {
+		hashes = append(hashes, uncle.Hash())
+	}
+	return &Hashes{hashes}
+}
+
+// GetTotalDifficulty returns the total difficulty of the chain up to the block
+// as reported by an RPC response, or nil if unknown.
+func (b *Block) GetTotalDifficulty() *BigInt {
+	if b.totalDifficulty == nil {
+		return nil
+	}
+	return &BigInt{copyBig(b.totalDifficulty)}
+}
+
 // GetParentHash ...
 func (b *Block) GetParentHash() *Hash { return &Hash{b.block.ParentHash()} }
 
@@ -224,8 +768,31 @@ func (b *Block) GetBloom() *Bloom { return &Bloom{b.block.Bloom()} }
 // GetDifficulty ...
 func (b *Block) GetDifficulty() *BigInt { return &BigInt{b.block.Difficulty()} }
 
-// GetNumber ...
-func (b *Block) GetNumber() int64 { return b.block.Number().Int64() }
+// GetNumber returns the number of the block. It fails if the number does not
+// fit into an int64.
+func (b *Block) GetNumber() (_ int64, err error) {
+	defer recoverError(&err)
+	return blockNumberInt64(b.block.Number())
+}
+
+// GetNumberBig returns the number of the block, or nil if it is missing, as in
+// pending blocks served by some nodes.
+func (b *Block) GetNumberBig() *BigInt {
+	defer recoverZero()
+	if b.block.Header().Number == nil {
+		return nil
+	}
+	return &BigInt{b.block.Number()}
+}
+
+// GetBaseFee returns the EIP-1559 base fee of the block, or nil for blocks
+// predating the London fork.
+func (b *Block) GetBaseFee() *BigInt {
+	if fee := b.block.BaseFee(); fee != nil {
+		return &BigInt{fee}
+	}
+	return nil
+}
 
 // GetGasLimit ...
 func (b *Block) GetGasLimit() int64 { return int64(b.block.GasLimit()) }
@@ -236,6 +803,21 @@ func (b *Block) GetGasUsed() int64 { return int64(b.block.GasUsed()) }
 // GetTime ...
 func (b *Block) GetTime() int64 { return int64(b.block.Time()) }
 
+// GetGasLimitChecked returns the gas limit, failing if it overflows int64.
+func (b *Block) GetGasLimitChecked() (int64, error) {
+	return uint64ToInt64(b.block.GasLimit(), "gas limit")
+}
+
+// GetGasUsedChecked returns the gas used, failing if it overflows int64.
+func (b *Block) GetGasUsedChecked() (int64, error) {
+	return uint64ToInt64(b.block.GasUsed(), "gas used")
+}
+
+// GetTimeChecked returns the timestamp, failing if it overflows int64.
+func (b *Block) GetTimeChecked() (int64, error) {
+	return uint64ToInt64(b.block.Time(), "timestamp")
+}
+
 // GetExtra ...
 func (b *Block) GetExtra() []byte { return b.block.Extra() }
 
@@ -243,37 +825,350 @@ func (b *Block) GetExtra() []byte { return b.block.Extra() }
 func (b *Block) GetMixDigest() *Hash { return &Hash{b.block.MixDigest()} }
 
 // GetNonce ...
+//
+// Deprecated: nonces above 2^63 come back negative, use GetNonceObj.
 func (b *Block) GetNonce() int64 { return int64(b.block.Nonce()) }
 
+// GetNonceObj returns the proof-of-work nonce of the block.
+func (b *Block) GetNonceObj() *Nonce { return &Nonce{types.EncodeNonce(b.block.Nonce())} }
+
 // GetHash ...
 func (b *Block) GetHash() *Hash { return &Hash{b.block.Hash()} }
 
+// GetSize returns the length of the RLP encoding of the block. Blocks decoded
+// from RPC responses without transaction bodies report the served size.
+func (b *Block) GetSize() int64 {
+	if b.reportedSize != 0 {
+		return int64(b.reportedSize)
+	}
+	return int64(b.block.Size())
+}
+
+// GetSerializedSize returns the length of the RLP encoding of the block, see
+// GetSize.
+func (b *Block) GetSerializedSize() int64 { return b.GetSize() }
+
+// GetHeaderSize returns the length of the RLP encoding of the block header.
+func (b *Block) GetHeaderSize() int64 {
+	b.cacheSizes()
+	return b.headerSize
+}
+
+// GetBodySize returns the length of the RLP encoding of the block body, i.e. the
+// transactions and uncles as served by eth/GetBlockBodies. The header and body
+// sizes do not add up to the block size exactly, since the block wraps its parts
+// in a single list.
+func (b *Block) GetBodySize() int64 {
+	b.cacheSizes()
+	return b.bodySize
+}
+
+// cacheSizes computes the encoded header and body sizes once.
+func (b *Block) cacheSizes() {
+	b.sizeOnce.Do(func() {
+		if header, err := rlp.EncodeToBytes(b.block.Header()); err == nil {
+			b.headerSize = int64(len(header))
+		}
+		if body, err := rlp.EncodeToBytes(b.block.Body()); err == nil {
+			b.bodySize = int64(len(body))
+		}
+	})
+}
+
 // GetHeader ...
 func (b *Block) GetHeader() *Header { return &Header{b.block.Header()} }
 
-// GetUncles ...
-func (b *Block) GetUncles() *Headers { return &Headers{b.block.Uncles()} }
+// GetUncles returns a copy of the uncle headers of the block.
+func (b *Block) GetUncles() *Headers { return &Headers{copyHeaders(b.block.Uncles())} }
+
+// GetTransactions returns a copy of the transaction list of the block, which can
+// be modified without affecting the block.
+func (b *Block) GetTransactions() *Transactions {
+	return &Transactions{append(types.Transactions(nil), b.block.Transactions()...)}
+}
+
+// GetTransaction returns the transaction with the given hash from the block.
+func (b *Block) GetTransaction(hash *Hash) (_ *Transaction, err error) {
+	defer recoverError(&err)
+	index, err := b.GetTransactionIndex(hash)
+	if err != nil {
+		return nil, err
+	}
+	return &Transaction{tx: b.block.Transactions()[index]}, nil
+}
+
+// GetTransactionCount returns the number of transactions in the block.
+func (b *Block) GetTransactionCount() int { return len(b.block.Transactions()) }
+
+// GetTransactionByIndex returns the transaction at the given index in the block.
+func (b *Block) GetTransactionByIndex(index int) (_ *Transaction, err error) {
+	defer recoverError(&err)
+	txs := b.block.Transactions()
+	if index < 0 || index >= len(txs) {
+		return nil, errors.New("index out of bounds")
+	}
+	return &Transaction{tx: txs[index]}, nil
+}
+
+// GetTransactionIndex returns the position of the transaction with the given hash
+// in the block. The hash index is built on first use.
+func (b *Block) GetTransactionIndex(hash *Hash) (_ int, err error) {
+	defer recoverError(&err)
+	b.indexOnce.Do(func() {
+		txs := b.block.Transactions()
+		b.txIndex = make(map[common.Hash]int, len(txs))
+		for i, tx := range txs {
+			b.txIndex[tx.Hash()] = i
+		}
+	})
+	index, ok := b.txIndex[hash.hash]
+	if !ok {
+		return -1, fmt.Errorf("transaction %x not found in block", hash.hash)
+	}
+	return index, nil
+}
+
+// GetBurntFees returns the fees burnt by the block, i.e. the base fee times the
+// gas used by all transactions. Blocks predating the London fork burn nothing.
+// The receipts must belong to the transactions of the block.
+func (b *Block) GetBurntFees(receipts *Receipts) (_ *BigInt, err error) {
+	defer recoverError(&err)
+	if err := b.checkReceipts(receipts); err != nil {
+		return nil, err
+	}
+	baseFee := b.block.BaseFee()
+	if baseFee == nil {
+		return &BigInt{new(big.Int)}, nil
+	}
+	var gasUsed uint64
+	for _, receipt := range receipts.receipts {
+		gasUsed += receipt.GasUsed
+	}
+	return &BigInt{new(big.Int).Mul(baseFee, new(big.Int).SetUint64(gasUsed))}, nil
+}
+
+// GetTotalFees returns the fees paid by all transactions of the block, i.e. the
+// sum of the effective gas price times the gas used. Receipts lacking the
+// effective gas price have it computed from the transaction and base fee. The
+// receipts must belong to the transactions of the block.
+func (b *Block) GetTotalFees(receipts *Receipts) (_ *BigInt, err error) {
+	defer recoverError(&err)
+	if err := b.checkReceipts(receipts); err != nil {
+		return nil, err
+	}
+	var baseFee *BigInt
+	if fee := b.block.BaseFee(); fee != nil {
+		baseFee = &BigInt{fee}
+	}
+	total := new(big.Int)
+	for i, tx := range b.block.Transactions() {
+		price := receipts.receipts[i].EffectiveGasPrice
+		if price == nil {
+			price = (&Transaction{tx: tx}).GetEffectiveGasPrice(baseFee).bigint
+		}
+		total.Add(total, new(big.Int).Mul(price, new(big.Int).SetUint64(receipts.receipts[i].GasUsed)))
+	}
+	return &BigInt{total}, nil
+}
+
+// checkReceipts verifies that the receipts belong to the transactions of the
+// block, in order.
+func (b *Block) checkReceipts(receipts *Receipts) error {
+	txs := b.block.Transactions()
+	if len(receipts.receipts) != len(txs) {
+		return fmt.Errorf("receipt count mismatch: have %d, want %d", len(receipts.receipts), len(txs))
+	}
+	for i, tx := range txs {
+		if receipts.receipts[i].TxHash != tx.Hash() {
+			return fmt.Errorf("receipt %d: transaction hash mismatch: have %x, want %x", i, receipts.receipts[i].TxHash, tx.Hash())
+		}
+	}
+	return nil
+}
+
+// GetBody returns the transactions and uncles of the block.
+func (b *Block) GetBody() *Body { return &Body{b.block.Body()} }
+
+// Body represents the transactions and uncles of a block, as exchanged by the
+// eth/GetBlockBodies protocol message.
+type Body struct {
+	body *types.Body
+}
+
+// NewBodyFromRLP parses a block body from an RLP data dump.
+func NewBodyFromRLP(data []byte) (_ *Body, err error) {
+	defer recoverError(&err)
+	if rlp.DecodeBytes(data, new(types.Block)) == nil {
+		return nil, errors.New("data is a full block, not a block body")
+	}
+	b := &Body{
+		body: new(types.Body),
+	}
+	if err := rlp.DecodeBytes(common.CopyBytes(data), b.body); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// EncodeRLP encodes a block body into an RLP data dump.
+func (b *Body) EncodeRLP() (_ []byte, err error) {
+	defer recoverError(&err)
+	return rlp.EncodeToBytes(b.body)
+}
+
+// GetTransactions returns a copy of the transaction list of the body, which can
+// be modified without affecting the body.
+func (b *Body) GetTransactions() *Transactions {
+	return &Transactions{append(types.Transactions(nil), b.body.Transactions...)}
+}
+
+// GetUncles returns a copy of the uncle headers of the body.
+func (b *Body) GetUncles() *Headers { return &Headers{copyHeaders(b.body.Uncles)} }
 
-// GetTransactions ...
-func (b *Block) GetTransactions() *Transactions { return &Transactions{b.block.Transactions()} }
+// Blocks represents a slice of blocks.
+type Blocks struct{ blocks []*types.Block }
 
-// GetTransaction ...
-func (b *Block) GetTransaction(hash *Hash) *Transaction {
-	return &Transaction{b.block.Transaction(hash.hash)}
+// NewBlocks creates an empty slice of blocks.
+func NewBlocks() *Blocks {
+	return &Blocks{blocks: make([]*types.Block, 0)}
 }
 
+// Size returns the number of blocks in the slice.
+func (b *Blocks) Size() int {
+	return len(b.blocks)
+}
+
+// Get returns the block at the given index from the slice.
+func (b *Blocks) Get(index int) (block *Block, _ error) {
+	if index < 0 || index >= len(b.blocks) {
+		return nil, errors.New("index out of bounds")
+	}
+	return &Block{block: b.blocks[index]}, nil
+}
+
+// Append adds a new block element to the end of the slice.
+func (b *Blocks) Append(block *Block) error {
+	if block == nil || block.block == nil {
+		return errors.New("nil block")
+	}
+	b.blocks = append(b.blocks, block.block)
+	return nil
+}
+
+// Hashes returns the hashes of all blocks in the slice.
+func (b *Blocks) Hashes() *Hashes {
+	hashes := make([]common.Hash, len(b.blocks))
+	for i, block := range b.blocks {
+		hashes[i] = block.Hash()
+	}
+	return &Hashes{hashes}
+}
+
+// NewBlocksFromRLP parses a slice of blocks from an RLP list. The blocks are
+// decoded one by one from the input, and the index of the first malformed block
+// is reported on failure.
+func NewBlocksFromRLP(data []byte) (_ *Blocks, err error) {
+	defer recoverError(&err)
+	stream := rlp.NewStream(bytes.NewReader(data), uint64(len(data)))
+	if _, err := stream.List(); err != nil {
+		return nil, err
+	}
+	blocks := NewBlocks()
+	for i := 0; ; i++ {
+		block := new(types.Block)
+		if err := stream.Decode(block); err == rlp.EOL {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("block %d: %v", i, err)
+		}
+		blocks.blocks = append(blocks.blocks, block)
+	}
+	if err := stream.ListEnd(); err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+// EncodeRLP encodes a slice of blocks into an RLP list.
+func (b *Blocks) EncodeRLP() (_ []byte, err error) {
+	defer recoverError(&err)
+	if b.blocks == nil {
+		return rlp.EncodeToBytes([]*types.Block{})
+	}
+	return rlp.EncodeToBytes(b.blocks)
+}
+
+// Transaction envelope types as reported by Transaction.GetType.
+const (
+	TxTypeLegacy     = int(types.LegacyTxType)
+	TxTypeAccessList = int(types.AccessListTxType)
+	TxTypeDynamicFee = int(types.DynamicFeeTxType)
+	TxTypeBlob       = int(types.BlobTxType)
+)
+
 // Transaction represents a single Ethereum transaction.
 type Transaction struct {
 	tx *types.Transaction
+
+	senderLock    sync.Mutex
+	sender        *common.Address // Sender recovered by the last GetFrom call
+	senderChainID *big.Int        // Chain ID the sender was recovered with
 }
 
 // NewTransaction creates a new transaction with the given properties.
 func NewTransaction(nonce int64, to *Address, amount *BigInt, gasLimit int64, gasPrice *BigInt, data []byte) *Transaction {
-	return &Transaction{types.NewTransaction(uint64(nonce), to.address, amount.bigint, uint64(gasLimit), gasPrice.bigint, common.CopyBytes(data))}
+	return &Transaction{tx: types.NewTransaction(uint64(nonce), to.address, amount.bigint, uint64(gasLimit), gasPrice.bigint, common.CopyBytes(data))}
+}
+
+// NewContractCreation creates a new transaction deploying the contract code in
+// data. The transaction has no recipient.
+func NewContractCreation(nonce int64, amount *BigInt, gasLimit int64, gasPrice *BigInt, data []byte) *Transaction {
+	return &Transaction{tx: types.NewContractCreation(uint64(nonce), amount.bigint, uint64(gasLimit), gasPrice.bigint, common.CopyBytes(data))}
+}
+
+// NewDynamicFeeContractCreation creates a new EIP-1559 transaction deploying the
+// contract code in data.
+func NewDynamicFeeContractCreation(chainID *BigInt, nonce int64, amount *BigInt, gasLimit int64, gasTipCap *BigInt, gasFeeCap *BigInt, data []byte) (*Transaction, error) {
+	return NewDynamicFeeTransaction(chainID, nonce, nil, amount, gasLimit, gasTipCap, gasFeeCap, data)
+}
+
+// NewDynamicFeeTransaction creates a new EIP-1559 transaction with the given
+// properties. The to address may be nil for contract creations and a nil amount
+// transfers nothing, but the chain ID and both fee caps are required.
+func NewDynamicFeeTransaction(chainID *BigInt, nonce int64, to *Address, amount *BigInt, gasLimit int64, gasTipCap *BigInt, gasFeeCap *BigInt, data []byte) (_ *Transaction, err error) {
+	defer recoverError(&err)
+	switch {
+	case chainID == nil:
+		return nil, errors.New("missing chain ID")
+	case gasTipCap == nil:
+		return nil, errors.New("missing gas tip cap")
+	case gasFeeCap == nil:
+		return nil, errors.New("missing gas fee cap")
+	}
+	var recipient *common.Address
+	if to != nil {
+		addr := to.address
+		recipient = &addr
+	}
+	value := new(big.Int)
+	if amount != nil {
+		value.Set(amount.bigint)
+	}
+	return &Transaction{tx: types.NewTx(&types.DynamicFeeTx{
+		ChainID:   new(big.Int).Set(chainID.bigint),
+		Nonce:     uint64(nonce),
+		GasTipCap: new(big.Int).Set(gasTipCap.bigint),
+		GasFeeCap: new(big.Int).Set(gasFeeCap.bigint),
+		Gas:       uint64(gasLimit),
+		To:        recipient,
+		Value:     value,
+		Data:      common.CopyBytes(data),
+	})}, nil
 }
 
 // NewTransactionFromRLP parses a transaction from an RLP data dump.
-func NewTransactionFromRLP(data []byte) (*Transaction, error) {
+func NewTransactionFromRLP(data []byte) (_ *Transaction, err error) {
+	defer recoverError(&err)
 	tx := &Transaction{
 		tx: new(types.Transaction),
 	}
@@ -284,12 +1179,56 @@ func NewTransactionFromRLP(data []byte) (*Transaction, error) {
 }
 
 // EncodeRLP encodes a transaction into an RLP data dump.
-func (tx *Transaction) EncodeRLP() ([]byte, error) {
+func (tx *Transaction) EncodeRLP() (_ []byte, err error) {
+	defer recoverError(&err)
 	return rlp.EncodeToBytes(tx.tx)
 }
 
+// NewTransactionFromBinary parses a transaction from its canonical EIP-2718
+// encoding, as used by eth_sendRawTransaction and eth_getRawTransactionByHash.
+func NewTransactionFromBinary(data []byte) (_ *Transaction, err error) {
+	defer recoverError(&err)
+	tx := &Transaction{
+		tx: new(types.Transaction),
+	}
+	if err := tx.tx.UnmarshalBinary(common.CopyBytes(data)); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// EncodeBinary encodes a transaction into its canonical EIP-2718 encoding. Legacy
+// transactions are plain RLP lists, typed transactions are prefixed by their type.
+func (tx *Transaction) EncodeBinary() (_ []byte, err error) {
+	defer recoverError(&err)
+	return tx.tx.MarshalBinary()
+}
+
+// NewTransactionFromRawHex parses a transaction from the hex string of its
+// canonical encoding. The 0x prefix is optional.
+func NewTransactionFromRawHex(raw string) (_ *Transaction, err error) {
+	defer recoverError(&err)
+	data, err := decodeHex(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid raw transaction hex: %v", err)
+	}
+	return NewTransactionFromBinary(data)
+}
+
+// EncodeRawHex encodes a transaction into the 0x prefixed hex string of its
+// canonical encoding.
+func (tx *Transaction) EncodeRawHex() (_ string, err error) {
+	defer recoverError(&err)
+	data, err := tx.tx.MarshalBinary()
+	if err != nil {
+		return "", err
+	}
+	return hexutil.Encode(data), nil
+}
+
 // NewTransactionFromJSON parses a transaction from a JSON data dump.
-func NewTransactionFromJSON(data string) (*Transaction, error) {
+func NewTransactionFromJSON(data string) (_ *Transaction, err error) {
+	defer recoverError(&err)
 	tx := &Transaction{
 		tx: new(types.Transaction),
 	}
@@ -300,45 +1239,261 @@ func NewTransactionFromJSON(data string) (*Transaction, error) {
 }
 
 // EncodeJSON encodes a transaction into a JSON data dump.
-func (tx *Transaction) EncodeJSON() (string, error) {
+func (tx *Transaction) EncodeJSON() (_ string, err error) {
+	defer recoverError(&err)
+	data, err := json.Marshal(tx.tx)
+	return string(data), err
+}
+
+// NewTransactionFromRPCJSON parses a transaction object in the format returned by
+// eth_getTransactionByHash. The sender and block context fields are ignored, the
+// hash field is checked against the decoded transaction if present.
+func NewTransactionFromRPCJSON(data string) (_ *Transaction, err error) {
+	defer recoverError(&err)
+	tx := &Transaction{
+		tx: new(types.Transaction),
+	}
+	if err := json.Unmarshal([]byte(data), tx.tx); err != nil {
+		return nil, err
+	}
+	var fields struct {
+		Hash *common.Hash `json:"hash"`
+	}
+	if err := json.Unmarshal([]byte(data), &fields); err != nil {
+		return nil, err
+	}
+	if fields.Hash != nil && *fields.Hash != tx.tx.Hash() {
+		return nil, fmt.Errorf("transaction hash mismatch: have %x, want %x", tx.tx.Hash(), *fields.Hash)
+	}
+	return tx, nil
+}
+
+// EncodeRPCJSON encodes a transaction into the format returned by
+// eth_getTransactionByHash, recovering the sender with the given chain ID. A nil
+// blockHash encodes a pending transaction with null block fields.
+//
+// Dynamic fee transactions report their fee cap as gasPrice, like nodes do for
+// pending transactions, since the base fee of the block is not known here.
+func (tx *Transaction) EncodeRPCJSON(chainID *BigInt, blockHash *Hash, blockNumber int64, index int) (_ string, err error) {
+	defer recoverError(&err)
+	from, err := tx.GetFrom(chainID)
+	if err != nil {
+		return "", err
+	}
 	data, err := json.Marshal(tx.tx)
+	if err != nil {
+		return "", err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return "", err
+	}
+	fields["from"] = from.address
+	fields["gasPrice"] = (*hexutil.Big)(tx.tx.GasPrice())
+	fields["blockHash"], fields["blockNumber"], fields["transactionIndex"] = nil, nil, nil
+	if blockHash != nil {
+		fields["blockHash"] = blockHash.hash
+		fields["blockNumber"] = hexutil.Uint64(blockNumber)
+		fields["transactionIndex"] = hexutil.Uint64(index)
+	}
+	data, err = json.Marshal(fields)
 	return string(data), err
 }
 
+// GetType returns the EIP-2718 envelope type of the transaction, see the TxType
+// constants.
+func (tx *Transaction) GetType() int { return int(tx.tx.Type()) }
+
+// GetChainID returns the chain ID the transaction is signed for. Typed
+// transactions report their chainId field, legacy ones the chain ID derived from
+// the EIP-155 signature. Unprotected legacy transactions return nil.
+func (tx *Transaction) GetChainID() *BigInt {
+	if tx.tx.Type() == types.LegacyTxType && !tx.tx.Protected() {
+		return nil
+	}
+	return &BigInt{copyBig(tx.tx.ChainId())}
+}
+
+// DeriveChainID returns the chain ID the signature of the transaction commits to,
+// see GetChainID.
+func (tx *Transaction) DeriveChainID() *BigInt { return tx.GetChainID() }
+
+// SignerKind returns the signing scheme of the transaction: "homestead" for
+// unprotected legacy transactions, "eip155", "eip2930", "eip1559" or "eip4844".
+func (tx *Transaction) SignerKind() string {
+	switch tx.tx.Type() {
+	case types.LegacyTxType:
+		if tx.tx.Protected() {
+			return "eip155"
+		}
+		return "homestead"
+	case types.AccessListTxType:
+		return "eip2930"
+	case types.DynamicFeeTxType:
+		return "eip1559"
+	case types.BlobTxType:
+		return "eip4844"
+	default:
+		return "unknown"
+	}
+}
+
+// IsProtected reports whether the transaction is replay protected, i.e. bound to
+// a specific chain ID.
+func (tx *Transaction) IsProtected() bool { return tx.tx.Protected() }
+
 // GetData ...
 func (tx *Transaction) GetData() []byte { return tx.tx.Data() }
 
 // GetGas ...
 func (tx *Transaction) GetGas() int64 { return int64(tx.tx.Gas()) }
 
+// GetGasChecked returns the gas limit, failing if it overflows int64.
+func (tx *Transaction) GetGasChecked() (int64, error) {
+	return uint64ToInt64(tx.tx.Gas(), "gas limit")
+}
+
 // GetGasPrice ...
 func (tx *Transaction) GetGasPrice() *BigInt { return &BigInt{tx.tx.GasPrice()} }
 
+// GetGasTipCap returns the EIP-1559 priority fee cap, or the gas price for
+// legacy transactions.
+func (tx *Transaction) GetGasTipCap() *BigInt { return &BigInt{tx.tx.GasTipCap()} }
+
+// GetGasFeeCap returns the EIP-1559 total fee cap, or the gas price for legacy
+// transactions.
+func (tx *Transaction) GetGasFeeCap() *BigInt { return &BigInt{tx.tx.GasFeeCap()} }
+
+// GetBlobGasFeeCap returns the maximum fee per blob gas of an EIP-4844 blob
+// transaction, or nil for other transaction types.
+func (tx *Transaction) GetBlobGasFeeCap() *BigInt {
+	if tx.tx.Type() != types.BlobTxType {
+		return nil
+	}
+	return &BigInt{tx.tx.BlobGasFeeCap()}
+}
+
+// GetBlobHashes returns the versioned hashes of the blobs carried by an EIP-4844
+// transaction. It is empty for other transaction types.
+func (tx *Transaction) GetBlobHashes() *Hashes { return &Hashes{tx.tx.BlobHashes()} }
+
 // GetValue ...
 func (tx *Transaction) GetValue() *BigInt { return &BigInt{tx.tx.Value()} }
 
 // GetNonce ...
 func (tx *Transaction) GetNonce() int64 { return int64(tx.tx.Nonce()) }
 
+// GetNonceChecked returns the sender nonce, failing if it overflows int64.
+func (tx *Transaction) GetNonceChecked() (int64, error) {
+	return uint64ToInt64(tx.tx.Nonce(), "nonce")
+}
+
 // GetHash ...
 func (tx *Transaction) GetHash() *Hash { return &Hash{tx.tx.Hash()} }
 
 // GetCost ...
 func (tx *Transaction) GetCost() *BigInt { return &BigInt{tx.tx.Cost()} }
 
+// GetEffectiveGasPrice returns the price per gas actually paid when included in
+// a block with the given base fee: min(gasTipCap+baseFee, gasFeeCap). Legacy
+// transactions and a nil base fee (pre-London blocks) yield the gas price.
+func (tx *Transaction) GetEffectiveGasPrice(baseFee *BigInt) *BigInt {
+	if baseFee == nil || baseFee.bigint == nil || tx.tx.Type() == types.LegacyTxType {
+		return &BigInt{tx.tx.GasPrice()}
+	}
+	price := new(big.Int).Add(tx.tx.GasTipCap(), baseFee.bigint)
+	if feeCap := tx.tx.GasFeeCap(); price.Cmp(feeCap) > 0 {
+		price = feeCap
+	}
+	return &BigInt{price}
+}
+
+// GetEffectiveGasTip returns the miner tip per gas when included in a block with
+// the given base fee. An error is returned if the fee cap is below the base fee.
+// A nil base fee yields the tip cap.
+func (tx *Transaction) GetEffectiveGasTip(baseFee *BigInt) (_ *BigInt, err error) {
+	defer recoverError(&err)
+	var fee *big.Int
+	if baseFee != nil {
+		fee = baseFee.bigint
+	}
+	tip, err := tx.tx.EffectiveGasTip(fee)
+	if err != nil {
+		return nil, err
+	}
+	return &BigInt{tip}, nil
+}
+
+// GetSerializedSize returns the length of the canonical encoding of the
+// transaction, including the type prefix of typed transactions.
+func (tx *Transaction) GetSerializedSize() int64 { return int64(tx.tx.Size()) }
+
 // GetSigHash ...
-// Deprecated: GetSigHash cannot know which signer to use.
+// Deprecated: GetSigHash cannot know which signer to use, use GetSigningHash.
 func (tx *Transaction) GetSigHash() *Hash { return &Hash{types.HomesteadSigner{}.Hash(tx.tx)} }
 
+// GetSigningHash returns the hash to be signed for the transaction, using the
+// signer matching its type and the chain ID, which may be nil for legacy
+// transactions without replay protection. Signing it with SignHash yields a
+// signature for WithSignature.
+func (tx *Transaction) GetSigningHash(chainID *BigInt) (_ *Hash, err error) {
+	defer recoverError(&err)
+	signer, err := transactionSigner(tx.tx, chainID)
+	if err != nil {
+		return nil, err
+	}
+	return &Hash{signer.Hash(tx.tx)}, nil
+}
+
+// GetSender returns the sender of the transaction, detecting the signing scheme
+// and chain ID from the signature itself.
+func (tx *Transaction) GetSender() (*Address, error) {
+	return tx.GetFrom(tx.DeriveChainID())
+}
+
 // GetFrom ...
 // Deprecated: use EthereumClient.TransactionSender
-func (tx *Transaction) GetFrom(chainID *BigInt) (address *Address, _ error) {
-	var signer types.Signer = types.HomesteadSigner{}
+func (tx *Transaction) GetFrom(chainID *BigInt) (address *Address, err error) {
+	defer recoverError(&err)
+
+	var id *big.Int
 	if chainID != nil {
-		signer = types.NewEIP155Signer(chainID.bigint)
+		id = chainID.bigint
+	}
+	tx.senderLock.Lock()
+	if tx.sender != nil && sameChainID(tx.senderChainID, id) {
+		from := *tx.sender
+		tx.senderLock.Unlock()
+		return &Address{from}, nil
+	}
+	tx.senderLock.Unlock()
+
+	signer, err := transactionSigner(tx.tx, chainID)
+	if err != nil {
+		return nil, err
 	}
 	from, err := types.Sender(signer, tx.tx)
-	return &Address{from}, err
+	if err != nil {
+		return nil, err
+	}
+	tx.senderLock.Lock()
+	tx.sender = &from
+	if id != nil {
+		tx.senderChainID = new(big.Int).Set(id)
+	} else {
+		tx.senderChainID = nil
+	}
+	tx.senderLock.Unlock()
+
+	return &Address{from}, nil
+}
+
+// sameChainID reports whether two optional chain IDs are equal.
+func sameChainID(a, b *big.Int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Cmp(b) == 0
 }
 
 // GetTo ...
@@ -349,14 +1504,61 @@ func (tx *Transaction) GetTo() *Address {
 	return nil
 }
 
+// IsContractCreation reports whether the transaction deploys a contract, i.e. it
+// has no recipient.
+func (tx *Transaction) IsContractCreation() bool { return tx.tx.To() == nil }
+
+// GetCreatedContractAddress computes the address of the contract deployed by a
+// signed contract creation transaction from its sender and nonce.
+func (tx *Transaction) GetCreatedContractAddress(chainID *BigInt) (_ *Address, err error) {
+	defer recoverError(&err)
+	if tx.tx.To() != nil {
+		return nil, errors.New("transaction is not a contract creation")
+	}
+	if !isSigned(tx.tx) {
+		return nil, errors.New("transaction is not signed")
+	}
+	from, err := tx.GetFrom(chainID)
+	if err != nil {
+		return nil, err
+	}
+	return &Address{crypto.CreateAddress(from.address, tx.tx.Nonce())}, nil
+}
+
 // WithSignature ...
-func (tx *Transaction) WithSignature(sig []byte, chainID *BigInt) (signedTx *Transaction, _ error) {
-	var signer types.Signer = types.HomesteadSigner{}
-	if chainID != nil {
-		signer = types.NewEIP155Signer(chainID.bigint)
+func (tx *Transaction) WithSignature(sig []byte, chainID *BigInt) (signedTx *Transaction, err error) {
+	defer recoverError(&err)
+	signer, err := transactionSigner(tx.tx, chainID)
+	if err != nil {
+		return nil, err
 	}
 	rawTx, err := tx.tx.WithSignature(signer, common.CopyBytes(sig))
-	return &Transaction{rawTx}, err
+	return &Transaction{tx: rawTx}, err
+}
+
+// ToCallMsg converts the transaction into a message call from the given sender,
+// suitable for gas estimation and simulation. Typed transactions carry over their
+// fee caps and access list, legacy ones their gas price.
+func (tx *Transaction) ToCallMsg(from *Address) *CallMsg {
+	msg := ethereum.CallMsg{
+		Gas:   tx.tx.Gas(),
+		Value: tx.tx.Value(),
+		Data:  common.CopyBytes(tx.tx.Data()),
+	}
+	if from != nil {
+		msg.From = from.address
+	}
+	if to := tx.tx.To(); to != nil {
+		addr := *to
+		msg.To = &addr
+	}
+	if tx.tx.Type() == types.LegacyTxType {
+		msg.GasPrice = tx.tx.GasPrice()
+	} else {
+		msg.GasTipCap, msg.GasFeeCap = tx.tx.GasTipCap(), tx.tx.GasFeeCap()
+		msg.AccessList = tx.tx.AccessList()
+	}
+	return &CallMsg{msg}
 }
 
 // Transactions represents a slice of transactions.
@@ -372,7 +1574,194 @@ func (txs *Transactions) Get(index int) (tx *Transaction, _ error) {
 	if index < 0 || index >= len(txs.txs) {
 		return nil, errors.New("index out of bounds")
 	}
-	return &Transaction{txs.txs[index]}, nil
+	return &Transaction{tx: txs.txs[index]}, nil
+}
+
+// NewTransactions creates an empty slice of transactions.
+func NewTransactions() *Transactions {
+	return &Transactions{txs: make(types.Transactions, 0)}
+}
+
+// Append adds a new transaction element to the end of the slice.
+func (txs *Transactions) Append(tx *Transaction) error {
+	if tx == nil || tx.tx == nil {
+		return errors.New("nil transaction")
+	}
+	txs.txs = append(txs.txs, tx.tx)
+	return nil
+}
+
+// NewTransactionsFromJSON parses a slice of transactions from a JSON array.
+func NewTransactionsFromJSON(data string) (_ *Transactions, err error) {
+	defer recoverError(&err)
+	txs := NewTransactions()
+	if err := json.Unmarshal([]byte(data), &txs.txs); err != nil {
+		return nil, err
+	}
+	if txs.txs == nil {
+		txs.txs = make(types.Transactions, 0)
+	}
+	return txs, nil
+}
+
+// EncodeJSON encodes a slice of transactions into a JSON array.
+func (txs *Transactions) EncodeJSON() (_ string, err error) {
+	defer recoverError(&err)
+	if txs.txs == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(txs.txs)
+	return string(data), err
+}
+
+// RecoverSenders recovers the senders of all transactions concurrently, using one
+// worker per available CPU. The result is ordered like the slice. Recovered
+// senders are cached in the underlying transactions, so later GetFrom calls with
+// the same chain ID are cheap.
+//
+// Transactions whose sender cannot be recovered yield the zero address, and the
+// returned error lists the reason for each failing index. The addresses are
+// returned even if some failed.
+func (txs *Transactions) RecoverSenders(chainID *BigInt) (_ *Addresses, err error) {
+	defer recoverError(&err)
+
+	var (
+		senders = make([]common.Address, len(txs.txs))
+		errs    = make([]error, len(txs.txs))
+		indexes = make(chan int)
+		pend    sync.WaitGroup
+	)
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(txs.txs) {
+		workers = len(txs.txs)
+	}
+	for i := 0; i < workers; i++ {
+		pend.Add(1)
+		go func() {
+			defer pend.Done()
+			for index := range indexes {
+				signer, err := transactionSigner(txs.txs[index], chainID)
+				if err == nil {
+					senders[index], err = types.Sender(signer, txs.txs[index])
+				}
+				errs[index] = err
+			}
+		}()
+	}
+	for i := range txs.txs {
+		indexes <- i
+	}
+	close(indexes)
+	pend.Wait()
+
+	var failures []string
+	for i, err := range errs {
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%d: %v", i, err))
+		}
+	}
+	if len(failures) > 0 {
+		err = fmt.Errorf("sender recovery failed for %d transactions: %s", len(failures), strings.Join(failures, "; "))
+	}
+	return &Addresses{senders}, err
+}
+
+// Remove deletes the transaction at the given index from the slice.
+func (txs *Transactions) Remove(index int) error {
+	if index < 0 || index >= len(txs.txs) {
+		return errors.New("index out of bounds")
+	}
+	txs.txs = append(txs.txs[:index], txs.txs[index+1:]...)
+	return nil
+}
+
+// SortByNonce orders the transactions by ascending nonce in place. Transactions
+// with equal nonces keep their relative order.
+func (txs *Transactions) SortByNonce() {
+	sort.SliceStable(txs.txs, func(i, j int) bool {
+		return txs.txs[i].Nonce() < txs.txs[j].Nonce()
+	})
+}
+
+// SortByPriceAndNonce orders the transactions in place the way the miner picks
+// them: transactions of each sender stay in nonce order, and among the next
+// transactions of all senders the one paying the highest tip goes first. With a
+// base fee the effective tip is used, otherwise the tip cap (gas price for legacy
+// transactions). Ties keep the original order.
+//
+// Senders are recovered with the signing scheme detected from each signature.
+func (txs *Transactions) SortByPriceAndNonce(baseFee *BigInt) (err error) {
+	defer recoverError(&err)
+
+	var fee *big.Int
+	if baseFee != nil {
+		fee = baseFee.bigint
+	}
+	// Group the transactions by sender, keeping their position for tie breaks
+	accounts := make(map[common.Address][]*sortedTx)
+	for i, tx := range txs.txs {
+		from, err := (&Transaction{tx: tx}).GetSender()
+		if err != nil {
+			return fmt.Errorf("transaction %d: %v", i, err)
+		}
+		tip, _ := tx.EffectiveGasTip(fee)
+		accounts[from.address] = append(accounts[from.address], &sortedTx{tx: tx, tip: tip, index: i})
+	}
+	heads := make(sortedTxHeap, 0, len(accounts))
+	for _, account := range accounts {
+		sort.SliceStable(account, func(i, j int) bool { return account[i].tx.Nonce() < account[j].tx.Nonce() })
+		for i := 0; i < len(account)-1; i++ {
+			account[i].next = account[i+1]
+		}
+		heads = append(heads, account[0])
+	}
+	heap.Init(&heads)
+
+	sorted := make(types.Transactions, 0, len(txs.txs))
+	for len(heads) > 0 {
+		head := heads[0]
+		sorted = append(sorted, head.tx)
+		if head.next != nil {
+			heads[0] = head.next
+			heap.Fix(&heads, 0)
+		} else {
+			heap.Pop(&heads)
+		}
+	}
+	txs.txs = sorted
+	return nil
+}
+
+// sortedTx is a transaction queued for price and nonce sorting, linked to the
+// next transaction of the same sender.
+type sortedTx struct {
+	tx    *types.Transaction
+	tip   *big.Int
+	index int
+	next  *sortedTx
+}
+
+// sortedTxHeap is a max-heap of the next transactions of each sender, ordered by
+// tip and then by original position.
+type sortedTxHeap []*sortedTx
+
+func (h sortedTxHeap) Len() int      { return len(h) }
+func (h sortedTxHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h sortedTxHeap) Less(i, j int) bool {
+	if cmp := h[i].tip.Cmp(h[j].tip); cmp != 0 {
+		return cmp > 0
+	}
+	return h[i].index < h[j].index
+}
+
+func (h *sortedTxHeap) Push(x interface{}) { *h = append(*h, x.(*sortedTx)) }
+
+func (h *sortedTxHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
 }
 
 // Receipt represents the results of a transaction.
@@ -381,7 +1770,11 @@ type Receipt struct {
 }
 
 // NewReceiptFromRLP parses a transaction receipt from an RLP data dump.
-func NewReceiptFromRLP(data []byte) (*Receipt, error) {
+func NewReceiptFromRLP(data []byte) (_ *Receipt, err error) {
+	defer recoverError(&err)
+	if len(data) > 0 && data[0] <= 0x7f {
+		return nil, fmt.Errorf("typed receipt envelope (type %d), decode it with NewReceiptFromBinary", data[0])
+	}
 	r := &Receipt{
 		receipt: new(types.Receipt),
 	}
@@ -392,27 +1785,64 @@ func NewReceiptFromRLP(data []byte) (*Receipt, error) {
 }
 
 // EncodeRLP encodes a transaction receipt into an RLP data dump.
-func (r *Receipt) EncodeRLP() ([]byte, error) {
+func (r *Receipt) EncodeRLP() (_ []byte, err error) {
+	defer recoverError(&err)
 	return rlp.EncodeToBytes(r.receipt)
 }
 
+// NewReceiptFromBinary parses a transaction receipt from its consensus binary
+// encoding, which is the RLP encoding for legacy receipts and the EIP-2718 typed
+// envelope for the others.
+func NewReceiptFromBinary(data []byte) (_ *Receipt, err error) {
+	defer recoverError(&err)
+	r := &Receipt{
+		receipt: new(types.Receipt),
+	}
+	if err := r.receipt.UnmarshalBinary(common.CopyBytes(data)); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// EncodeBinary encodes a transaction receipt into its consensus binary encoding.
+func (r *Receipt) EncodeBinary() (_ []byte, err error) {
+	defer recoverError(&err)
+	return r.receipt.MarshalBinary()
+}
+
 // NewReceiptFromJSON parses a transaction receipt from a JSON data dump.
-func NewReceiptFromJSON(data string) (*Receipt, error) {
+func NewReceiptFromJSON(data string) (_ *Receipt, err error) {
+	defer recoverError(&err)
+	// Older versions of EncodeJSON produced RLP, give a hint for persisted data
+	if trimmed := strings.TrimSpace(data); trimmed != "" && trimmed[0] >= 0xc0 {
+		return nil, errors.New("receipt data looks like RLP, decode it with NewReceiptFromRLP")
+	}
+	return decodeReceiptJSON([]byte(data))
+}
+
+// decodeReceiptJSON parses a receipt in the format returned by
+// eth_getTransactionReceipt, including the block context fields.
+func decodeReceiptJSON(data []byte) (*Receipt, error) {
 	r := &Receipt{
 		receipt: new(types.Receipt),
 	}
-	if err := json.Unmarshal([]byte(data), r.receipt); err != nil {
+	if err := json.Unmarshal(data, r.receipt); err != nil {
 		return nil, err
 	}
 	return r, nil
 }
 
 // EncodeJSON encodes a transaction receipt into a JSON data dump.
-func (r *Receipt) EncodeJSON() (string, error) {
-	data, err := rlp.EncodeToBytes(r.receipt)
+func (r *Receipt) EncodeJSON() (_ string, err error) {
+	defer recoverError(&err)
+	data, err := json.Marshal(r.receipt)
 	return string(data), err
 }
 
+// GetType returns the EIP-2718 type of the transaction the receipt belongs to,
+// see the TxType constants.
+func (r *Receipt) GetType() int { return int(r.receipt.Type) }
+
 // GetStatus ...
 func (r *Receipt) GetStatus() int { return int(r.receipt.Status) }
 
@@ -422,12 +1852,29 @@ func (r *Receipt) GetPostState() []byte { return r.receipt.PostState }
 // GetCumulativeGasUsed ...
 func (r *Receipt) GetCumulativeGasUsed() int64 { return int64(r.receipt.CumulativeGasUsed) }
 
+// GetCumulativeGasUsedChecked returns the gas used in the block up to and
+// including the transaction, failing if it overflows int64.
+func (r *Receipt) GetCumulativeGasUsedChecked() (int64, error) {
+	return uint64ToInt64(r.receipt.CumulativeGasUsed, "cumulative gas used")
+}
+
 // GetBloom ...
 func (r *Receipt) GetBloom() *Bloom { return &Bloom{r.receipt.Bloom} }
 
 // GetLogs ...
 func (r *Receipt) GetLogs() *Logs { return &Logs{r.receipt.Logs} }
 
+// VerifyReceiptBloom checks that the bloom filter stored in the receipt matches
+// the one computed from its logs, catching receipts whose logs or bloom were
+// tampered with.
+func VerifyReceiptBloom(receipt *Receipt) (err error) {
+	defer recoverError(&err)
+	if want := types.BytesToBloom(types.LogsBloom(receipt.receipt.Logs)); receipt.receipt.Bloom != want {
+		return fmt.Errorf("receipt bloom mismatch: have %x, want %x", receipt.receipt.Bloom, want)
+	}
+	return nil
+}
+
 // GetTxHash ...
 func (r *Receipt) GetTxHash() *Hash { return &Hash{r.receipt.TxHash} }
 
@@ -437,6 +1884,172 @@ func (r *Receipt) GetContractAddress() *Address { return &Address{r.receipt.Cont
 // GetGasUsed ...
 func (r *Receipt) GetGasUsed() int64 { return int64(r.receipt.GasUsed) }
 
+// GetGasUsedChecked returns the gas used by the transaction, failing if it
+// overflows int64.
+func (r *Receipt) GetGasUsedChecked() (int64, error) {
+	return uint64ToInt64(r.receipt.GasUsed, "gas used")
+}
+
+// GetBlockHash returns the hash of the block containing the transaction. It is
+// the zero hash for receipts decoded from consensus RLP, which lacks the field.
+func (r *Receipt) GetBlockHash() *Hash { return &Hash{r.receipt.BlockHash} }
+
+// GetBlockNumber returns the number of the block containing the transaction. It
+// fails for receipts decoded from consensus RLP, which lacks the field.
+func (r *Receipt) GetBlockNumber() (int64, error) {
+	return blockNumberInt64(r.receipt.BlockNumber)
+}
+
+// GetTransactionIndex returns the position of the transaction in its block. It
+// is 0 for receipts decoded from consensus RLP, which lacks the field.
+func (r *Receipt) GetTransactionIndex() int { return int(r.receipt.TransactionIndex) }
+
+// GetEffectiveGasPrice returns the price per gas actually paid by the
+// transaction, base fee plus effective tip after London. It is nil for receipts
+// lacking the field, such as those decoded from consensus RLP or served by nodes
+// predating it.
+func (r *Receipt) GetEffectiveGasPrice() *BigInt {
+	if r.receipt.EffectiveGasPrice == nil {
+		return nil
+	}
+	return &BigInt{new(big.Int).Set(r.receipt.EffectiveGasPrice)}
+}
+
+// GetFeePaid returns the fee paid by the transaction in wei, i.e. the effective
+// gas price times the gas used. It is nil if the receipt lacks the effective gas
+// price, see GetFeePaidWithGasPrice.
+func (r *Receipt) GetFeePaid() *BigInt {
+	return r.GetFeePaidWithGasPrice(nil)
+}
+
+// GetFeePaidWithGasPrice returns the fee paid by the transaction in wei like
+// GetFeePaid, using the given gas price, e.g. the one of a legacy transaction,
+// if the receipt lacks the effective gas price. It is nil if neither is known.
+func (r *Receipt) GetFeePaidWithGasPrice(gasPrice *BigInt) *BigInt {
+	price := r.receipt.EffectiveGasPrice
+	if price == nil {
+		if gasPrice == nil || gasPrice.bigint == nil {
+			return nil
+		}
+		price = gasPrice.bigint
+	}
+	return &BigInt{new(big.Int).Mul(price, new(big.Int).SetUint64(r.receipt.GasUsed))}
+}
+
+// Receipts represents a slice of transaction receipts.
+type Receipts struct{ receipts types.Receipts }
+
+// NewReceipts creates an empty slice of receipts.
+func NewReceipts() *Receipts {
+	return &Receipts{receipts: make(types.Receipts, 0)}
+}
+
+// Size returns the number of receipts in the slice.
+func (r *Receipts) Size() int {
+	return len(r.receipts)
+}
+
+// Get returns the receipt at the given index from the slice.
+func (r *Receipts) Get(index int) (receipt *Receipt, _ error) {
+	if index < 0 || index >= len(r.receipts) {
+		return nil, errors.New("index out of bounds")
+	}
+	return &Receipt{r.receipts[index]}, nil
+}
+
+// Append adds a new receipt element to the end of the slice.
+func (r *Receipts) Append(receipt *Receipt) error {
+	if receipt == nil || receipt.receipt == nil {
+		return errors.New("nil receipt")
+	}
+	r.receipts = append(r.receipts, receipt.receipt)
+	return nil
+}
+
+// NewReceiptsFromJSON parses a slice of receipts from a JSON array.
+func NewReceiptsFromJSON(data string) (_ *Receipts, err error) {
+	defer recoverError(&err)
+	r := NewReceipts()
+	if err := json.Unmarshal([]byte(data), &r.receipts); err != nil {
+		return nil, err
+	}
+	if r.receipts == nil {
+		r.receipts = make(types.Receipts, 0)
+	}
+	return r, nil
+}
+
+// EncodeJSON encodes a slice of receipts into a JSON array.
+func (r *Receipts) EncodeJSON() (_ string, err error) {
+	defer recoverError(&err)
+	if r.receipts == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(r.receipts)
+	return string(data), err
+}
+
+// DeriveFields fills in the fields of the receipts not covered by the consensus
+// encoding from the block they belong to: transaction hash and index, block
+// hash and number, effective gas price, gas used, contract address and the
+// positions of the logs. The senders needed for contract addresses are recovered
+// with the signing scheme detected from each signature. Receipts whose
+// cumulative gas used decreases are rejected without being modified.
+func (r *Receipts) DeriveFields(block *Block) (err error) {
+	defer recoverError(&err)
+	txs := block.block.Transactions()
+	if len(txs) != len(r.receipts) {
+		return fmt.Errorf("receipt count mismatch: have %d, want %d", len(r.receipts), len(txs))
+	}
+	for i := 1; i < len(r.receipts); i++ {
+		if prev, cur := r.receipts[i-1].CumulativeGasUsed, r.receipts[i].CumulativeGasUsed; cur < prev {
+			return fmt.Errorf("receipt %d: cumulative gas used %d below %d of the previous receipt", i, cur, prev)
+		}
+	}
+	var (
+		hash    = block.block.Hash()
+		number  = block.block.NumberU64()
+		baseFee *BigInt
+		logIdx  uint
+	)
+	if fee := block.block.BaseFee(); fee != nil {
+		baseFee = &BigInt{fee}
+	}
+	for i, receipt := range r.receipts {
+		tx := &Transaction{tx: txs[i]}
+
+		receipt.Type = txs[i].Type()
+		receipt.TxHash = txs[i].Hash()
+		receipt.EffectiveGasPrice = tx.GetEffectiveGasPrice(baseFee).bigint
+		receipt.BlockHash = hash
+		receipt.BlockNumber = new(big.Int).SetUint64(number)
+		receipt.TransactionIndex = uint(i)
+
+		if txs[i].To() == nil {
+			from, err := tx.GetSender()
+			if err != nil {
+				return fmt.Errorf("transaction %d: %v", i, err)
+			}
+			receipt.ContractAddress = crypto.CreateAddress(from.address, txs[i].Nonce())
+		} else {
+			receipt.ContractAddress = common.Address{}
+		}
+		receipt.GasUsed = receipt.CumulativeGasUsed
+		if i > 0 {
+			receipt.GasUsed -= r.receipts[i-1].CumulativeGasUsed
+		}
+		for _, log := range receipt.Logs {
+			log.BlockNumber = number
+			log.BlockHash = hash
+			log.TxHash = receipt.TxHash
+			log.TxIndex = uint(i)
+			log.Index = logIdx
+			logIdx++
+		}
+	}
+	return nil
+}
+
 // Info represents a diagnostic information about the whisper node.
 type Info struct {
 	info *whisper.Info
@@ -0,0 +1,1941 @@
+package web3go
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+const testKeyHex = "b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291"
+
+var testAddress, _ = NewAddressFromHex("0x71562b71999873DB5b286dF957af199Ec94617F7")
+
+// Unprotected Homestead transaction with nonce 5 signed by testKeyHex.
+const testHomesteadTx = "0xf864058504a817c80082520894095e7baea6a6c7c4c2dfeb977efac326af552d870a801ba0b2d233c18b11fee46491ede7e1e772e9e6f305ff6cdb1f948dc49a6c0299098ca070ac1054beb16209bf456afe78022c36c52632be8e12aed99d7d979d9a3f4852"
+
+// Transactions of each envelope type signed by testKeyHex on chain 1, in the raw
+// binary and the RPC JSON encodings.
+var testTxFixtures = []struct {
+	txType int
+	raw    string
+	json   string
+	hash   string
+}{
+	{
+		txType: TxTypeLegacy,
+		raw:    "0xf86c808504a817c80082520894095e7baea6a6c7c4c2dfeb977efac326af552d87880de0b6b3a76400008026a0196ad7f31265d613108a252adb7f03962be761ac0c4625d3a10530b7681e5aa1a0133bf90a8bb0d6141260e4b95725616074949d5c11b9a0a1b48a360ade7c6d53",
+		json:   `{"type":"0x0","chainId":"0x1","nonce":"0x0","to":"0x095e7baea6a6c7c4c2dfeb977efac326af552d87","gas":"0x5208","gasPrice":"0x4a817c800","maxPriorityFeePerGas":null,"maxFeePerGas":null,"value":"0xde0b6b3a7640000","input":"0x","v":"0x26","r":"0x196ad7f31265d613108a252adb7f03962be761ac0c4625d3a10530b7681e5aa1","s":"0x133bf90a8bb0d6141260e4b95725616074949d5c11b9a0a1b48a360ade7c6d53","hash":"0x591e110f74454e49034ee6c460be97a0ce13d87f8e567b1594e2f35e6de60a07"}`,
+		hash:   "0x591e110f74454e49034ee6c460be97a0ce13d87f8e567b1594e2f35e6de60a07",
+	},
+	{
+		txType: TxTypeAccessList,
+		raw:    "0x01f89f01018504a817c80082753094095e7baea6a6c7c4c2dfeb977efac326af552d870180f838f794095e7baea6a6c7c4c2dfeb977efac326af552d87e1a0010000000000000000000000000000000000000000000000000000000000000001a04075023a8dcb8a532c1906f9f52898a6cef27d24ecd92ef2e13e237624cdaf45a06f5732f9612119ec1cdf25a32162de5d5608a10d00369aeabaeb898b5aa5a5a0",
+		json:   `{"type":"0x1","chainId":"0x1","nonce":"0x1","to":"0x095e7baea6a6c7c4c2dfeb977efac326af552d87","gas":"0x7530","gasPrice":"0x4a817c800","maxPriorityFeePerGas":null,"maxFeePerGas":null,"value":"0x1","input":"0x","accessList":[{"address":"0x095e7baea6a6c7c4c2dfeb977efac326af552d87","storageKeys":["0x0100000000000000000000000000000000000000000000000000000000000000"]}],"v":"0x1","r":"0x4075023a8dcb8a532c1906f9f52898a6cef27d24ecd92ef2e13e237624cdaf45","s":"0x6f5732f9612119ec1cdf25a32162de5d5608a10d00369aeabaeb898b5aa5a5a0","yParity":"0x1","hash":"0x1cac9889e125659da7dbae60cf112e8eee529d864a0b8a63a06f27b14477c158"}`,
+		hash:   "0x1cac9889e125659da7dbae60cf112e8eee529d864a0b8a63a06f27b14477c158",
+	},
+	{
+		txType: TxTypeDynamicFee,
+		raw:    "0x02f86d01028459682f008506fc23ac0082520894095e7baea6a6c7c4c2dfeb977efac326af552d870282deadc001a084bf07f1dd946ee05e356343964404bcf895b11758b17a33a6b6604c406b474ca02784153112f9c0afd48f7c97a4c9bb8dd7dcb7d08259fb8f7f75ebb1308ea684",
+		json:   `{"type":"0x2","chainId":"0x1","nonce":"0x2","to":"0x095e7baea6a6c7c4c2dfeb977efac326af552d87","gas":"0x5208","gasPrice":null,"maxPriorityFeePerGas":"0x59682f00","maxFeePerGas":"0x6fc23ac00","value":"0x2","input":"0xdead","accessList":[],"v":"0x1","r":"0x84bf07f1dd946ee05e356343964404bcf895b11758b17a33a6b6604c406b474c","s":"0x2784153112f9c0afd48f7c97a4c9bb8dd7dcb7d08259fb8f7f75ebb1308ea684","yParity":"0x1","hash":"0x7122fc4b027fac60b68ee8a94e6dcccdca66f8e373abda5dcf7bda310ac81230"}`,
+		hash:   "0x7122fc4b027fac60b68ee8a94e6dcccdca66f8e373abda5dcf7bda310ac81230",
+	},
+}
+
+// newTestDynamicFeeTx creates an EIP-1559 transaction from valid arguments.
+func newTestDynamicFeeTx(chainID *BigInt, nonce int64, to *Address, amount *BigInt, gasLimit int64, gasTipCap *BigInt, gasFeeCap *BigInt, data []byte) *Transaction {
+	tx, err := NewDynamicFeeTransaction(chainID, nonce, to, amount, gasLimit, gasTipCap, gasFeeCap, data)
+	if err != nil {
+		panic(err)
+	}
+	return tx
+}
+
+// newTestDynamicFeeCreation creates an EIP-1559 contract creation from valid
+// arguments.
+func newTestDynamicFeeCreation(chainID *BigInt, nonce int64, amount *BigInt, gasLimit int64, gasTipCap *BigInt, gasFeeCap *BigInt, data []byte) *Transaction {
+	return newTestDynamicFeeTx(chainID, nonce, nil, amount, gasLimit, gasTipCap, gasFeeCap, data)
+}
+
+func TestDynamicFeeTransactionNilArgs(t *testing.T) {
+	tx, err := NewDynamicFeeTransaction(NewBigInt(1), 0, testAddress, nil, 21000, NewBigInt(1), NewBigInt(2), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.GetValue().GetInt64() != 0 {
+		t.Errorf("nil amount not zero: have %v", tx.GetValue())
+	}
+	for i, args := range [][3]*BigInt{
+		{nil, NewBigInt(1), NewBigInt(2)},
+		{NewBigInt(1), nil, NewBigInt(2)},
+		{NewBigInt(1), NewBigInt(1), nil},
+	} {
+		if _, err := NewDynamicFeeTransaction(args[0], 0, testAddress, NewBigInt(1), 21000, args[1], args[2], nil); err == nil {
+			t.Errorf("test %d: expected error for nil argument", i)
+		}
+	}
+	if _, err := NewDynamicFeeContractCreation(nil, 0, nil, 100000, NewBigInt(1), NewBigInt(2), nil); err == nil {
+		t.Error("expected error for contract creation without chain ID")
+	}
+}
+
+func TestDynamicFeeTransactionRoundTrip(t *testing.T) {
+	key, _ := crypto.HexToECDSA(testKeyHex)
+	chainID := NewBigInt(5)
+
+	tx := newTestDynamicFeeTx(chainID, 3, testAddress, NewBigInt(1000), 21000, NewBigInt(2), NewBigInt(100), []byte{0x01})
+	if tx.GetGasTipCap().GetInt64() != 2 || tx.GetGasFeeCap().GetInt64() != 100 {
+		t.Fatalf("fee caps mismatch: have %v/%v, want 2/100", tx.GetGasTipCap(), tx.GetGasFeeCap())
+	}
+	sig, err := crypto.Sign(types.LatestSignerForChainID(chainID.bigint).Hash(tx.tx).Bytes(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed, err := tx.WithSignature(sig, chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc, err := signed.EncodeRLP()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec, err := NewTransactionFromRLP(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dec.GetHash().GetHex() != signed.GetHash().GetHex() {
+		t.Errorf("hash mismatch after round-trip: have %s, want %s", dec.GetHash().GetHex(), signed.GetHash().GetHex())
+	}
+	if dec.GetGasFeeCap().GetInt64() != 100 {
+		t.Errorf("fee cap mismatch after round-trip: have %v, want 100", dec.GetGasFeeCap())
+	}
+}
+
+func TestLegacyTransactionFeeCaps(t *testing.T) {
+	tx := NewTransaction(0, testAddress, NewBigInt(1), 21000, NewBigInt(7), nil)
+	if tx.GetGasTipCap().GetInt64() != 7 || tx.GetGasFeeCap().GetInt64() != 7 {
+		t.Errorf("fee caps mismatch: have %v/%v, want 7/7", tx.GetGasTipCap(), tx.GetGasFeeCap())
+	}
+}
+
+func TestTransactionGetType(t *testing.T) {
+	for i, fixture := range testTxFixtures {
+		tx, err := NewTransactionFromJSON(fixture.json)
+		if err != nil {
+			t.Fatalf("fixture %d: failed to decode JSON: %v", i, err)
+		}
+		if tx.GetType() != fixture.txType {
+			t.Errorf("fixture %d: JSON type mismatch: have %d, want %d", i, tx.GetType(), fixture.txType)
+		}
+		enc, err := tx.EncodeRLP()
+		if err != nil {
+			t.Fatalf("fixture %d: failed to encode RLP: %v", i, err)
+		}
+		dec, err := NewTransactionFromRLP(enc)
+		if err != nil {
+			t.Fatalf("fixture %d: failed to decode RLP: %v", i, err)
+		}
+		if dec.GetType() != fixture.txType {
+			t.Errorf("fixture %d: RLP type mismatch: have %d, want %d", i, dec.GetType(), fixture.txType)
+		}
+	}
+	legacy := NewTransaction(0, testAddress, NewBigInt(1), 21000, NewBigInt(1), nil)
+	if legacy.GetType() != TxTypeLegacy {
+		t.Errorf("legacy constructor type mismatch: have %d, want %d", legacy.GetType(), TxTypeLegacy)
+	}
+	dynamic := newTestDynamicFeeTx(NewBigInt(1), 0, testAddress, NewBigInt(1), 21000, NewBigInt(1), NewBigInt(2), nil)
+	if dynamic.GetType() != TxTypeDynamicFee {
+		t.Errorf("dynamic fee constructor type mismatch: have %d, want %d", dynamic.GetType(), TxTypeDynamicFee)
+	}
+}
+
+func TestTransactionRawHexRoundTrip(t *testing.T) {
+	for i, fixture := range testTxFixtures {
+		tx, err := NewTransactionFromRawHex(fixture.raw)
+		if err != nil {
+			t.Fatalf("fixture %d: failed to decode raw hex: %v", i, err)
+		}
+		if tx.GetHash().GetHex() != fixture.hash {
+			t.Errorf("fixture %d: hash mismatch: have %s, want %s", i, tx.GetHash().GetHex(), fixture.hash)
+		}
+		raw, err := tx.EncodeRawHex()
+		if err != nil {
+			t.Fatalf("fixture %d: failed to encode raw hex: %v", i, err)
+		}
+		if raw != fixture.raw {
+			t.Errorf("fixture %d: raw hex mismatch: have %s, want %s", i, raw, fixture.raw)
+		}
+		// Decoding must also work without the 0x prefix and from plain binary
+		if tx, err = NewTransactionFromRawHex(fixture.raw[2:]); err != nil {
+			t.Fatalf("fixture %d: failed to decode unprefixed raw hex: %v", i, err)
+		}
+		bin, err := tx.EncodeBinary()
+		if err != nil {
+			t.Fatalf("fixture %d: failed to encode binary: %v", i, err)
+		}
+		if tx, err = NewTransactionFromBinary(bin); err != nil {
+			t.Fatalf("fixture %d: failed to decode binary: %v", i, err)
+		}
+		if tx.GetHash().GetHex() != fixture.hash {
+			t.Errorf("fixture %d: binary hash mismatch: have %s, want %s", i, tx.GetHash().GetHex(), fixture.hash)
+		}
+	}
+}
+
+func TestTransactionRawHexInvalid(t *testing.T) {
+	for _, raw := range []string{"0x02f", "0xzz", "not hex at all!"} {
+		if _, err := NewTransactionFromRawHex(raw); err == nil {
+			t.Errorf("expected error for %q", raw)
+		}
+	}
+}
+
+func TestSignTransaction(t *testing.T) {
+	chainID := NewBigInt(1)
+	txs := []*Transaction{
+		NewTransaction(0, testAddress, NewBigInt(1), 21000, NewBigInt(1), nil),
+		newTestDynamicFeeTx(chainID, 0, testAddress, NewBigInt(1), 21000, NewBigInt(1), NewBigInt(2), nil),
+	}
+	for i, tx := range txs {
+		signed, err := SignTransaction(tx, testKeyHex, chainID)
+		if err != nil {
+			t.Fatalf("tx %d: failed to sign: %v", i, err)
+		}
+		from, err := types.Sender(types.LatestSignerForChainID(chainID.bigint), signed.tx)
+		if err != nil {
+			t.Fatalf("tx %d: failed to recover sender: %v", i, err)
+		}
+		if from != testAddress.address {
+			t.Errorf("tx %d: sender mismatch: have %s, want %s", i, from.Hex(), testAddress.GetHex())
+		}
+		if _, err := SignTransaction(signed, testKeyHex, chainID); err == nil {
+			t.Errorf("tx %d: expected error re-signing a signed transaction", i)
+		}
+		if _, err := SignTransactionWithHexKey(signed, "0x"+testKeyHex, chainID, true); err != nil {
+			t.Errorf("tx %d: failed to force re-sign: %v", i, err)
+		}
+	}
+	if _, err := SignTransaction(txs[1], testKeyHex, nil); err == nil {
+		t.Error("expected error signing a typed transaction without chain ID")
+	}
+	if _, err := SignTransaction(txs[0], "not a key", chainID); err == nil || !strings.HasPrefix(err.Error(), "invalid private key") {
+		t.Errorf("unexpected error for invalid key: %v", err)
+	}
+}
+
+func TestTransactionGetFrom(t *testing.T) {
+	for i, fixture := range testTxFixtures {
+		tx, err := NewTransactionFromRawHex(fixture.raw)
+		if err != nil {
+			t.Fatalf("fixture %d: failed to decode: %v", i, err)
+		}
+		from, err := tx.GetFrom(NewBigInt(1))
+		if err != nil {
+			t.Fatalf("fixture %d: failed to recover sender: %v", i, err)
+		}
+		if from.GetHex() != testAddress.GetHex() {
+			t.Errorf("fixture %d: sender mismatch: have %s, want %s", i, from.GetHex(), testAddress.GetHex())
+		}
+		if _, err := tx.GetFrom(nil); fixture.txType != TxTypeLegacy && (err == nil || err.Error() != "chainID required for typed transactions") {
+			t.Errorf("fixture %d: unexpected error without chain ID: %v", i, err)
+		}
+	}
+}
+
+func BenchmarkTransactionGetFrom(b *testing.B) {
+	tx, _ := NewTransactionFromRawHex(testTxFixtures[2].raw)
+	chainID := NewBigInt(1)
+
+	b.Run("uncached", func(b *testing.B) {
+		signer := types.LatestSignerForChainID(chainID.bigint)
+		for i := 0; i < b.N; i++ {
+			for j := 0; j < 1000; j++ {
+				signer.Sender(tx.tx)
+			}
+		}
+	})
+	b.Run("cached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j := 0; j < 1000; j++ {
+				tx.GetFrom(chainID)
+			}
+		}
+	})
+}
+
+func TestTransactionToCallMsg(t *testing.T) {
+	dynamic, _ := NewTransactionFromRawHex(testTxFixtures[2].raw)
+	msg := dynamic.ToCallMsg(testAddress)
+	if msg.GetGasPrice().bigint != nil {
+		t.Errorf("unexpected gas price on dynamic fee call: %v", msg.GetGasPrice())
+	}
+	if msg.GetGasTipCap().String() != dynamic.GetGasTipCap().String() || msg.GetGasFeeCap().String() != dynamic.GetGasFeeCap().String() {
+		t.Errorf("fee caps mismatch: have %v/%v, want %v/%v", msg.GetGasTipCap(), msg.GetGasFeeCap(), dynamic.GetGasTipCap(), dynamic.GetGasFeeCap())
+	}
+	if msg.GetFrom().GetHex() != testAddress.GetHex() || msg.GetTo().GetHex() != dynamic.GetTo().GetHex() {
+		t.Errorf("address mismatch: have %s -> %s", msg.GetFrom().GetHex(), msg.GetTo().GetHex())
+	}
+	// Mutating the call data must not leak into the transaction
+	msg.GetData()[0] = 0x00
+	if dynamic.GetData()[0] != 0xde {
+		t.Error("call message data aliases the transaction data")
+	}
+
+	legacy := NewContractCreation(0, NewBigInt(1), 21000, NewBigInt(7), nil)
+	msg = legacy.ToCallMsg(testAddress)
+	if msg.GetGasPrice().GetInt64() != 7 || msg.GetTo() != nil {
+		t.Errorf("unexpected legacy call: gas price %v, to %v", msg.GetGasPrice(), msg.GetTo())
+	}
+}
+
+func TestTransactionGetSerializedSize(t *testing.T) {
+	for i, fixture := range testTxFixtures {
+		tx, _ := NewTransactionFromRawHex(fixture.raw)
+		bin, _ := tx.EncodeBinary()
+		if size := tx.GetSerializedSize(); size != int64(len(bin)) {
+			t.Errorf("fixture %d: size mismatch: have %d, want %d", i, size, len(bin))
+		}
+		// Freshly constructed transactions have no cached size
+		fresh := &Transaction{tx: types.NewTx(&types.LegacyTx{Gas: uint64(i)})}
+		bin, _ = fresh.EncodeBinary()
+		if size := fresh.GetSerializedSize(); size != int64(len(bin)) {
+			t.Errorf("fixture %d: fresh size mismatch: have %d, want %d", i, size, len(bin))
+		}
+	}
+	block := &Block{block: types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1)})}
+	enc, _ := block.EncodeRLP()
+	if size := block.GetSize(); size != int64(len(enc)) {
+		t.Errorf("block size mismatch: have %d, want %d", size, len(enc))
+	}
+}
+
+func TestTransactionEffectiveGasPrice(t *testing.T) {
+	tests := []struct {
+		tx      *Transaction
+		baseFee *BigInt
+		price   int64
+		tip     int64
+		fail    bool
+	}{
+		{NewTransaction(0, testAddress, NewBigInt(0), 21000, NewBigInt(50), nil), NewBigInt(40), 50, 10, false},
+		{NewTransaction(0, testAddress, NewBigInt(0), 21000, NewBigInt(50), nil), nil, 50, 50, false},
+		{newTestDynamicFeeTx(NewBigInt(1), 0, testAddress, NewBigInt(0), 21000, NewBigInt(2), NewBigInt(100), nil), NewBigInt(40), 42, 2, false},
+		{newTestDynamicFeeTx(NewBigInt(1), 0, testAddress, NewBigInt(0), 21000, NewBigInt(2), NewBigInt(100), nil), NewBigInt(99), 100, 1, false},
+		{newTestDynamicFeeTx(NewBigInt(1), 0, testAddress, NewBigInt(0), 21000, NewBigInt(0), NewBigInt(100), nil), NewBigInt(40), 40, 0, false},
+		{newTestDynamicFeeTx(NewBigInt(1), 0, testAddress, NewBigInt(0), 21000, NewBigInt(2), NewBigInt(100), nil), nil, 100, 2, false},
+		{newTestDynamicFeeTx(NewBigInt(1), 0, testAddress, NewBigInt(0), 21000, NewBigInt(2), NewBigInt(100), nil), NewBigInt(101), 100, 0, true},
+	}
+	for i, tt := range tests {
+		if price := tt.tx.GetEffectiveGasPrice(tt.baseFee); price.GetInt64() != tt.price {
+			t.Errorf("test %d: price mismatch: have %v, want %d", i, price, tt.price)
+		}
+		tip, err := tt.tx.GetEffectiveGasTip(tt.baseFee)
+		if tt.fail {
+			if err == nil {
+				t.Errorf("test %d: expected error for fee cap below base fee", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test %d: failed to compute tip: %v", i, err)
+		} else if tip.GetInt64() != tt.tip {
+			t.Errorf("test %d: tip mismatch: have %v, want %d", i, tip, tt.tip)
+		}
+	}
+}
+
+func TestTransactionCreatedContractAddress(t *testing.T) {
+	// Mainnet deployment of the deterministic deployment proxy (transaction
+	// 0xeddf9e61fb9d8f5111840daef55e5fde0041f5702856532cdbb5a02998033d26), sent
+	// by the keyless account 0x3fab184622dc19b6109349b94811493bf2a45362 with nonce
+	// 0 and creating the contract at 0x4e59b44847b379578588920ca78fbf26c0b4956c.
+	tx, err := NewTransactionFromRawHex("0xf8a58085174876e800830186a08080b853604580600e600039806000f350fe7fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffe03601600081602082378035828234f58015156039578182fd5b8082525050506014600cf31ba02222222222222222222222222222222222222222222222222222222222222222a02222222222222222222222222222222222222222222222222222222222222222")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tx.IsContractCreation() {
+		t.Fatal("deployment not detected as contract creation")
+	}
+	if tx.GetHash().GetHex() != "0xeddf9e61fb9d8f5111840daef55e5fde0041f5702856532cdbb5a02998033d26" {
+		t.Errorf("transaction hash mismatch: have %s", tx.GetHash().GetHex())
+	}
+	if from, err := tx.GetSender(); err != nil || from.GetHex() != "0x3fAB184622Dc19b6109349B94811493BF2a45362" || tx.GetNonce() != 0 {
+		t.Errorf("deployer mismatch: have %v with nonce %d (%v)", from, tx.GetNonce(), err)
+	}
+	addr, err := tx.GetCreatedContractAddress(NewBigInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr.GetHex() != "0x4e59b44847b379578588920cA78FbF26c0B4956C" {
+		t.Errorf("contract address mismatch: have %s, want 0x4e59b44847b379578588920cA78FbF26c0B4956C", addr.GetHex())
+	}
+	transfer, _ := NewTransactionFromRawHex(testTxFixtures[0].raw)
+	if transfer.IsContractCreation() {
+		t.Error("transfer detected as contract creation")
+	}
+	if _, err := transfer.GetCreatedContractAddress(NewBigInt(1)); err == nil {
+		t.Error("expected error for transaction with recipient")
+	}
+	unsigned := NewContractCreation(0, NewBigInt(0), 100000, NewBigInt(1), nil)
+	if _, err := unsigned.GetCreatedContractAddress(NewBigInt(1)); err == nil {
+		t.Error("expected error for unsigned transaction")
+	}
+}
+
+func TestTransactionsBuilder(t *testing.T) {
+	txs := NewTransactions()
+	for _, fixture := range testTxFixtures {
+		tx, _ := NewTransactionFromRawHex(fixture.raw)
+		if err := txs.Append(tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := txs.Append(nil); err == nil {
+		t.Error("expected error appending nil transaction")
+	}
+	if err := txs.Remove(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := txs.Remove(2); err == nil {
+		t.Error("expected error removing out of bounds")
+	}
+	if txs.Size() != 2 {
+		t.Fatalf("size mismatch: have %d, want 2", txs.Size())
+	}
+	if tx, _ := txs.Get(1); tx.GetHash().GetHex() != testTxFixtures[2].hash {
+		t.Errorf("order mismatch: have %s, want %s", tx.GetHash().GetHex(), testTxFixtures[2].hash)
+	}
+
+	header := &Header{&types.Header{Number: big.NewInt(10), Difficulty: big.NewInt(1)}}
+	block, err := NewBlock(header, txs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block.GetTxHash().hash != types.DeriveSha(txs.txs, trie.NewStackTrie(nil)) {
+		t.Error("transaction root not recomputed")
+	}
+	if block.GetUncleHash().hash != types.EmptyUncleHash {
+		t.Error("uncle root not recomputed")
+	}
+	enc, err := block.EncodeRLP()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec, err := NewBlockFromRLP(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dec.GetHash().GetHex() != block.GetHash().GetHex() || dec.GetTransactions().Size() != 2 {
+		t.Errorf("block round-trip mismatch")
+	}
+	// Editing the transactions of a block must not change the block
+	first, _ := block.GetTransactionByIndex(0)
+	if err := block.GetTransactions().Remove(0); err != nil {
+		t.Fatal(err)
+	}
+	if tx, _ := block.GetTransactionByIndex(0); block.GetTransactionCount() != 2 || !tx.GetHash().Equals(first.GetHash()) {
+		t.Error("block transactions changed by Remove")
+	}
+	if err := block.VerifyTransactionsRoot(); err != nil {
+		t.Errorf("block corrupted by Remove: %v", err)
+	}
+	if index, err := block.GetTransactionIndex(first.GetHash()); err != nil || index != 0 {
+		t.Errorf("transaction index mismatch: have %d (%v), want 0", index, err)
+	}
+	body := block.GetBody()
+	body.GetTransactions().Remove(0)
+	if body.GetTransactions().Size() != 2 {
+		t.Error("body transactions changed by Remove")
+	}
+}
+
+func TestTransactionsJSONRoundTrip(t *testing.T) {
+	txs := NewTransactions()
+	if enc, err := txs.EncodeJSON(); err != nil || enc != "[]" {
+		t.Fatalf("empty encoding mismatch: have %q (%v), want []", enc, err)
+	}
+	if dec, err := NewTransactionsFromJSON("[]"); err != nil || dec.Size() != 0 {
+		t.Fatalf("empty decoding mismatch: have %v (%v)", dec, err)
+	}
+	for _, fixture := range testTxFixtures {
+		tx, _ := NewTransactionFromRawHex(fixture.raw)
+		txs.Append(tx)
+	}
+	enc, err := txs.EncodeJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec, err := NewTransactionsFromJSON(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dec.Size() != len(testTxFixtures) {
+		t.Fatalf("size mismatch: have %d, want %d", dec.Size(), len(testTxFixtures))
+	}
+	for i, fixture := range testTxFixtures {
+		if tx, _ := dec.Get(i); tx.GetHash().GetHex() != fixture.hash {
+			t.Errorf("tx %d: hash mismatch: have %s, want %s", i, tx.GetHash().GetHex(), fixture.hash)
+		}
+	}
+}
+
+func TestTransactionsRecoverSenders(t *testing.T) {
+	txs := NewTransactions()
+	for _, fixture := range testTxFixtures {
+		tx, _ := NewTransactionFromRawHex(fixture.raw)
+		txs.Append(tx)
+	}
+	txs.Append(NewTransaction(0, testAddress, NewBigInt(0), 21000, NewBigInt(1), nil))
+
+	senders, err := txs.RecoverSenders(NewBigInt(1))
+	if err == nil {
+		t.Fatal("expected error for unsigned transaction")
+	}
+	if senders.Size() != txs.Size() {
+		t.Fatalf("sender count mismatch: have %d, want %d", senders.Size(), txs.Size())
+	}
+	for i := range testTxFixtures {
+		if sender, _ := senders.Get(i); sender.GetHex() != testAddress.GetHex() {
+			t.Errorf("tx %d: sender mismatch: have %s, want %s", i, sender.GetHex(), testAddress.GetHex())
+		}
+	}
+	if sender, _ := senders.Get(len(testTxFixtures)); sender.address != (common.Address{}) {
+		t.Errorf("unsigned tx: sender mismatch: have %s, want zero address", sender.GetHex())
+	}
+}
+
+func BenchmarkTransactionsRecoverSenders(b *testing.B) {
+	key, _ := crypto.HexToECDSA(testKeyHex)
+	signer := types.LatestSignerForChainID(big.NewInt(1))
+
+	raws := make([][]byte, 500)
+	for i := range raws {
+		tx := types.MustSignNewTx(key, signer, &types.LegacyTx{Nonce: uint64(i), Gas: 21000, GasPrice: big.NewInt(1)})
+		raws[i], _ = tx.MarshalBinary()
+	}
+	// decode creates fresh transactions without any cached senders
+	decode := func() *Transactions {
+		txs := NewTransactions()
+		for _, raw := range raws {
+			tx, _ := NewTransactionFromBinary(raw)
+			txs.Append(tx)
+		}
+		return txs
+	}
+	b.Run("serial", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			txs := decode()
+			b.StartTimer()
+			for j := 0; j < txs.Size(); j++ {
+				tx, _ := txs.Get(j)
+				tx.GetFrom(NewBigInt(1))
+			}
+		}
+	})
+	b.Run("parallel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			txs := decode()
+			b.StartTimer()
+			txs.RecoverSenders(NewBigInt(1))
+		}
+	})
+}
+
+func TestTransactionChainID(t *testing.T) {
+	homestead, _ := NewTransactionFromRawHex(testHomesteadTx)
+	if homestead.IsProtected() {
+		t.Error("homestead transaction reported as protected")
+	}
+	if id := homestead.GetChainID(); id != nil {
+		t.Errorf("homestead chain ID mismatch: have %v, want nil", id)
+	}
+	for i, fixture := range testTxFixtures {
+		tx, _ := NewTransactionFromJSON(fixture.json)
+		if !tx.IsProtected() {
+			t.Errorf("fixture %d: not reported as protected", i)
+		}
+		if id := tx.GetChainID(); id == nil || id.GetInt64() != 1 {
+			t.Errorf("fixture %d: chain ID mismatch: have %v, want 1", i, id)
+		}
+	}
+	signed, _ := SignTransaction(NewTransaction(0, testAddress, NewBigInt(0), 21000, NewBigInt(1), nil), testKeyHex, NewBigInt(1337))
+	if id := signed.GetChainID(); id == nil || id.GetInt64() != 1337 {
+		t.Errorf("signed chain ID mismatch: have %v, want 1337", id)
+	}
+}
+
+func TestNewContractCreation(t *testing.T) {
+	code := []byte{0x60, 0x80, 0x60, 0x40}
+	txs := []*Transaction{
+		NewContractCreation(1, NewBigInt(0), 100000, NewBigInt(1), code),
+		newTestDynamicFeeCreation(NewBigInt(1), 1, NewBigInt(0), 100000, NewBigInt(1), NewBigInt(2), code),
+	}
+	for i, tx := range txs {
+		tx, _ = SignTransaction(tx, testKeyHex, NewBigInt(1))
+		if tx.GetTo() != nil || !tx.IsContractCreation() {
+			t.Errorf("tx %d: recipient set on contract creation", i)
+		}
+		blob, _ := tx.EncodeRLP()
+		dec, err := NewTransactionFromRLP(blob)
+		if err != nil {
+			t.Fatalf("tx %d: %v", i, err)
+		}
+		if dec.GetTo() != nil || !dec.IsContractCreation() {
+			t.Errorf("tx %d: RLP round-trip set a recipient", i)
+		}
+		data, _ := tx.EncodeJSON()
+		if dec, err = NewTransactionFromJSON(data); err != nil {
+			t.Fatalf("tx %d: %v", i, err)
+		}
+		if dec.GetTo() != nil || !dec.IsContractCreation() {
+			t.Errorf("tx %d: JSON round-trip set a recipient", i)
+		}
+	}
+}
+
+func TestTransactionSignerKind(t *testing.T) {
+	tests := []struct {
+		raw     string
+		kind    string
+		chainID int64 // -1 for unprotected transactions
+	}{
+		{testHomesteadTx, "homestead", -1},
+		{testTxFixtures[0].raw, "eip155", 1},
+		{testTxFixtures[1].raw, "eip2930", 1},
+		{testTxFixtures[2].raw, "eip1559", 1},
+	}
+	for _, tt := range tests {
+		tx, err := NewTransactionFromRawHex(tt.raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if kind := tx.SignerKind(); kind != tt.kind {
+			t.Errorf("signer kind mismatch: have %s, want %s", kind, tt.kind)
+		}
+		id := tx.DeriveChainID()
+		if (tt.chainID < 0 && id != nil) || (tt.chainID >= 0 && (id == nil || id.GetInt64() != tt.chainID)) {
+			t.Errorf("%s: chain ID mismatch: have %v, want %d", tt.kind, id, tt.chainID)
+		}
+		from, err := tx.GetSender()
+		if err != nil {
+			t.Fatalf("%s: %v", tt.kind, err)
+		}
+		if from.GetHex() != testAddress.GetHex() {
+			t.Errorf("%s: sender mismatch: have %s, want %s", tt.kind, from.GetHex(), testAddress.GetHex())
+		}
+	}
+}
+
+// Blob transaction in the pooled-without-sidecar (block) form, carrying two
+// versioned hashes and signed by testKeyHex for chain 1.
+const (
+	testBlobTx     = "0x03f8b40107843b9aca008506fc23ac0082520894095e7baea6a6c7c4c2dfeb977efac326af552d878080c084b2d05e00f842a00100000000000000000000000000000000000000000000000000000000000001a0010000000000000000000000000000000000000000000000000000000000000201a0a04f6acefa2f81f1513fc605d1329e868ef5052efd642f373677ec5c491f4238a041e94600ca0b67b91d5355f81187f3a7f26496761ece8fe3171e25ff27c2c600"
+	testBlobTxHash = "0x35989f5bb2329167126bc0bba36618d66f55f67bdc844773ec54a3576b50067e"
+)
+
+func TestBlobTransaction(t *testing.T) {
+	tx, err := NewTransactionFromRawHex(testBlobTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.GetType() != TxTypeBlob || tx.SignerKind() != "eip4844" {
+		t.Errorf("type mismatch: have %d (%s), want %d", tx.GetType(), tx.SignerKind(), TxTypeBlob)
+	}
+	if tx.GetHash().GetHex() != testBlobTxHash {
+		t.Errorf("hash mismatch: have %s, want %s", tx.GetHash().GetHex(), testBlobTxHash)
+	}
+	if fee := tx.GetBlobGasFeeCap(); fee == nil || fee.GetInt64() != 3000000000 {
+		t.Errorf("blob fee cap mismatch: have %v, want 3000000000", fee)
+	}
+	hashes := tx.GetBlobHashes()
+	if hashes.Size() != 2 {
+		t.Fatalf("blob hash count mismatch: have %d, want 2", hashes.Size())
+	}
+	if h, _ := hashes.Get(1); h.GetHex() != "0x0100000000000000000000000000000000000000000000000000000000000002" {
+		t.Errorf("blob hash mismatch: have %s", h.GetHex())
+	}
+	from, err := tx.GetFrom(NewBigInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from.GetHex() != testAddress.GetHex() {
+		t.Errorf("sender mismatch: have %s, want %s", from.GetHex(), testAddress.GetHex())
+	}
+	blob, _ := tx.EncodeRLP()
+	dec, err := NewTransactionFromRLP(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dec.GetHash().GetHex() != testBlobTxHash {
+		t.Errorf("RLP round-trip hash mismatch: have %s, want %s", dec.GetHash().GetHex(), testBlobTxHash)
+	}
+	data, _ := tx.EncodeJSON()
+	if dec, err = NewTransactionFromJSON(data); err != nil {
+		t.Fatal(err)
+	}
+	if dec.GetHash().GetHex() != testBlobTxHash {
+		t.Errorf("JSON round-trip hash mismatch: have %s, want %s", dec.GetHash().GetHex(), testBlobTxHash)
+	}
+	legacy, _ := NewTransactionFromRawHex(testTxFixtures[0].raw)
+	if legacy.GetBlobGasFeeCap() != nil || legacy.GetBlobHashes().Size() != 0 {
+		t.Error("blob fields reported for legacy transaction")
+	}
+}
+
+// Legacy transfer of testTxFixtures[0] as returned by eth_getTransactionByHash,
+// mined at index 3 of block 100.
+const testRPCTx = `{
+	"blockHash": "0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b",
+	"blockNumber": "0x64",
+	"from": "0x71562b71999873db5b286df957af199ec94617f7",
+	"gas": "0x5208",
+	"gasPrice": "0x4a817c800",
+	"hash": "0x591e110f74454e49034ee6c460be97a0ce13d87f8e567b1594e2f35e6de60a07",
+	"input": "0x",
+	"nonce": "0x0",
+	"to": "0x095e7baea6a6c7c4c2dfeb977efac326af552d87",
+	"transactionIndex": "0x3",
+	"value": "0xde0b6b3a7640000",
+	"type": "0x0",
+	"chainId": "0x1",
+	"v": "0x26",
+	"r": "0x196ad7f31265d613108a252adb7f03962be761ac0c4625d3a10530b7681e5aa1",
+	"s": "0x133bf90a8bb0d6141260e4b95725616074949d5c11b9a0a1b48a360ade7c6d53"
+}`
+
+func TestTransactionRPCJSON(t *testing.T) {
+	tx, err := NewTransactionFromRPCJSON(testRPCTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.GetHash().GetHex() != testTxFixtures[0].hash {
+		t.Fatalf("hash mismatch: have %s, want %s", tx.GetHash().GetHex(), testTxFixtures[0].hash)
+	}
+	blockHash, _ := NewHashFromHex("0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b")
+	data, err := tx.EncodeRPCJSON(NewBigInt(1), blockHash, 100, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var have, want map[string]interface{}
+	json.Unmarshal([]byte(data), &have)
+	json.Unmarshal([]byte(testRPCTx), &want)
+	for key, value := range want {
+		if have[key] != value {
+			t.Errorf("field %s mismatch: have %v, want %v", key, have[key], value)
+		}
+	}
+	// Pending transactions have no block context, dynamic fee ones a gas price
+	dynamic, _ := NewTransactionFromRawHex(testTxFixtures[2].raw)
+	if data, err = dynamic.EncodeRPCJSON(NewBigInt(1), nil, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	have = nil
+	json.Unmarshal([]byte(data), &have)
+	if have["blockHash"] != nil || have["blockNumber"] != nil || have["transactionIndex"] != nil {
+		t.Errorf("pending transaction has block context: %s", data)
+	}
+	if have["gasPrice"] != "0x6fc23ac00" || have["from"] != "0x71562b71999873db5b286df957af199ec94617f7" {
+		t.Errorf("unexpected pending fields: %s", data)
+	}
+	if _, err := NewTransactionFromRPCJSON(strings.Replace(testRPCTx, `"nonce": "0x0"`, `"nonce": "0x1"`, 1)); err == nil {
+		t.Error("expected error for mismatching hash")
+	}
+}
+
+func TestTransactionsSort(t *testing.T) {
+	otherKey := "8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a"
+	sign := func(tx *Transaction, key string) *Transaction {
+		signed, err := SignTransaction(tx, key, NewBigInt(1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+	var (
+		a0 = sign(NewTransaction(0, testAddress, NewBigInt(0), 21000, NewBigInt(25), nil), testKeyHex)
+		a1 = sign(newTestDynamicFeeTx(NewBigInt(1), 1, testAddress, NewBigInt(0), 21000, NewBigInt(50), NewBigInt(100), nil), testKeyHex)
+		a2 = sign(NewTransaction(2, testAddress, NewBigInt(0), 21000, NewBigInt(5), nil), testKeyHex)
+		b0 = sign(newTestDynamicFeeTx(NewBigInt(1), 0, testAddress, NewBigInt(0), 21000, NewBigInt(20), NewBigInt(100), nil), otherKey)
+		b1 = sign(NewTransaction(1, testAddress, NewBigInt(0), 21000, NewBigInt(30), nil), otherKey)
+	)
+	check := func(txs *Transactions, want ...*Transaction) {
+		t.Helper()
+		for i, tx := range want {
+			if have, _ := txs.Get(i); have.GetHash().GetHex() != tx.GetHash().GetHex() {
+				t.Errorf("position %d: have nonce %d, want nonce %d", i, have.GetNonce(), tx.GetNonce())
+			}
+		}
+	}
+	shuffled := func() *Transactions {
+		txs := NewTransactions()
+		for _, tx := range []*Transaction{a2, b1, a0, b0, a1} {
+			txs.Append(tx)
+		}
+		return txs
+	}
+	txs := shuffled()
+	txs.SortByNonce()
+	check(txs, a0, b0, b1, a1, a2)
+
+	txs = shuffled()
+	if err := txs.SortByPriceAndNonce(nil); err != nil {
+		t.Fatal(err)
+	}
+	check(txs, a0, a1, b0, b1, a2)
+
+	// A base fee of 10 lowers the legacy tips below the tip of b0
+	txs = shuffled()
+	if err := txs.SortByPriceAndNonce(NewBigInt(10)); err != nil {
+		t.Fatal(err)
+	}
+	check(txs, b0, b1, a0, a1, a2)
+
+	// Sorting the transactions of a block must not reorder the block
+	header := &Header{&types.Header{Number: big.NewInt(10), Difficulty: big.NewInt(1)}}
+	block, err := NewBlock(header, shuffled(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block.GetTransactions().SortByNonce()
+	if err := block.GetTransactions().SortByPriceAndNonce(nil); err != nil {
+		t.Fatal(err)
+	}
+	check(block.GetTransactions(), a2, b1, a0, b0, a1)
+	if err := block.VerifyTransactionsRoot(); err != nil {
+		t.Errorf("block corrupted by sorting: %v", err)
+	}
+}
+
+func TestHeaderBaseFee(t *testing.T) {
+	frontier := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(1)}
+	london := &types.Header{Number: big.NewInt(12965000), Difficulty: big.NewInt(1), BaseFee: big.NewInt(1000000000)}
+
+	for _, header := range []*types.Header{frontier, london} {
+		blob, _ := (&Header{header}).EncodeRLP()
+		dec, err := NewHeaderFromRLP(blob)
+		if err != nil {
+			t.Fatalf("block %d: %v", header.Number, err)
+		}
+		if dec.GetHash().GetHex() != header.Hash().Hex() {
+			t.Errorf("block %d: RLP round-trip hash mismatch", header.Number)
+		}
+		data, _ := dec.EncodeJSON()
+		if dec, err = NewHeaderFromJSON(data); err != nil {
+			t.Fatalf("block %d: %v", header.Number, err)
+		}
+		fee := dec.GetBaseFee()
+		if header.BaseFee == nil && fee != nil {
+			t.Errorf("block %d: base fee mismatch: have %v, want nil", header.Number, fee)
+		}
+		if header.BaseFee != nil && (fee == nil || fee.bigint.Cmp(header.BaseFee) != 0) {
+			t.Errorf("block %d: base fee mismatch: have %v, want %v", header.Number, fee, header.BaseFee)
+		}
+		if block := (&Block{block: types.NewBlockWithHeader(header)}); (block.GetBaseFee() == nil) != (header.BaseFee == nil) {
+			t.Errorf("block %d: block base fee mismatch", header.Number)
+		}
+	}
+}
+
+func TestBigIntGettersIndependent(t *testing.T) {
+	header := &Header{&types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(131072), BaseFee: big.NewInt(1000000000)}}
+	headerRLP, _ := header.EncodeRLP()
+	for _, value := range []*BigInt{header.GetDifficulty(), header.GetBaseFee(), header.GetNumberBig()} {
+		value.SetInt64(7)
+		value.SetBytes([]byte{0xff, 0xff})
+	}
+	if blob, _ := header.EncodeRLP(); !bytes.Equal(blob, headerRLP) {
+		t.Errorf("header modified through getter:\nhave %x\nwant %x", blob, headerRLP)
+	}
+	chainID := NewBigInt(1)
+	tx := newTestDynamicFeeTx(chainID, 0, testAddress, NewBigInt(1000), 21000, NewBigInt(2), NewBigInt(30), nil)
+	txRLP, _ := tx.EncodeRLP()
+	for _, value := range []*BigInt{tx.GetValue(), tx.GetGasPrice(), tx.GetGasTipCap(), tx.GetGasFeeCap(), tx.GetChainID(), tx.GetCost()} {
+		value.SetInt64(7)
+	}
+	if blob, _ := tx.EncodeRLP(); !bytes.Equal(blob, txRLP) {
+		t.Errorf("transaction modified through getter:\nhave %x\nwant %x", blob, txRLP)
+	}
+	msg := NewCallMsg()
+	value := NewBigInt(5)
+	msg.SetValue(value)
+	value.SetInt64(6)
+	msg.GetValue().SetInt64(7)
+	if have := msg.GetValue().GetInt64(); have != 5 {
+		t.Errorf("call value mismatch: have %d, want 5", have)
+	}
+	list := NewBigIntsEmpty()
+	list.Append(value)
+	value.SetInt64(8)
+	first, _ := list.Get(0)
+	first.SetInt64(9)
+	if first, _ = list.Get(0); first.GetInt64() != 6 {
+		t.Errorf("list entry mismatch: have %d, want 6", first.GetInt64())
+	}
+}
+
+func TestHeaderGetNumber(t *testing.T) {
+	// Pending headers served by some nodes have no number, which the JSON decoder
+	// rejects, but headers built in memory may lack one too
+	data, _ := (&Header{&types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(1)}}).EncodeJSON()
+	if !strings.Contains(data, `"number":"0x1"`) {
+		t.Fatalf("unexpected header encoding: %s", data)
+	}
+	data = strings.Replace(data, `"number":"0x1"`, `"number":null`, 1)
+	if _, err := NewHeaderFromJSON(data); err == nil || !strings.Contains(err.Error(), "number") {
+		t.Errorf("missing number decode error mismatch: %v", err)
+	}
+	pending := &Header{new(types.Header)}
+	if _, err := pending.GetNumber(); err == nil {
+		t.Error("expected error for missing number")
+	}
+	if pending.GetNumberBig() != nil {
+		t.Error("expected nil big number for missing number")
+	}
+	huge := new(big.Int).Lsh(big.NewInt(1), 64)
+	header := &Header{&types.Header{Number: huge}}
+	if _, err := header.GetNumber(); err == nil {
+		t.Error("expected error for number overflowing int64")
+	}
+	if header.GetNumberBig().bigint.Cmp(huge) != 0 {
+		t.Errorf("big number mismatch: have %v, want %v", header.GetNumberBig(), huge)
+	}
+	block := &Block{block: types.NewBlockWithHeader(&types.Header{Number: big.NewInt(42)})}
+	if number, err := block.GetNumber(); err != nil || number != 42 {
+		t.Errorf("block number mismatch: have %d (%v), want 42", number, err)
+	}
+	number := block.GetNumberBig()
+	number.SetInt64(7)
+	if block.GetNumberBig().GetInt64() != 42 {
+		t.Errorf("block number modified through GetNumberBig: have %v", block.GetNumberBig())
+	}
+	// A missing block number is no internal error. Blocks are built from copies
+	// of their headers, which go-ethereum numbers zero.
+	before := GetInternalErrorCount()
+	pendingBlock := &Block{block: types.NewBlockWithHeader(new(types.Header))}
+	if number := pendingBlock.GetNumberBig(); number != nil && number.Sign() != 0 {
+		t.Errorf("missing block number mismatch: have %v, want nil or 0", number)
+	}
+	if have := GetInternalErrorCount(); have != before {
+		t.Errorf("internal error count mismatch: have %d, want %d", have, before)
+	}
+}
+
+func TestHeaderBuilder(t *testing.T) {
+	parent, _ := NewHashFromHex("0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b")
+	root, _ := NewHashFromHex("0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+	digest, _ := NewHashFromHex("0x0100000000000000000000000000000000000000000000000000000000000000")
+
+	header := NewEmptyHeader()
+	header.SetParentHash(parent)
+	header.SetCoinbase(testAddress)
+	header.SetRoot(root)
+	header.SetNumber(1234)
+	header.SetGasLimit(30000000)
+	header.SetGasUsed(21000)
+	header.SetTime(1700000000)
+	header.SetExtra([]byte("web3go"))
+	header.SetDifficulty(NewBigInt(131072))
+	header.SetBaseFee(NewBigInt(7))
+	header.SetMixDigest(digest)
+	header.SetNonce(&Nonce{types.EncodeNonce(42)})
+
+	blob, err := header.EncodeRLP()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec, err := NewHeaderFromRLP(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	number, _ := dec.GetNumber()
+	switch {
+	case dec.GetParentHash().GetHex() != parent.GetHex():
+		t.Error("parent hash mismatch")
+	case dec.GetCoinbase().GetHex() != testAddress.GetHex():
+		t.Error("coinbase mismatch")
+	case dec.GetRoot().GetHex() != root.GetHex():
+		t.Error("root mismatch")
+	case number != 1234:
+		t.Errorf("number mismatch: have %d, want 1234", number)
+	case dec.GetGasLimit() != 30000000 || dec.GetGasUsed() != 21000:
+		t.Error("gas mismatch")
+	case dec.GetTime() != 1700000000:
+		t.Error("time mismatch")
+	case string(dec.GetExtra()) != "web3go":
+		t.Error("extra mismatch")
+	case dec.GetDifficulty().GetInt64() != 131072:
+		t.Error("difficulty mismatch")
+	case dec.GetBaseFee() == nil || dec.GetBaseFee().GetInt64() != 7:
+		t.Error("base fee mismatch")
+	case dec.GetMixDigest().GetHex() != digest.GetHex():
+		t.Error("mix digest mismatch")
+	case dec.GetNonce().GetHex() != "0x000000000000002a":
+		t.Errorf("nonce mismatch: have %s", dec.GetNonce().GetHex())
+	case dec.GetHash().GetHex() != header.GetHash().GetHex():
+		t.Error("hash mismatch")
+	}
+	// Mutating a copy must change its hash, but leave the original intact
+	hash := header.GetHash().GetHex()
+	cpy := header.Copy()
+	cpy.SetExtra([]byte("tweaked"))
+	if cpy.GetHash().GetHex() == hash {
+		t.Error("hash not updated after mutation")
+	}
+	if header.GetHash().GetHex() != hash || string(header.GetExtra()) != "web3go" {
+		t.Error("copy mutation leaked into the original")
+	}
+	// Nil arguments are ignored, or clear the big int fields
+	header.SetParentHash(nil)
+	header.SetCoinbase(nil)
+	header.SetRoot(nil)
+	header.SetMixDigest(nil)
+	header.SetNonce(nil)
+	if header.GetHash().GetHex() != hash {
+		t.Error("nil argument changed the header")
+	}
+	header.SetDifficulty(nil)
+	header.SetBaseFee(nil)
+	if header.GetDifficulty() != nil || header.GetBaseFee() != nil {
+		t.Error("nil big int not cleared")
+	}
+}
+
+func TestBlockUnclesCopied(t *testing.T) {
+	uncles := NewHeaders()
+	uncles.Append(NewEmptyHeader())
+	block, err := NewBlock(NewEmptyHeader(), NewTransactions(), uncles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edit := func(headers *Headers) {
+		uncle, _ := headers.Get(0)
+		uncle.SetTime(1700000000)
+		uncle.SetExtra([]byte("tweaked"))
+		uncle.SetNumber(7)
+	}
+	edit(block.GetUncles())
+	edit(block.GetBody().GetUncles())
+	if err := block.VerifyBody(); err != nil {
+		t.Errorf("block uncles changed by header setters: %v", err)
+	}
+	if uncle, _ := block.GetUncles().Get(0); uncle.GetTime() != 0 || len(uncle.GetExtra()) != 0 {
+		t.Error("uncle header modified")
+	}
+}
+
+func TestHeadersEncoding(t *testing.T) {
+	headers := NewHeaders()
+	for i := int64(0); i < 3; i++ {
+		header := NewEmptyHeader()
+		header.SetNumber(i)
+		if i > 0 {
+			header.SetBaseFee(NewBigInt(i))
+		}
+		headers.Append(header)
+	}
+	if err := headers.Append(nil); err == nil {
+		t.Error("expected error for nil header")
+	}
+	blob, err := headers.EncodeRLP()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromRLP, err := NewHeadersFromRLP(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := headers.EncodeJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromJSON, err := NewHeadersFromJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, dec := range []*Headers{fromRLP, fromJSON} {
+		if dec.Size() != headers.Size() {
+			t.Fatalf("size mismatch: have %d, want %d", dec.Size(), headers.Size())
+		}
+		for i := 0; i < headers.Size(); i++ {
+			have, _ := dec.Get(i)
+			want, _ := headers.Get(i)
+			if have.GetHash().GetHex() != want.GetHash().GetHex() {
+				t.Errorf("header %d: hash mismatch", i)
+			}
+		}
+	}
+	// Malformed elements are reported by index
+	if _, err := NewHeadersFromJSON(`[` + strings.Trim(data, "[]") + `,{"number":"0x3"}]`); err == nil || !strings.HasPrefix(err.Error(), "header 3:") {
+		t.Errorf("unexpected JSON error: %v", err)
+	}
+	malformed, _ := rlp.EncodeToBytes([]interface{}{[]uint{1, 2}})
+	if _, err := NewHeadersFromRLP(malformed); err == nil || !strings.HasPrefix(err.Error(), "header 0:") {
+		t.Errorf("unexpected RLP error: %v", err)
+	}
+	if data, _ := NewHeaders().EncodeJSON(); data != "[]" {
+		t.Errorf("empty encoding mismatch: have %s, want []", data)
+	}
+}
+
+
+func TestHeadersAppendCopies(t *testing.T) {
+	headers := NewHeaders()
+	parent := NewEmptyHeader()
+	parent.SetNumber(1)
+	headers.Append(parent)
+	child := NewEmptyHeader()
+	child.SetNumber(2)
+	child.SetParentHash(parent.GetHash())
+	headers.Append(child)
+	blob, _ := headers.EncodeRLP()
+
+	// Changing the appended headers must not modify the slice
+	parent.SetNumber(5)
+	child.SetNumber(7)
+	if stored, _ := headers.Get(1); stored.GetNumberBig().GetInt64() != 2 {
+		t.Errorf("stored header modified: have number %v, want 2", stored.GetNumberBig())
+	}
+	if have, _ := headers.EncodeRLP(); !bytes.Equal(have, blob) {
+		t.Error("encoding modified by changing an appended header")
+	}
+	if err := headers.ValidateChain(); err != nil {
+		t.Errorf("chain modified by changing an appended header: %v", err)
+	}
+}
+
+func TestBlockSizes(t *testing.T) {
+	txs, _ := NewTransactionsFromJSON("[" + testTxFixtures[0].json + "," + testTxFixtures[2].json + "]")
+	uncles := NewHeaders()
+	uncles.Append(NewEmptyHeader())
+	header := NewEmptyHeader()
+	header.SetNumber(10)
+	block, err := NewBlock(header, txs, uncles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc, _ := block.EncodeRLP()
+	if size := block.GetSerializedSize(); size != int64(len(enc)) {
+		t.Errorf("block size mismatch: have %d, want %d", size, len(enc))
+	}
+	head, _ := block.GetHeader().EncodeRLP()
+	if size := block.GetHeaderSize(); size != int64(len(head)) {
+		t.Errorf("header size mismatch: have %d, want %d", size, len(head))
+	}
+	body, _ := rlp.EncodeToBytes(block.block.Body())
+	if size := block.GetBodySize(); size != int64(len(body)) {
+		t.Errorf("body size mismatch: have %d, want %d", size, len(body))
+	}
+}
+
+func TestBlockTransactionIndex(t *testing.T) {
+	txs, _ := NewTransactionsFromJSON("[" + testTxFixtures[0].json + "," + testTxFixtures[1].json + "," + testTxFixtures[2].json + "]")
+	block, err := NewBlock(NewEmptyHeader(), txs, NewHeaders())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block.GetTransactionCount() != 3 {
+		t.Fatalf("transaction count mismatch: have %d, want 3", block.GetTransactionCount())
+	}
+	for i, fixture := range testTxFixtures {
+		tx, err := block.GetTransactionByIndex(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tx.GetHash().GetHex() != fixture.hash {
+			t.Errorf("transaction %d: hash mismatch: have %s, want %s", i, tx.GetHash().GetHex(), fixture.hash)
+		}
+		if index, err := block.GetTransactionIndex(tx.GetHash()); err != nil || index != i {
+			t.Errorf("transaction %d: index mismatch: have %d (%v)", i, index, err)
+		}
+		if tx, err = block.GetTransaction(tx.GetHash()); err != nil || tx.GetHash().GetHex() != fixture.hash {
+			t.Errorf("transaction %d: lookup mismatch: %v", i, err)
+		}
+	}
+	if _, err := block.GetTransactionByIndex(3); err == nil {
+		t.Error("expected error for index out of bounds")
+	}
+	missing, _ := NewHashFromHex(testBlobTxHash)
+	if _, err := block.GetTransactionIndex(missing); err == nil {
+		t.Error("expected error for missing transaction index")
+	}
+	if tx, err := block.GetTransaction(missing); err == nil || tx != nil {
+		t.Error("expected error for missing transaction")
+	}
+}
+
+func TestBlockFees(t *testing.T) {
+	// Legacy transaction paying 20 gwei and a dynamic fee one paying 1.5 gwei tip
+	txs, _ := NewTransactionsFromJSON("[" + testTxFixtures[0].json + "," + testTxFixtures[2].json + "]")
+	receipts := NewReceipts()
+	for i := 0; i < txs.Size(); i++ {
+		tx, _ := txs.Get(i)
+		receipts.Append(&Receipt{&types.Receipt{TxHash: tx.tx.Hash(), GasUsed: 21000}})
+	}
+	header := NewEmptyHeader()
+	header.SetBaseFee(NewBigInt(10000000000))
+	london, _ := NewBlock(header, txs, NewHeaders())
+
+	burnt, err := london.GetBurntFees(receipts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "420000000000000"; burnt.String() != want {
+		t.Errorf("burnt fees mismatch: have %s, want %s", burnt, want)
+	}
+	total, err := london.GetTotalFees(receipts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "661500000000000"; total.String() != want { // 21000 * (20 gwei + 11.5 gwei)
+		t.Errorf("total fees mismatch: have %s, want %s", total, want)
+	}
+	// Receipts reporting an effective gas price take precedence
+	first, _ := receipts.Get(0)
+	first.receipt.EffectiveGasPrice = big.NewInt(1)
+	if total, _ = london.GetTotalFees(receipts); total.String() != "241500000021000" {
+		t.Errorf("total fees mismatch: have %s, want 241500000021000", total)
+	}
+	header.SetBaseFee(nil)
+	frontier, _ := NewBlock(header, txs, NewHeaders())
+	if burnt, err = frontier.GetBurntFees(receipts); err != nil || burnt.GetInt64() != 0 {
+		t.Errorf("pre-London burnt fees mismatch: have %v (%v), want 0", burnt, err)
+	}
+	// Receipts must match the block transactions
+	if _, err := london.GetBurntFees(NewReceipts()); err == nil {
+		t.Error("expected error for receipt count mismatch")
+	}
+	first.receipt.TxHash = common.Hash{}
+	if _, err := london.GetTotalFees(receipts); err == nil {
+		t.Error("expected error for transaction hash mismatch")
+	}
+}
+
+func TestBlockWithBody(t *testing.T) {
+	txs, _ := NewTransactionsFromJSON("[" + testTxFixtures[0].json + "," + testTxFixtures[1].json + "]")
+	uncles := NewHeaders()
+	uncles.Append(NewEmptyHeader())
+
+	header := NewEmptyHeader()
+	header.SetNumber(5)
+	block := NewBlockWithHeader(header).WithBody(txs, uncles)
+	if err := block.VerifyBody(); err == nil {
+		t.Error("expected error for body not matching the header roots")
+	}
+	// Assembling through NewBlock fixes up the roots
+	built, _ := NewBlock(header, txs, uncles)
+	block = NewBlockWithHeader(built.GetHeader()).WithBody(txs, uncles)
+	if err := block.VerifyBody(); err != nil {
+		t.Fatal(err)
+	}
+	if block.GetTxHash().GetHex() != built.GetTxHash().GetHex() || block.GetUncleHash().GetHex() != built.GetUncleHash().GetHex() {
+		t.Error("roots not taken from the header")
+	}
+	enc, err := block.EncodeRLP()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec, err := NewBlockFromRLP(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dec.GetHash().GetHex() != block.GetHash().GetHex() || dec.GetTransactionCount() != 2 || dec.GetUncles().Size() != 1 {
+		t.Error("RLP round-trip mismatch")
+	}
+	if err := dec.VerifyBody(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBodyRLP(t *testing.T) {
+	txs, _ := NewTransactionsFromJSON("[" + testTxFixtures[0].json + "," + testTxFixtures[2].json + "]")
+	uncles := NewHeaders()
+	uncles.Append(NewEmptyHeader())
+	block, _ := NewBlock(NewEmptyHeader(), txs, uncles)
+
+	enc, err := block.GetBody().EncodeRLP()
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := NewBodyFromRLP(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body.GetTransactions().Size() != 2 || body.GetUncles().Size() != 1 {
+		t.Fatalf("body content mismatch: %d transactions, %d uncles", body.GetTransactions().Size(), body.GetUncles().Size())
+	}
+	for i := 0; i < 2; i++ {
+		have, _ := body.GetTransactions().Get(i)
+		want, _ := txs.Get(i)
+		if have.GetHash().GetHex() != want.GetHash().GetHex() {
+			t.Errorf("transaction %d: hash mismatch", i)
+		}
+	}
+	reenc, _ := body.EncodeRLP()
+	if !bytes.Equal(enc, reenc) {
+		t.Error("RLP round-trip mismatch")
+	}
+	// The body must be usable to rebuild the block from its header
+	rebuilt := NewBlockWithHeader(block.GetHeader()).WithBody(body.GetTransactions(), body.GetUncles())
+	if err := rebuilt.VerifyBody(); err != nil {
+		t.Error(err)
+	}
+	full, _ := block.EncodeRLP()
+	if _, err := NewBodyFromRLP(full); err == nil {
+		t.Error("expected error for full block data")
+	}
+}
+
+func TestBlockNonce(t *testing.T) {
+	header := NewEmptyHeader()
+	header.SetNonce(&Nonce{types.EncodeNonce(0xa13b7d1f5e9a6c42)})
+	block := NewBlockWithHeader(header)
+
+	nonce := block.GetNonceObj()
+	if nonce.GetHex() != "0xa13b7d1f5e9a6c42" {
+		t.Errorf("nonce hex mismatch: have %s, want 0xa13b7d1f5e9a6c42", nonce.GetHex())
+	}
+	if nonce.GetUint64String() != "11618017237416963138" {
+		t.Errorf("nonce mismatch: have %s, want 11618017237416963138", nonce.GetUint64String())
+	}
+	if nonce.GetHex() != header.GetNonce().GetHex() {
+		t.Error("block and header nonces differ")
+	}
+}
+
+func TestVerifyRoots(t *testing.T) {
+	// Empty blocks and receipt lists hash to the well known empty trie root
+	empty, _ := NewBlock(NewEmptyHeader(), NewTransactions(), NewHeaders())
+	if empty.GetTxHash().GetHex() != types.EmptyRootHash.Hex() {
+		t.Errorf("empty transaction root mismatch: have %s", empty.GetTxHash().GetHex())
+	}
+	if err := empty.VerifyTransactionsRoot(); err != nil {
+		t.Error(err)
+	}
+	root, err := ComputeReceiptsRoot(NewReceipts())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root.GetHex() != types.EmptyRootHash.Hex() {
+		t.Errorf("empty receipt root mismatch: have %s", root.GetHex())
+	}
+	txs, _ := NewTransactionsFromJSON("[" + testTxFixtures[0].json + "," + testTxFixtures[2].json + "]")
+	block, _ := NewBlock(NewEmptyHeader(), txs, NewHeaders())
+	if err := block.VerifyTransactionsRoot(); err != nil {
+		t.Error(err)
+	}
+	// A body served for another header is reported with both roots
+	forged := NewBlockWithHeader(empty.GetHeader()).WithBody(txs, NewHeaders())
+	err = forged.VerifyTransactionsRoot()
+	if err == nil || !strings.Contains(err.Error(), block.GetTxHash().GetHex()[2:]) || !strings.Contains(err.Error(), types.EmptyRootHash.Hex()[2:]) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	receipts := NewReceipts()
+	receipts.Append(&Receipt{&types.Receipt{Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 21000, Logs: []*types.Log{}}})
+	if root, _ = ComputeReceiptsRoot(receipts); root.GetHex() == types.EmptyRootHash.Hex() {
+		t.Error("receipt root not affected by receipts")
+	}
+}
+
+// testRPCBlock is mainnet block 1 as returned by eth_getBlockByNumber("0x1",
+// false) of a geth node. Its fields hash to the known block hash, so the decoder
+// is checked against a response of a real node.
+const testRPCBlock = `{
+	"difficulty": "0x3ff800000",
+	"extraData": "0x476574682f76312e302e302f6c696e75782f676f312e342e32",
+	"gasLimit": "0x1388",
+	"gasUsed": "0x0",
+	"hash": "0x88e96d4537bea4d9c05d12549907b32561d3bf31f45aae734cdc119f13406cb6",
+	"logsBloom": "0x00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
+	"miner": "0x05a56e2d52c817161883f50c441c3228cfe54d9f",
+	"mixHash": "0x969b900de27b6ac6a67742365dd65f55a0526c41fd18e1b16f1a1215c2e66f59",
+	"nonce": "0x539bd4979fef1ec4",
+	"number": "0x1",
+	"parentHash": "0xd4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3",
+	"receiptsRoot": "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421",
+	"sha3Uncles": "0x1dcc4de8dec75d7aab85b567b6ccd41ad312451b948a7413f0a142fd40d49347",
+	"size": "0x219",
+	"stateRoot": "0xd67e4d450343046425ae4271474353857ab860dbc0a1dde64b41b5cd3a532bf3",
+	"timestamp": "0x55ba4224",
+	"totalDifficulty": "0x7ff800000",
+	"transactions": [],
+	"transactionsRoot": "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421",
+	"uncles": []
+}`
+
+// testGeneratedRPCBlock is a generated post-Shanghai block carrying
+// testTxFixtures[0] and [2] plus one withdrawal, in the format returned by
+// eth_getBlockByNumber without full transactions.
+const testGeneratedRPCBlock = `{
+	"baseFeePerGas": "0x2540be400",
+	"difficulty": "0x0",
+	"extraData": "0x77656233676f",
+	"gasLimit": "0x1c9c380",
+	"gasUsed": "0xa410",
+	"hash": "0x4d8161716d6ae2aeb43a736b43e6bf4924655f68c9758db604f8d4cacd8ce9ef",
+	"logsBloom": "0x00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
+	"miner": "0x71562b71999873db5b286df957af199ec94617f7",
+	"mixHash": "0x0000000000000000000000000000000000000000000000000000000000000000",
+	"nonce": "0x0000000000000000",
+	"number": "0x103ee76",
+	"parentHash": "0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b",
+	"receiptsRoot": "0x1c3a2a4e1b1f0ffc3dfa3aef6c7f0a16f43a6b2d79ad1c62bb3e7d1ec7a0ff3e",
+	"sha3Uncles": "0x1dcc4de8dec75d7aab85b567b6ccd41ad312451b948a7413f0a142fd40d49347",
+	"size": "0x32d",
+	"stateRoot": "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421",
+	"timestamp": "0x64373057",
+	"totalDifficulty": "0xc70d815d562d3cfa955",
+	"transactions": [
+		"0x591e110f74454e49034ee6c460be97a0ce13d87f8e567b1594e2f35e6de60a07",
+		"0x7122fc4b027fac60b68ee8a94e6dcccdca66f8e373abda5dcf7bda310ac81230"
+	],
+	"transactionsRoot": "0xde8f69596d03def3816379c8052d9f499114d198f60a97ad62d8a6fd248ccdcc",
+	"uncles": [],
+	"withdrawals": [
+		{"index": "0x7", "validatorIndex": "0x2a", "address": "0x095e7baea6a6c7c4c2dfeb977efac326af552d87", "amount": "0x3e8"}
+	],
+	"withdrawalsRoot": "0x2baf8e27031074639c66d499bd17119affac14713d882edbb3d32418e2dad5b9"
+}`
+
+func TestBlockRPCJSON(t *testing.T) {
+	block, err := NewBlockFromRPCJSON(testRPCBlock, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block.GetHash().GetHex() != "0x88e96d4537bea4d9c05d12549907b32561d3bf31f45aae734cdc119f13406cb6" {
+		t.Errorf("hash mismatch: have %s", block.GetHash().GetHex())
+	}
+	if number, err := block.GetNumber(); err != nil || number != 1 {
+		t.Errorf("number mismatch: have %d (%v), want 1", number, err)
+	}
+	if td := block.GetTotalDifficulty(); td == nil || td.String() != "34351349760" {
+		t.Errorf("total difficulty mismatch: have %v", td)
+	}
+	if block.GetSize() != 0x219 || block.GetBaseFee() != nil {
+		t.Errorf("size or base fee mismatch: size %d, base fee %v", block.GetSize(), block.GetBaseFee())
+	}
+	if block.GetTransactionHashes().Size() != 0 || block.GetUncleHashes().Size() != 0 {
+		t.Error("empty block has transactions or uncles")
+	}
+	// Blocks without transactions verify, even when decoded from hashes
+	if err := block.VerifyBody(); err != nil {
+		t.Error(err)
+	}
+	data, err := block.EncodeRPCJSON(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var have, want map[string]interface{}
+	json.Unmarshal([]byte(data), &have)
+	json.Unmarshal([]byte(testRPCBlock), &want)
+	for key, value := range want {
+		if fmt.Sprint(have[key]) != fmt.Sprint(value) {
+			t.Errorf("field %s mismatch: have %v, want %v", key, have[key], value)
+		}
+	}
+}
+
+func TestGeneratedBlockRPCJSON(t *testing.T) {
+	block, err := NewBlockFromRPCJSON(testGeneratedRPCBlock, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block.GetHash().GetHex() != "0x4d8161716d6ae2aeb43a736b43e6bf4924655f68c9758db604f8d4cacd8ce9ef" {
+		t.Errorf("hash mismatch: have %s", block.GetHash().GetHex())
+	}
+	if td := block.GetTotalDifficulty(); td == nil || td.String() != "58750003716598352816469" {
+		t.Errorf("total difficulty mismatch: have %v", td)
+	}
+	hashes := block.GetTransactionHashes()
+	if hashes.Size() != 2 || block.GetTransactionCount() != 0 {
+		t.Fatalf("transaction mismatch: %d hashes, %d bodies", hashes.Size(), block.GetTransactionCount())
+	}
+	if hash, _ := hashes.Get(1); hash.GetHex() != testTxFixtures[2].hash {
+		t.Errorf("transaction hash mismatch: have %s", hash.GetHex())
+	}
+	// Hash-only blocks re-encode to the same response, but cannot produce bodies
+	data, err := block.EncodeRPCJSON(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var have, want map[string]interface{}
+	json.Unmarshal([]byte(data), &have)
+	json.Unmarshal([]byte(testGeneratedRPCBlock), &want)
+	for key, value := range want {
+		if fmt.Sprint(have[key]) != fmt.Sprint(value) {
+			t.Errorf("field %s mismatch: have %v, want %v", key, have[key], value)
+		}
+	}
+	if _, err := block.EncodeRPCJSON(true); err == nil {
+		t.Error("expected error for missing transaction bodies")
+	}
+	// Full transaction objects round-trip into a verifiable block
+	txs, _ := NewTransactionsFromJSON("[" + testTxFixtures[0].json + "," + testTxFixtures[2].json + "]")
+	full := block.WithBody(txs, nil)
+	if data, err = full.EncodeRPCJSON(true); err != nil {
+		t.Fatal(err)
+	}
+	dec, err := NewBlockFromRPCJSON(data, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dec.GetHash().GetHex() != block.GetHash().GetHex() || dec.GetTransactionCount() != 2 {
+		t.Errorf("full round-trip mismatch: hash %s, %d transactions", dec.GetHash().GetHex(), dec.GetTransactionCount())
+	}
+	if err := dec.VerifyBody(); err != nil {
+		t.Error(err)
+	}
+	if err := block.VerifyBody(); err == nil || err.Error() != "transaction bodies not available" {
+		t.Errorf("hash-only block verification error mismatch: %v", err)
+	}
+	if _, err := NewBlockFromRPCJSON(strings.Replace(testGeneratedRPCBlock, `"gasUsed": "0xa410"`, `"gasUsed": "0xa411"`, 1), false); err == nil {
+		t.Error("expected error for mismatching block hash")
+	}
+}
+
+func TestBlocksRLP(t *testing.T) {
+	txs, _ := NewTransactionsFromJSON("[" + testTxFixtures[1].json + "]")
+	blocks := NewBlocks()
+	for i := int64(0); i < 3; i++ {
+		header := NewEmptyHeader()
+		header.SetNumber(i)
+		block, _ := NewBlock(header, txs, NewHeaders())
+		blocks.Append(block)
+	}
+	if err := blocks.Append(nil); err == nil {
+		t.Error("expected error for nil block")
+	}
+	enc, err := blocks.EncodeRLP()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec, err := NewBlocksFromRLP(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	have, want := dec.Hashes(), blocks.Hashes()
+	if have.Size() != 3 || want.Size() != 3 {
+		t.Fatalf("size mismatch: have %d, want 3", have.Size())
+	}
+	for i := 0; i < 3; i++ {
+		h, _ := have.Get(i)
+		w, _ := want.Get(i)
+		if h.GetHex() != w.GetHex() {
+			t.Errorf("block %d: hash mismatch", i)
+		}
+	}
+	// Corrupting the last block is reported by index
+	malformed, _ := rlp.EncodeToBytes([]interface{}{blocks.blocks[0], blocks.blocks[1], []uint{1}})
+	if _, err := NewBlocksFromRLP(malformed); err == nil || !strings.HasPrefix(err.Error(), "block 2:") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	empty, _ := NewBlocks().EncodeRLP()
+	if dec, err = NewBlocksFromRLP(empty); err != nil || dec.Size() != 0 {
+		t.Errorf("empty round-trip mismatch: %v", err)
+	}
+}
+
+func TestReceiptJSONRoundTrip(t *testing.T) {
+	contract := common.HexToAddress("0x2139B5Baf855EEE55Cdb5F19dF50583585581EaD")
+	receipt := &Receipt{&types.Receipt{
+		Status:            types.ReceiptStatusSuccessful,
+		CumulativeGasUsed: 84000,
+		TxHash:            common.HexToHash(testTxFixtures[0].hash),
+		ContractAddress:   contract,
+		GasUsed:           63000,
+		Logs: []*types.Log{{
+			Address: contract,
+			Topics:  []common.Hash{common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")},
+			Data:    []byte{0x01},
+		}},
+	}}
+	receipt.receipt.Bloom = types.CreateBloom(types.Receipts{receipt.receipt})
+
+	data, err := receipt.EncodeJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec, err := NewReceiptFromJSON(data)
+	if err != nil {
+		t.Fatalf("%v: %s", err, data)
+	}
+	switch {
+	case dec.GetStatus() != receipt.GetStatus():
+		t.Error("status mismatch")
+	case dec.GetCumulativeGasUsed() != 84000 || dec.GetGasUsed() != 63000:
+		t.Error("gas mismatch")
+	case dec.GetContractAddress().GetHex() != contract.Hex():
+		t.Error("contract address mismatch")
+	case dec.GetTxHash().GetHex() != testTxFixtures[0].hash:
+		t.Error("transaction hash mismatch")
+	case dec.GetLogs().Size() != 1:
+		t.Error("log count mismatch")
+	case dec.GetBloom().GetHex() != receipt.GetBloom().GetHex():
+		t.Error("bloom mismatch")
+	}
+	// Data persisted by the former RLP based encoder is rejected with a hint
+	legacy, _ := receipt.EncodeRLP()
+	if _, err := NewReceiptFromJSON(string(legacy)); err == nil || !strings.Contains(err.Error(), "NewReceiptFromRLP") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// Receipt of testTxFixtures[0] as returned by eth_getTransactionReceipt.
+const testRPCReceipt = `{
+	"blockHash": "0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b",
+	"blockNumber": "0x64",
+	"contractAddress": null,
+	"cumulativeGasUsed": "0xa410",
+	"effectiveGasPrice": "0x4a817c800",
+	"from": "0x71562b71999873db5b286df957af199ec94617f7",
+	"gasUsed": "0x5208",
+	"logs": [],
+	"logsBloom": "0x00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
+	"status": "0x1",
+	"to": "0x095e7baea6a6c7c4c2dfeb977efac326af552d87",
+	"transactionHash": "0x591e110f74454e49034ee6c460be97a0ce13d87f8e567b1594e2f35e6de60a07",
+	"transactionIndex": "0x3",
+	"type": "0x0"
+}`
+
+func TestReceiptBlockContext(t *testing.T) {
+	receipt, err := NewReceiptFromJSON(testRPCReceipt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt.GetBlockHash().GetHex() != "0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b" {
+		t.Errorf("block hash mismatch: have %s", receipt.GetBlockHash().GetHex())
+	}
+	if number, err := receipt.GetBlockNumber(); err != nil || number != 100 {
+		t.Errorf("block number mismatch: have %d (%v), want 100", number, err)
+	}
+	if receipt.GetTransactionIndex() != 3 {
+		t.Errorf("transaction index mismatch: have %d, want 3", receipt.GetTransactionIndex())
+	}
+	// Consensus encoding drops the block context
+	blob, _ := receipt.EncodeRLP()
+	dec, err := NewReceiptFromRLP(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dec.GetBlockHash().GetHex() != (common.Hash{}).Hex() || dec.GetTransactionIndex() != 0 {
+		t.Error("unexpected block context on RLP receipt")
+	}
+	if _, err := dec.GetBlockNumber(); err == nil {
+		t.Error("expected error for missing block number")
+	}
+}
+
+// Consensus encodings of a legacy and a dynamic fee receipt with one log.
+var testReceiptFixtures = []struct {
+	txType int
+	binary string
+}{
+	{TxTypeLegacy, "0xf901430182a410b9010000000000000000001000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000008000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000000040000000000000000000000000000000000000000000000000000000f83af83894095e7baea6a6c7c4c2dfeb977efac326af552d87e1a0ddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef2a"},
+	{TxTypeDynamicFee, "0x02f901430182a410b9010000000000000000001000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000008000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000000040000000000000000000000000000000000000000000000000000000f83af83894095e7baea6a6c7c4c2dfeb977efac326af552d87e1a0ddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef2a"},
+}
+
+func TestReceiptBinary(t *testing.T) {
+	for i, fixture := range testReceiptFixtures {
+		blob := hexutil.MustDecode(fixture.binary)
+		receipt, err := NewReceiptFromBinary(blob)
+		if err != nil {
+			t.Fatalf("fixture %d: %v", i, err)
+		}
+		if receipt.GetType() != fixture.txType {
+			t.Errorf("fixture %d: type mismatch: have %d, want %d", i, receipt.GetType(), fixture.txType)
+		}
+		if receipt.GetStatus() != 1 || receipt.GetCumulativeGasUsed() != 42000 || receipt.GetLogs().Size() != 1 {
+			t.Errorf("fixture %d: content mismatch", i)
+		}
+		enc, err := receipt.EncodeBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hexutil.Encode(enc) != fixture.binary {
+			t.Errorf("fixture %d: binary round-trip mismatch", i)
+		}
+		_, err = NewReceiptFromRLP(blob)
+		if fixture.txType == TxTypeLegacy && err != nil {
+			t.Errorf("fixture %d: legacy receipt rejected: %v", i, err)
+		}
+		if fixture.txType != TxTypeLegacy && (err == nil || !strings.Contains(err.Error(), "NewReceiptFromBinary")) {
+			t.Errorf("fixture %d: unexpected error: %v", i, err)
+		}
+	}
+}
+
+func TestReceiptsDeriveFields(t *testing.T) {
+	deploy, _ := SignTransaction(NewContractCreation(1, NewBigInt(0), 100000, NewBigInt(1), []byte{0x60}), testKeyHex, NewBigInt(1))
+	txs, _ := NewTransactionsFromJSON("[" + testTxFixtures[0].json + "," + testTxFixtures[2].json + "]")
+	txs.Append(deploy)
+
+	header := NewEmptyHeader()
+	header.SetNumber(100)
+	header.SetBaseFee(NewBigInt(10000000000))
+	block, _ := NewBlock(header, txs, NewHeaders())
+
+	// Receipts as decoded from the consensus encoding
+	receipts := NewReceipts()
+	for i, cumulative := range []uint64{21000, 42000, 95000} {
+		logs := []*types.Log{{Topics: []common.Hash{}, Data: []byte{byte(i)}}, {Topics: []common.Hash{}, Data: []byte{byte(i)}}}
+		receipts.Append(&Receipt{&types.Receipt{Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: cumulative, Logs: logs}})
+	}
+	if err := receipts.DeriveFields(block); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < receipts.Size(); i++ {
+		receipt, _ := receipts.Get(i)
+		tx, _ := block.GetTransactionByIndex(i)
+		if receipt.GetTxHash().GetHex() != tx.GetHash().GetHex() || receipt.GetTransactionIndex() != i || receipt.GetType() != tx.GetType() {
+			t.Errorf("receipt %d: transaction fields mismatch", i)
+		}
+		if number, _ := receipt.GetBlockNumber(); number != 100 || receipt.GetBlockHash().GetHex() != block.GetHash().GetHex() {
+			t.Errorf("receipt %d: block fields mismatch", i)
+		}
+		for j, log := range receipt.receipt.Logs {
+			if log.Index != uint(2*i+j) || log.TxIndex != uint(i) || log.TxHash != receipt.receipt.TxHash || log.BlockNumber != 100 {
+				t.Errorf("receipt %d: log %d fields mismatch", i, j)
+			}
+		}
+	}
+	third, _ := receipts.Get(2)
+	if third.GetGasUsed() != 53000 {
+		t.Errorf("gas used mismatch: have %d, want 53000", third.GetGasUsed())
+	}
+	created, _ := deploy.GetCreatedContractAddress(NewBigInt(1))
+	if third.GetContractAddress().GetHex() != created.GetHex() {
+		t.Errorf("contract address mismatch: have %s, want %s", third.GetContractAddress().GetHex(), created.GetHex())
+	}
+	second, _ := receipts.Get(1)
+	if second.receipt.EffectiveGasPrice.Int64() != 11500000000 {
+		t.Errorf("effective gas price mismatch: have %v, want 11500000000", second.receipt.EffectiveGasPrice)
+	}
+	// JSON round-trip keeps the derived fields
+	data, err := receipts.EncodeJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec, err := NewReceiptsFromJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dec.Size() != 3 {
+		t.Fatalf("size mismatch: have %d, want 3", dec.Size())
+	}
+	if last, _ := dec.Get(2); last.GetContractAddress().GetHex() != created.GetHex() || last.GetTransactionIndex() != 2 {
+		t.Error("JSON round-trip lost derived fields")
+	}
+	// Decreasing cumulative gas must fail instead of wrapping around
+	prev, cur := receipts.receipts[0].CumulativeGasUsed, receipts.receipts[1].CumulativeGasUsed
+	receipts.receipts[1].CumulativeGasUsed = prev - 1
+	if err := receipts.DeriveFields(block); err == nil || !strings.Contains(err.Error(), "cumulative gas") {
+		t.Errorf("decreasing cumulative gas error mismatch: %v", err)
+	}
+	if used := receipts.receipts[1].GasUsed; used != cur-prev {
+		t.Errorf("gas used modified by failed derivation: have %d, want %d", used, cur-prev)
+	}
+	receipts.receipts[1].CumulativeGasUsed = cur
+
+	receipts.receipts = receipts.receipts[:2]
+	if err := receipts.DeriveFields(block); err == nil {
+		t.Error("expected error for receipt count mismatch")
+	}
+}
+
+func TestBloomMembership(t *testing.T) {
+	// The legacy receipt fixture holds a single ERC-20 Transfer log
+	receipt, err := NewReceiptFromBinary(hexutil.MustDecode(testReceiptFixtures[0].binary))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bloom := receipt.GetBloom()
+
+	token, _ := NewAddressFromHex("0x095e7baea6a6c7c4c2dfeb977efac326af552d87")
+	transfer, _ := NewHashFromHex("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+	if !bloom.TestAddress(token) {
+		t.Error("token address not in bloom")
+	}
+	if !bloom.TestTopic(transfer) {
+		t.Error("transfer topic not in bloom")
+	}
+	if !bloom.Test(token.GetBytes()) {
+		t.Error("raw token address not in bloom")
+	}
+	// With 3 bits set per entry out of 2048, a random address hits with a
+	// probability around 1e-7
+	hits := 0
+	for i := 0; i < 1000; i++ {
+		addr := &Address{common.BytesToAddress(crypto.Keccak256([]byte{byte(i >> 8), byte(i)}))}
+		if bloom.TestAddress(addr) {
+			hits++
+		}
+	}
+	if hits > 0 {
+		t.Errorf("unexpected bloom hits for random addresses: %d", hits)
+	}
+	if new(Bloom).TestTopic(transfer) {
+		t.Error("empty bloom reported a hit")
+	}
+}
+
+func TestBloomConstruction(t *testing.T) {
+	receipt, err := NewReceiptFromBinary(hexutil.MustDecode(testReceiptFixtures[0].binary))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyReceiptBloom(receipt); err != nil {
+		t.Fatalf("known-good receipt rejected: %v", err)
+	}
+	bloom := CreateBloomFromLogs(receipt.GetLogs())
+	if bloom.GetHex() != receipt.GetBloom().GetHex() {
+		t.Errorf("bloom mismatch: have %s, want %s", bloom.GetHex(), receipt.GetBloom().GetHex())
+	}
+	if empty := CreateBloomFromLogs(NewLogs()); empty.GetHex() != new(Bloom).GetHex() {
+		t.Errorf("empty logs bloom not empty: %s", empty.GetHex())
+	}
+	// Byte round-trip and length validation
+	dec, err := NewBloomFromBytes(bloom.GetBytes())
+	if err != nil || dec.GetHex() != bloom.GetHex() {
+		t.Errorf("byte round-trip mismatch: %v", err)
+	}
+	for _, n := range []int{0, 255, 257} {
+		if _, err := NewBloomFromBytes(make([]byte, n)); err == nil {
+			t.Errorf("expected error for %d byte bloom", n)
+		}
+	}
+	// Aggregation keeps both members and leaves the inputs untouched
+	extra := &types.Log{Address: testAddress.address}
+	other := CreateBloomFromLogs(&Logs{[]*types.Log{extra}})
+	before := bloom.GetHex()
+	union := bloom.Or(other)
+	if bloom.GetHex() != before {
+		t.Error("Or mutated its receiver")
+	}
+	if !union.TestAddress(testAddress) || !union.Test(receipt.receipt.Logs[0].Address[:]) {
+		t.Error("union lost a member")
+	}
+	if want := CreateBloomFromLogs(&Logs{append(receipt.receipt.Logs, extra)}); union.GetHex() != want.GetHex() {
+		t.Errorf("union mismatch: have %s, want %s", union.GetHex(), want.GetHex())
+	}
+	// Tampering with the logs must be detected
+	receipt.receipt.Logs[0].Topics[0][0] ^= 0xff
+	if err := VerifyReceiptBloom(receipt); err == nil {
+		t.Error("expected error for tampered receipt")
+	}
+}
+
+// testRPCDynamicFeeReceipt is a generated type-2 receipt in the format returned by
+// eth_getTransactionReceipt.
+const testRPCDynamicFeeReceipt = `{
+	"blockHash": "0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b",
+	"blockNumber": "0x64",
+	"contractAddress": null,
+	"cumulativeGasUsed": "0x1f0a3",
+	"effectiveGasPrice": "0x5d21dba3f",
+	"from": "0x71562b71999873db5b286df957af199ec94617f7",
+	"gasUsed": "0xcf9d",
+	"logs": [],
+	"logsBloom": "0x00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
+	"status": "0x1",
+	"to": "0x095e7baea6a6c7c4c2dfeb977efac326af552d87",
+	"transactionHash": "0x591e110f74454e49034ee6c460be97a0ce13d87f8e567b1594e2f35e6de60a07",
+	"transactionIndex": "0x3",
+	"type": "0x2"
+}`
+
+func TestReceiptFeePaid(t *testing.T) {
+	receipt, err := NewReceiptFromJSON(testRPCDynamicFeeReceipt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 25000000063 wei * 53149 gas
+	if have, want := receipt.GetEffectiveGasPrice().String(), "25000000063"; have != want {
+		t.Errorf("effective gas price mismatch: have %s, want %s", have, want)
+	}
+	if have, want := receipt.GetFeePaid().String(), "1328725003348387"; have != want {
+		t.Errorf("fee mismatch: have %s, want %s", have, want)
+	}
+	if have, want := receipt.GetFeePaidWithGasPrice(NewBigInt(1)).String(), "1328725003348387"; have != want {
+		t.Errorf("fallback price overrode the receipt: have %s, want %s", have, want)
+	}
+	// Returned values must not alias the receipt
+	receipt.GetEffectiveGasPrice().bigint.SetInt64(0)
+	if receipt.GetEffectiveGasPrice().String() != "25000000063" {
+		t.Error("effective gas price aliases the receipt")
+	}
+	// Receipts from consensus RLP lack the effective gas price
+	legacy, err := NewReceiptFromBinary(hexutil.MustDecode(testReceiptFixtures[0].binary))
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy.receipt.GasUsed = 21000
+	if legacy.GetEffectiveGasPrice() != nil || legacy.GetFeePaid() != nil {
+		t.Error("expected nil fee without effective gas price")
+	}
+	if have, want := legacy.GetFeePaidWithGasPrice(NewBigInt(20000000000)).String(), "420000000000000"; have != want {
+		t.Errorf("fallback fee mismatch: have %s, want %s", have, want)
+	}
+	if legacy.GetFeePaidWithGasPrice(nil) != nil {
+		t.Error("expected nil fee without any gas price")
+	}
+}
+
+func TestCheckedInt64Getters(t *testing.T) {
+	const maxUint64 = ^uint64(0)
+
+	header := NewEmptyHeader()
+	header.header.GasLimit, header.header.GasUsed, header.header.Time = maxUint64, 1<<63, 1<<63-1
+	if _, err := header.GetGasLimitChecked(); err == nil {
+		t.Error("expected overflow error for gas limit")
+	}
+	if _, err := header.GetGasUsedChecked(); err == nil {
+		t.Error("expected overflow error for gas used")
+	}
+	if v, err := header.GetTimeChecked(); err != nil || v != 1<<63-1 {
+		t.Errorf("time mismatch: have %d, err %v", v, err)
+	}
+	// The unchecked getters keep wrapping for compatibility
+	if header.GetGasLimit() != -1 {
+		t.Errorf("unchecked gas limit mismatch: have %d", header.GetGasLimit())
+	}
+	block := NewBlockWithHeader(header)
+	if _, err := block.GetGasLimitChecked(); err == nil {
+		t.Error("expected overflow error for block gas limit")
+	}
+	if _, err := block.GetGasUsedChecked(); err == nil {
+		t.Error("expected overflow error for block gas used")
+	}
+	if _, err := block.GetTimeChecked(); err != nil {
+		t.Errorf("block time: %v", err)
+	}
+	header.header.Number = new(big.Int).Lsh(big.NewInt(1), 80)
+	if _, err := header.GetNumber(); err == nil {
+		t.Error("expected overflow error for 2^80 block number")
+	}
+
+	tx := NewTransaction(int64(-1), testAddress, NewBigInt(0), -1, NewBigInt(1), nil)
+	if _, err := tx.GetNonceChecked(); err == nil {
+		t.Error("expected overflow error for nonce")
+	}
+	if _, err := tx.GetGasChecked(); err == nil {
+		t.Error("expected overflow error for gas")
+	}
+	receipt := &Receipt{&types.Receipt{GasUsed: maxUint64, CumulativeGasUsed: 21000}}
+	if _, err := receipt.GetGasUsedChecked(); err == nil {
+		t.Error("expected overflow error for receipt gas used")
+	}
+	if v, err := receipt.GetCumulativeGasUsedChecked(); err != nil || v != 21000 {
+		t.Errorf("cumulative gas used mismatch: have %d, err %v", v, err)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2019 The bcl-chain Authors. All rights reserved.
+
+// Contains conversions between integer amounts and decimal unit strings.
+
+package web3go
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const (
+	etherDecimals = 18 // Decimals of ether relative to wei
+	gweiDecimals  = 9  // Decimals of gwei relative to wei
+)
+
+// ParseUnits converts a decimal string such as "1.5" into an integer amount of
+// the smallest unit, given the number of decimals of the unit, e.g. 18 for ether
+// or 6 for USDC. More fractional digits than decimals are rejected rather than
+// rounded, as is anything but an optional sign, digits and a single point.
+func ParseUnits(s string, decimals int) (*BigInt, error) {
+	if decimals < 0 {
+		return nil, fmt.Errorf("invalid decimals %d", decimals)
+	}
+	digits, neg := s, false
+	if len(digits) > 0 && (digits[0] == '-' || digits[0] == '+') {
+		digits, neg = digits[1:], digits[0] == '-'
+	}
+	whole, frac := digits, ""
+	if i := strings.IndexByte(digits, '.'); i >= 0 {
+		whole, frac = digits[:i], digits[i+1:]
+		if frac == "" {
+			return nil, fmt.Errorf("invalid amount %q: missing fractional digits", s)
+		}
+	}
+	if whole == "" && frac == "" {
+		return nil, fmt.Errorf("invalid amount %q", s)
+	}
+	if strings.Trim(whole+frac, "0123456789") != "" {
+		return nil, fmt.Errorf("invalid amount %q", s)
+	}
+	if len(frac) > decimals {
+		return nil, fmt.Errorf("invalid amount %q: more than %d decimal places", s, decimals)
+	}
+	x, _ := new(big.Int).SetString("0"+whole+frac+strings.Repeat("0", decimals-len(frac)), 10)
+	if neg {
+		x.Neg(x)
+	}
+	return &BigInt{x}, nil
+}
+
+// FormatUnits converts an integer amount of the smallest unit into a decimal
+// string, given the number of decimals of the unit. At most precision fractional
+// digits are kept, truncating towards zero, and a negative precision keeps them
+// all. Trailing zeroes and a dangling point are trimmed, so 1500000000000000000
+// with 18 decimals formats as "1.5".
+func FormatUnits(v *BigInt, decimals int, precision int) string {
+	if decimals < 0 {
+		decimals = 0
+	}
+	digits := new(big.Int).Abs(v.bigint).String()
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+	whole, frac := digits[:len(digits)-decimals], digits[len(digits)-decimals:]
+	if precision >= 0 && precision < len(frac) {
+		frac = frac[:precision]
+	}
+	res := whole
+	if frac = strings.TrimRight(frac, "0"); frac != "" {
+		res += "." + frac
+	}
+	if v.bigint.Sign() < 0 && res != "0" {
+		res = "-" + res
+	}
+	return res
+}
+
+// ParseEther converts a decimal amount of ether, such as "1.5", into wei.
+func ParseEther(s string) (*BigInt, error) {
+	return ParseUnits(s, etherDecimals)
+}
+
+// FormatEther converts an amount of wei into a decimal amount of ether, keeping
+// all significant digits.
+func FormatEther(wei *BigInt) string {
+	return FormatUnits(wei, etherDecimals, -1)
+}
+
+// ParseGwei converts a decimal amount of gwei, such as "1.5", into wei.
+func ParseGwei(s string) (*BigInt, error) {
+	return ParseUnits(s, gweiDecimals)
+}
+
+// FormatGwei converts an amount of wei into a decimal amount of gwei, keeping
+// all significant digits.
+func FormatGwei(wei *BigInt) string {
+	return FormatUnits(wei, gweiDecimals, -1)
+}
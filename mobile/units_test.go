@@ -0,0 +1,97 @@
+package web3go
+
+import (
+	"testing"
+)
+
+func TestParseUnits(t *testing.T) {
+	tests := []struct {
+		input    string
+		decimals int
+		want     string
+	}{
+		{"1", 18, "1000000000000000000"},
+		{"1.5", 18, "1500000000000000000"},
+		{"0.000000000000000001", 18, "1"},
+		{".5", 18, "500000000000000000"},
+		{"-2.25", 18, "-2250000000000000000"},
+		{"+3", 6, "3000000"},
+		{"123456789012345678901234567890.123456789012345678", 18, "123456789012345678901234567890123456789012345678"},
+		{"1.100000", 6, "1100000"},
+		{"42", 0, "42"},
+	}
+	for _, tt := range tests {
+		v, err := ParseUnits(tt.input, tt.decimals)
+		if err != nil {
+			t.Errorf("%q: %v", tt.input, err)
+			continue
+		}
+		if v.String() != tt.want {
+			t.Errorf("%q: have %s, want %s", tt.input, v, tt.want)
+		}
+	}
+	invalid := []struct {
+		input    string
+		decimals int
+	}{
+		{"0.0000000000000000001", 18}, {"1.1234567", 6}, {"1.5", 0}, {"", 18}, {".", 18},
+		{"1.", 18}, {"-", 18}, {"1.2.3", 18}, {"1e18", 18}, {"0x10", 18}, {" 1", 18}, {"1,5", 18}, {"1", -1},
+	}
+	for _, tt := range invalid {
+		if v, err := ParseUnits(tt.input, tt.decimals); err == nil {
+			t.Errorf("%q with %d decimals: expected error, have %s", tt.input, tt.decimals, v)
+		}
+	}
+	if v, _ := ParseEther("0.5"); v.String() != "500000000000000000" {
+		t.Errorf("ether mismatch: have %s", v)
+	}
+	if v, _ := ParseGwei("1.5"); v.String() != "1500000000" {
+		t.Errorf("gwei mismatch: have %s", v)
+	}
+}
+
+func TestFormatUnits(t *testing.T) {
+	big := func(s string) *BigInt {
+		v, err := NewBigIntFromString(s, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	tests := []struct {
+		value     string
+		decimals  int
+		precision int
+		want      string
+	}{
+		{"1500000000000000000", 18, -1, "1.5"},
+		{"1000000000000000000", 18, -1, "1"},
+		{"1", 18, -1, "0.000000000000000001"},
+		{"0", 18, -1, "0"},
+		{"-2250000000000000000", 18, -1, "-2.25"},
+		{"123456789012345678901234567890123456789012345678", 18, -1, "123456789012345678901234567890.123456789012345678"},
+		{"1234567890000000000", 18, 4, "1.2345"},
+		{"1999999999999999999", 18, 2, "1.99"},
+		{"1000000000000000001", 18, 4, "1"},
+		{"-1", 18, 4, "0"},
+		{"1234", 0, -1, "1234"},
+		{"1100000", 6, 0, "1"},
+	}
+	for _, tt := range tests {
+		if have := FormatUnits(big(tt.value), tt.decimals, tt.precision); have != tt.want {
+			t.Errorf("%s with %d decimals, precision %d: have %s, want %s", tt.value, tt.decimals, tt.precision, have, tt.want)
+		}
+	}
+	if have := FormatEther(big("21000000000000")); have != "0.000021" {
+		t.Errorf("ether mismatch: have %s", have)
+	}
+	if have := FormatGwei(big("1500000000")); have != "1.5" {
+		t.Errorf("gwei mismatch: have %s", have)
+	}
+	// Parsing the formatted value yields the original amount
+	for _, s := range []string{"1", "-1", "987654321987654321987654321", "100000000000000000000"} {
+		if v, err := ParseEther(FormatEther(big(s))); err != nil || v.String() != s {
+			t.Errorf("%s: round-trip mismatch: have %v, err %v", s, v, err)
+		}
+	}
+}
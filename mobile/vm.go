@@ -19,8 +19,11 @@
 package web3go
 
 import (
+	"encoding/json"
 	"errors"
+	"sort"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
@@ -30,6 +33,25 @@ type Log struct {
 	log *types.Log
 }
 
+// NewLogFromJSON parses a log from its JSON encoding, as returned by eth_getLogs.
+func NewLogFromJSON(data string) (_ *Log, err error) {
+	defer recoverError(&err)
+	l := &Log{
+		log: new(types.Log),
+	}
+	if err := json.Unmarshal([]byte(data), l.log); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// EncodeJSON encodes a log into its JSON encoding.
+func (l *Log) EncodeJSON() (_ string, err error) {
+	defer recoverError(&err)
+	data, err := json.Marshal(l.log)
+	return string(data), err
+}
+
 // GetAddress ...
 func (l *Log) GetAddress() *Address { return &Address{l.log.Address} }
 
@@ -54,9 +76,45 @@ func (l *Log) GetBlockHash() *Hash { return &Hash{l.log.BlockHash} }
 // GetIndex ...
 func (l *Log) GetIndex() int { return int(l.log.Index) }
 
+// GetRemoved reports whether the log was reverted due to a chain reorganisation.
+// Logs delivered through a subscription or filter are flagged when their block
+// is dropped from the canonical chain.
+func (l *Log) GetRemoved() bool { return l.log.Removed }
+
 // Logs represents a slice of VM logs.
 type Logs struct{ logs []*types.Log }
 
+// NewLogs creates an empty slice of logs.
+func NewLogs() *Logs {
+	return &Logs{
+		logs: make([]*types.Log, 0),
+	}
+}
+
+// NewLogsFromJSON parses a slice of logs from a JSON array, such as the result
+// of eth_getLogs.
+func NewLogsFromJSON(data string) (_ *Logs, err error) {
+	defer recoverError(&err)
+	l := NewLogs()
+	if err := json.Unmarshal([]byte(data), &l.logs); err != nil {
+		return nil, err
+	}
+	if l.logs == nil {
+		l.logs = make([]*types.Log, 0)
+	}
+	return l, nil
+}
+
+// EncodeJSON encodes a slice of logs into a JSON array.
+func (l *Logs) EncodeJSON() (_ string, err error) {
+	defer recoverError(&err)
+	if l.logs == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(l.logs)
+	return string(data), err
+}
+
 // Size returns the number of logs in the slice.
 func (l *Logs) Size() int {
 	return len(l.logs)
@@ -69,3 +127,93 @@ func (l *Logs) Get(index int) (log *Log, _ error) {
 	}
 	return &Log{l.logs[index]}, nil
 }
+
+// filter returns the logs accepted by match as a new slice, leaving the original
+// untouched. The logs themselves are shared.
+func (l *Logs) filter(match func(log *types.Log) bool) *Logs {
+	res := NewLogs()
+	for _, log := range l.logs {
+		if match(log) {
+			res.logs = append(res.logs, log)
+		}
+	}
+	return res
+}
+
+// FilterByAddress returns the logs emitted by the given contract. A nil address
+// matches every log.
+func (l *Logs) FilterByAddress(addr *Address) *Logs {
+	return l.filter(func(log *types.Log) bool {
+		return addr == nil || log.Address == addr.address
+	})
+}
+
+// FilterByTopic returns the logs whose topic at the given position equals topic,
+// e.g. position 1 selects on the first indexed event argument. Logs with fewer
+// topics never match, while a nil topic matches any log having that position.
+func (l *Logs) FilterByTopic(position int, topic *Hash) *Logs {
+	return l.filter(func(log *types.Log) bool {
+		if position < 0 || position >= len(log.Topics) {
+			return false
+		}
+		return topic == nil || log.Topics[position] == topic.hash
+	})
+}
+
+// FilterByBlockRange returns the logs included in blocks from through to, both
+// inclusive.
+func (l *Logs) FilterByBlockRange(from, to int64) *Logs {
+	return l.filter(func(log *types.Log) bool {
+		number := int64(log.BlockNumber)
+		return number >= from && number <= to
+	})
+}
+
+// SortByPosition returns the logs ordered by their position in the chain, i.e.
+// by block number, transaction index and log index. The sort is stable and the
+// original slice is left untouched.
+func (l *Logs) SortByPosition() *Logs {
+	res := &Logs{append(make([]*types.Log, 0, len(l.logs)), l.logs...)}
+	sort.SliceStable(res.logs, func(i, j int) bool {
+		a, b := res.logs[i], res.logs[j]
+		if a.BlockNumber != b.BlockNumber {
+			return a.BlockNumber < b.BlockNumber
+		}
+		if a.TxIndex != b.TxIndex {
+			return a.TxIndex < b.TxIndex
+		}
+		return a.Index < b.Index
+	})
+	return res
+}
+
+// logKey identifies a log delivery. The removed flag is part of it, so that the
+// removal of a reorged log is kept next to its original delivery.
+type logKey struct {
+	block   common.Hash
+	index   uint
+	removed bool
+}
+
+// Dedup returns the logs without duplicates, keeping the first of the entries
+// with identical block hash, log index and removed flag. The original slice is
+// left untouched.
+func (l *Logs) Dedup() *Logs {
+	seen := make(map[logKey]struct{}, len(l.logs))
+	return l.filter(func(log *types.Log) bool {
+		key := logKey{log.BlockHash, log.Index, log.Removed}
+		if _, ok := seen[key]; ok {
+			return false
+		}
+		seen[key] = struct{}{}
+		return true
+	})
+}
+
+// MergeLogs returns the union of two sets of logs, e.g. overlapping results of
+// chunked queries, sorted by position and without duplicates. Neither input is
+// modified.
+func MergeLogs(a, b *Logs) *Logs {
+	merged := &Logs{append(append(make([]*types.Log, 0, len(a.logs)+len(b.logs)), a.logs...), b.logs...)}
+	return merged.SortByPosition().Dedup()
+}
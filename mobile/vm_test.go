@@ -0,0 +1,218 @@
+package web3go
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	mrand "math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// testLogJSON is an ERC-20 Transfer event in the format returned by eth_getLogs,
+// flagged as removed by a reorg.
+const testLogJSON = `{"address":"0xdac17f958d2ee523a2206206994597c13d831ec7","topics":["0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef","0x00000000000000000000000071660c4005ba85c37ccec55d0c4493e66fe775d3","0x000000000000000000000000a9d1e08c7793af67e9d92fe308d5697fb81d3e43"],"data":"0x00000000000000000000000000000000000000000000000000000000055d4a80","blockNumber":"0x10d4f1a","transactionHash":"0x2f5a8b6d3e1c7f9a0b4d6e8f1a3c5e7b9d0f2a4c6e8b1d3f5a7c9e0b2d4f6a8c","transactionIndex":"0x2b","blockHash":"0x8c1e3a5f7b9d2c4e6a8f0b1d3c5e7a9f2b4d6c8e0a1f3b5d7c9e2a4f6b8d0c1e","logIndex":"0x8f","removed":true}`
+
+func TestLogJSON(t *testing.T) {
+	log, err := NewLogFromJSON(testLogJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := log.GetAddress().GetHex(), "0xdAC17F958D2ee523a2206206994597C13D831ec7"; have != want {
+		t.Errorf("address mismatch: have %s, want %s", have, want)
+	}
+	topics := log.GetTopics()
+	if topics.Size() != 3 {
+		t.Fatalf("topic count mismatch: have %d, want 3", topics.Size())
+	}
+	if topic, _ := topics.Get(0); topic.GetHex() != "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef" {
+		t.Errorf("topic 0 mismatch: have %s", topic.GetHex())
+	}
+	if have := len(log.GetData()); have != 32 || log.GetData()[31] != 0x80 {
+		t.Errorf("data mismatch: have %x", log.GetData())
+	}
+	if have, want := log.GetBlockNumber(), int64(0x10d4f1a); have != want {
+		t.Errorf("block number mismatch: have %d, want %d", have, want)
+	}
+	if have, want := log.GetTxHash().GetHex(), "0x2f5a8b6d3e1c7f9a0b4d6e8f1a3c5e7b9d0f2a4c6e8b1d3f5a7c9e0b2d4f6a8c"; have != want {
+		t.Errorf("transaction hash mismatch: have %s, want %s", have, want)
+	}
+	if have, want := log.GetBlockHash().GetHex(), "0x8c1e3a5f7b9d2c4e6a8f0b1d3c5e7a9f2b4d6c8e0a1f3b5d7c9e2a4f6b8d0c1e"; have != want {
+		t.Errorf("block hash mismatch: have %s, want %s", have, want)
+	}
+	if log.GetTxIndex() != 0x2b || log.GetIndex() != 0x8f {
+		t.Errorf("index mismatch: have tx %d log %d, want 43 143", log.GetTxIndex(), log.GetIndex())
+	}
+	if !log.GetRemoved() {
+		t.Error("removed flag lost")
+	}
+	// Re-encoding must reproduce the original fields
+	data, err := log.EncodeJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var have, want map[string]interface{}
+	if err := json.Unmarshal([]byte(data), &have); err != nil {
+		t.Fatal(err)
+	}
+	json.Unmarshal([]byte(testLogJSON), &want)
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("JSON round-trip mismatch:\nhave %s\nwant %s", data, testLogJSON)
+	}
+	if _, err := NewLogFromJSON(`{"address":"0xdac17f958d2ee523a2206206994597c13d831ec7"}`); err == nil {
+		t.Error("expected error for log without topics")
+	}
+}
+
+func TestLogsFilter(t *testing.T) {
+	var (
+		token   = "0x000000000000000000000000000000000000aaaa"
+		other   = "0x000000000000000000000000000000000000bbbb"
+		event   = "0x1111111111111111111111111111111111111111111111111111111111111111"
+		alice   = "0x000000000000000000000000000000000000000000000000000000000000a11c"
+		bob     = "0x0000000000000000000000000000000000000000000000000000000000000b0b"
+		entries []string
+	)
+	add := func(address string, number int, topics ...string) {
+		entries = append(entries, fmt.Sprintf(`{"address":%q,"topics":["%s"],"data":"0x","blockNumber":"0x%x","transactionHash":"0x%064x","transactionIndex":"0x0","blockHash":"0x%064x","logIndex":"0x%x","removed":false}`,
+			address, strings.Join(topics, `","`), number, len(entries), number, len(entries)))
+	}
+	add(token, 10, event, alice, bob)
+	add(token, 11, event, bob, alice)
+	add(other, 12, event, alice, bob)
+	add(token, 13, event)
+
+	logs, err := NewLogsFromJSON("[" + strings.Join(entries, ",") + "]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	indices := func(logs *Logs) []int {
+		res := []int{}
+		for i := 0; i < logs.Size(); i++ {
+			log, _ := logs.Get(i)
+			res = append(res, log.GetIndex())
+		}
+		return res
+	}
+	tokenAddr, _ := NewAddressFromHex(token)
+	aliceHash, _ := NewHashFromHex(alice)
+	missing, _ := NewHashFromHex(event[:len(event)-1] + "2")
+
+	tests := []struct {
+		name string
+		logs *Logs
+		want []int
+	}{
+		{"address", logs.FilterByAddress(tokenAddr), []int{0, 1, 3}},
+		{"nil address", logs.FilterByAddress(nil), []int{0, 1, 2, 3}},
+		{"topic 1", logs.FilterByTopic(1, aliceHash), []int{0, 2}},
+		{"topic 2", logs.FilterByTopic(2, aliceHash), []int{1}},
+		{"nil topic", logs.FilterByTopic(1, nil), []int{0, 1, 2}},
+		{"topic out of range", logs.FilterByTopic(5, aliceHash), []int{}},
+		{"topic no match", logs.FilterByTopic(0, missing), []int{}},
+		{"block range", logs.FilterByBlockRange(11, 12), []int{1, 2}},
+		{"empty block range", logs.FilterByBlockRange(12, 11), []int{}},
+		{"chained", logs.FilterByAddress(tokenAddr).FilterByTopic(1, aliceHash), []int{0}},
+	}
+	for _, tt := range tests {
+		if tt.logs == nil {
+			t.Errorf("%s: nil result", tt.name)
+			continue
+		}
+		if have := indices(tt.logs); !reflect.DeepEqual(have, tt.want) {
+			t.Errorf("%s: have %v, want %v", tt.name, have, tt.want)
+		}
+	}
+	if logs.Size() != 4 {
+		t.Errorf("filtering mutated the original: size %d", logs.Size())
+	}
+	// JSON round-trip of the filtered and empty slices
+	data, err := logs.FilterByTopic(1, aliceHash).EncodeJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec, err := NewLogsFromJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have := indices(dec); !reflect.DeepEqual(have, []int{0, 2}) {
+		t.Errorf("JSON round-trip mismatch: have %v", have)
+	}
+	if data, _ := logs.FilterByBlockRange(100, 200).EncodeJSON(); data != "[]" {
+		t.Errorf("empty slice encoding mismatch: have %s, want []", data)
+	}
+	if empty, err := NewLogsFromJSON("null"); err != nil || empty.Size() != 0 {
+		t.Errorf("null decoding mismatch: %v", err)
+	}
+}
+
+func TestLogsMerge(t *testing.T) {
+	// A chain of 20 logs over 5 blocks, plus the removal of two of them
+	var all []*types.Log
+	for i := 0; i < 20; i++ {
+		number := uint64(i / 4)
+		all = append(all, &types.Log{
+			Topics:      []common.Hash{},
+			BlockNumber: number,
+			BlockHash:   common.BigToHash(new(big.Int).SetUint64(number + 1)),
+			TxIndex:     uint(i % 4 / 2),
+			Index:       uint(i),
+		})
+	}
+	for _, i := range []int{9, 13} {
+		removed := *all[i]
+		removed.Removed = true
+		all = append(all, &removed)
+	}
+	position := func(log *types.Log) [3]uint64 {
+		return [3]uint64{log.BlockNumber, uint64(log.TxIndex), uint64(log.Index)}
+	}
+	rand := mrand.New(mrand.NewSource(1))
+	for round := 0; round < 100; round++ {
+		// Split the logs into two overlapping, shuffled chunks
+		var a, b []*types.Log
+		for _, log := range all {
+			switch rand.Intn(3) {
+			case 0:
+				a = append(a, log)
+			case 1:
+				b = append(b, log)
+			default:
+				a, b = append(a, log), append(b, log)
+			}
+		}
+		rand.Shuffle(len(a), func(i, j int) { a[i], a[j] = a[j], a[i] })
+		rand.Shuffle(len(b), func(i, j int) { b[i], b[j] = b[j], b[i] })
+		before := append([]*types.Log(nil), a...)
+
+		merged := MergeLogs(&Logs{a}, &Logs{b})
+		if merged.Size() != len(all) {
+			t.Fatalf("round %d: merged size mismatch: have %d, want %d", round, merged.Size(), len(all))
+		}
+		for i := 1; i < merged.Size(); i++ {
+			prev, cur := position(merged.logs[i-1]), position(merged.logs[i])
+			if prev[0] > cur[0] || prev[0] == cur[0] && (prev[1] > cur[1] || prev[1] == cur[1] && prev[2] > cur[2]) {
+				t.Fatalf("round %d: logs %d and %d out of order: %v > %v", round, i-1, i, prev, cur)
+			}
+		}
+		if !reflect.DeepEqual(a, before) {
+			t.Fatalf("round %d: merge mutated its input", round)
+		}
+	}
+	// Removal entries survive deduplication next to their originals
+	dedup := (&Logs{append(append([]*types.Log(nil), all...), all...)}).Dedup()
+	if dedup.Size() != len(all) {
+		t.Errorf("dedup size mismatch: have %d, want %d", dedup.Size(), len(all))
+	}
+	sorted := dedup.SortByPosition()
+	if log := sorted.logs[10]; log.Index != 9 || !log.Removed || sorted.logs[9].Removed {
+		t.Errorf("removal not kept after its original: %+v", log)
+	}
+	if empty := MergeLogs(NewLogs(), NewLogs()); empty == nil || empty.Size() != 0 {
+		t.Error("merging empty logs should yield an empty slice")
+	}
+}